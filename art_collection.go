@@ -0,0 +1,278 @@
+package samsung
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kpeu3i/go-tizen-tv/tizenapi"
+)
+
+const artSlideshowOff = "off"
+
+type ArtPiece struct {
+	ID              string
+	CategoryID      string
+	Width           int
+	Height          int
+	MatteID         string
+	PortraitMatteID string
+	Date            string
+}
+
+type ArtMattes struct {
+	Types  []string
+	Colors []string
+}
+
+type ArtSlideshow struct {
+	Interval   time.Duration // Zero means the slideshow is off, set in whole minutes rounded up
+	CategoryID string
+	Shuffle    bool
+}
+
+type ArtCollection struct {
+	tv *TV
+}
+
+func (tv *TV) ArtCollection() *ArtCollection {
+	return &ArtCollection{tv: tv}
+}
+
+func (c *ArtCollection) List(category string) ([]ArtPiece, error) {
+	return c.ListContext(context.Background(), category)
+}
+
+func (c *ArtCollection) ListContext(ctx context.Context, category string) ([]ArtPiece, error) {
+	err := c.tv.ensureArtConnection(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	contents, err := c.tv.artClient.GetContentListContext(ctx, category)
+	if err != nil {
+		return nil, err
+	}
+
+	pieces := make([]ArtPiece, 0, len(contents))
+	for _, content := range contents {
+		pieces = append(pieces, buildArtPiece(content))
+	}
+
+	return pieces, nil
+}
+
+func (c *ArtCollection) Current() (ArtPiece, error) {
+	return c.CurrentContext(context.Background())
+}
+
+func (c *ArtCollection) CurrentContext(ctx context.Context) (ArtPiece, error) {
+	err := c.tv.ensureArtConnection(ctx)
+	if err != nil {
+		return ArtPiece{}, err
+	}
+
+	content, err := c.tv.artClient.GetCurrentArtworkContext(ctx)
+	if err != nil {
+		return ArtPiece{}, err
+	}
+
+	return buildArtPiece(content), nil
+}
+
+// Upload stores a local JPEG or PNG file on the TV and returns the ID of the
+// new piece. An empty matte ID uploads the piece without a matte.
+func (c *ArtCollection) Upload(path string, matteID string) (string, error) {
+	return c.UploadContext(context.Background(), path, matteID)
+}
+
+func (c *ArtCollection) UploadContext(ctx context.Context, path string, matteID string) (string, error) {
+	fileType, err := artFileType(path)
+	if err != nil {
+		return "", err
+	}
+
+	image, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	return c.UploadImageContext(ctx, image, fileType, matteID)
+}
+
+func (c *ArtCollection) UploadImage(image []byte, fileType string, matteID string) (string, error) {
+	return c.UploadImageContext(context.Background(), image, fileType, matteID)
+}
+
+func (c *ArtCollection) UploadImageContext(
+	ctx context.Context,
+	image []byte,
+	fileType string,
+	matteID string,
+) (string, error) {
+	err := c.tv.ensureArtConnection(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return c.tv.artClient.UploadImageContext(ctx, image, fileType, matteID)
+}
+
+func (c *ArtCollection) Delete(ids ...string) error {
+	return c.DeleteContext(context.Background(), ids...)
+}
+
+func (c *ArtCollection) DeleteContext(ctx context.Context, ids ...string) error {
+	err := c.tv.ensureArtConnection(ctx)
+	if err != nil {
+		return err
+	}
+
+	return c.tv.artClient.DeleteImagesContext(ctx, ids...)
+}
+
+func (c *ArtCollection) Select(id string) error {
+	return c.SelectContext(context.Background(), id)
+}
+
+func (c *ArtCollection) SelectContext(ctx context.Context, id string) error {
+	err := c.tv.ensureArtConnection(ctx)
+	if err != nil {
+		return err
+	}
+
+	return c.tv.artClient.SelectImageContext(ctx, id, true)
+}
+
+func (c *ArtCollection) Mattes() (ArtMattes, error) {
+	return c.MattesContext(context.Background())
+}
+
+func (c *ArtCollection) MattesContext(ctx context.Context) (ArtMattes, error) {
+	err := c.tv.ensureArtConnection(ctx)
+	if err != nil {
+		return ArtMattes{}, err
+	}
+
+	matteList, err := c.tv.artClient.GetMatteListContext(ctx)
+	if err != nil {
+		return ArtMattes{}, err
+	}
+
+	return ArtMattes{Types: matteList.Types, Colors: matteList.Colors}, nil
+}
+
+// SetMatte changes the matte of a piece. Matte IDs combine a type and a color,
+// e.g. "shadowbox_polar", or are "none".
+func (c *ArtCollection) SetMatte(id string, matteID string) error {
+	return c.SetMatteContext(context.Background(), id, matteID)
+}
+
+func (c *ArtCollection) SetMatteContext(ctx context.Context, id string, matteID string) error {
+	err := c.tv.ensureArtConnection(ctx)
+	if err != nil {
+		return err
+	}
+
+	return c.tv.artClient.ChangeMatteContext(ctx, id, matteID)
+}
+
+func (c *ArtCollection) SetFavorite(id string, favorite bool) error {
+	return c.SetFavoriteContext(context.Background(), id, favorite)
+}
+
+func (c *ArtCollection) SetFavoriteContext(ctx context.Context, id string, favorite bool) error {
+	err := c.tv.ensureArtConnection(ctx)
+	if err != nil {
+		return err
+	}
+
+	return c.tv.artClient.ChangeFavoriteContext(ctx, id, favorite)
+}
+
+func (c *ArtCollection) Slideshow() (ArtSlideshow, error) {
+	return c.SlideshowContext(context.Background())
+}
+
+func (c *ArtCollection) SlideshowContext(ctx context.Context) (ArtSlideshow, error) {
+	err := c.tv.ensureArtConnection(ctx)
+	if err != nil {
+		return ArtSlideshow{}, err
+	}
+
+	status, err := c.tv.artClient.GetSlideshowStatusContext(ctx)
+	if err != nil {
+		return ArtSlideshow{}, err
+	}
+
+	slideshow := ArtSlideshow{
+		CategoryID: status.CategoryID,
+		Shuffle:    strings.HasPrefix(status.Type, "shuffle"),
+	}
+
+	if status.Value != artSlideshowOff {
+		minutes, err := strconv.Atoi(status.Value)
+		if err != nil {
+			return ArtSlideshow{}, fmt.Errorf("invalid slideshow interval: %s", status.Value)
+		}
+
+		slideshow.Interval = time.Duration(minutes) * time.Minute
+	}
+
+	return slideshow, nil
+}
+
+func (c *ArtCollection) SetSlideshow(slideshow ArtSlideshow) error {
+	return c.SetSlideshowContext(context.Background(), slideshow)
+}
+
+func (c *ArtCollection) SetSlideshowContext(ctx context.Context, slideshow ArtSlideshow) error {
+	err := c.tv.ensureArtConnection(ctx)
+	if err != nil {
+		return err
+	}
+
+	value := artSlideshowOff
+	if slideshow.Interval > 0 {
+		// The TV takes whole minutes and reads zero as off, so round up.
+		minutes := (slideshow.Interval + time.Minute - 1) / time.Minute
+		value = strconv.Itoa(int(minutes))
+	}
+
+	categoryID := slideshow.CategoryID
+	if categoryID == "" {
+		categoryID = tizenapi.ArtCategoryMyPhotos
+	}
+
+	return c.tv.artClient.SetSlideshowStatusContext(ctx, value, categoryID, slideshow.Shuffle)
+}
+
+func buildArtPiece(content tizenapi.ArtContent) ArtPiece {
+	width, _ := content.Width.Int64()
+	height, _ := content.Height.Int64()
+
+	return ArtPiece{
+		ID:              content.ContentID,
+		CategoryID:      content.CategoryID,
+		Width:           int(width),
+		Height:          int(height),
+		MatteID:         content.MatteID,
+		PortraitMatteID: content.PortraitMatteID,
+		Date:            content.ImageDate,
+	}
+}
+
+func artFileType(path string) (string, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".jpg", ".jpeg":
+		return "jpg", nil
+	case ".png":
+		return "png", nil
+	default:
+		return "", fmt.Errorf("unsupported art file type: %s", path)
+	}
+}
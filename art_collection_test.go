@@ -0,0 +1,108 @@
+package samsung_test
+
+import (
+	"testing"
+	"time"
+
+	samsung "github.com/kpeu3i/go-tizen-tv"
+	"github.com/kpeu3i/go-tizen-tv/tizenapi"
+	"github.com/kpeu3i/go-tizen-tv/tizentest"
+)
+
+func newArtTV(t *testing.T) (*tizentest.ArtServer, *samsung.TV, func()) {
+	t.Helper()
+
+	server := tizentest.NewArtServer()
+
+	err := server.Start()
+	if err != nil {
+		t.Fatalf("start art server: %v", err)
+	}
+
+	artClient := tizenapi.NewArtAPIClient(
+		server.Host(),
+		"tizentest",
+		tizenapi.WithWebsocketPort(server.Port()),
+		tizenapi.WithWebsocketReconnect(false),
+		tizenapi.WithWebsocketReadTimeout(2*time.Second),
+	)
+
+	tv := samsung.NewTV(
+		tizenapi.NewUDPAPIClient(""),
+		tizenapi.NewHTTPAPIClient(server.Host()),
+		tizenapi.NewWebsocketAPIClient(server.Host(), "tizentest"),
+		server.Token,
+		samsung.WithArtAPIClient(artClient),
+	)
+
+	stop := func() {
+		_ = artClient.Close()
+		_ = server.Close()
+	}
+
+	return server, tv, stop
+}
+
+func TestArtCollectionChanges(t *testing.T) {
+	server, tv, stop := newArtTV(t)
+	defer stop()
+
+	server.AddContent(tizenapi.ArtContent{ContentID: "MY_F0001", CategoryID: tizenapi.ArtCategoryMyPhotos}, nil)
+
+	collection := tv.ArtCollection()
+
+	err := collection.Select("MY_F0001")
+	if err != nil || server.Current() != "MY_F0001" {
+		t.Errorf("Select() = %v, current %q", err, server.Current())
+	}
+
+	err = collection.SetMatte("MY_F0001", "shadowbox_polar")
+	if err != nil || server.Contents()[0].MatteID != "shadowbox_polar" {
+		t.Errorf("SetMatte() = %v, matte %q", err, server.Contents()[0].MatteID)
+	}
+
+	err = collection.SetFavorite("MY_F0001", true)
+	if err != nil || !server.IsFavorite("MY_F0001") {
+		t.Errorf("SetFavorite() = %v, favorite %v", err, server.IsFavorite("MY_F0001"))
+	}
+
+	// The TV rejects changes of pieces it does not have.
+	err = collection.Select("MY_F0404")
+	if err == nil {
+		t.Error("Select() of an unknown piece succeeded")
+	}
+}
+
+func TestArtCollectionSetSlideshow(t *testing.T) {
+	tests := []struct {
+		name     string
+		interval time.Duration
+		want     string
+	}{
+		{name: "off", interval: 0, want: "off"},
+		{name: "sub-minute", interval: 30 * time.Second, want: "1"},
+		{name: "whole minutes", interval: 15 * time.Minute, want: "15"},
+		{name: "partial minute", interval: 90 * time.Second, want: "2"},
+	}
+
+	server, tv, stop := newArtTV(t)
+	defer stop()
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := tv.ArtCollection().SetSlideshow(samsung.ArtSlideshow{Interval: test.interval, Shuffle: true})
+			if err != nil {
+				t.Fatalf("SetSlideshow() = %v", err)
+			}
+
+			status := server.Slideshow()
+			if status.Value != test.want {
+				t.Errorf("slideshow value = %q, want %q", status.Value, test.want)
+			}
+
+			if status.CategoryID != tizenapi.ArtCategoryMyPhotos || status.Type != "shuffleslideshow" {
+				t.Errorf("slideshow = %+v", status)
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+// Package clock abstracts time so that timing-dependent behaviour can be
+// driven by a fake clock in tests.
+package clock
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type Clock interface {
+	Now() time.Time
+	Since(t time.Time) time.Duration
+	After(d time.Duration) <-chan time.Time
+	Sleep(d time.Duration)
+	NewTimer(d time.Duration) Timer
+	NewTicker(d time.Duration) Ticker
+}
+
+type Timer interface {
+	C() <-chan time.Time
+	Stop() bool
+	Reset(d time.Duration) bool
+}
+
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+}
+
+// New returns the clock of the system.
+func New() Clock {
+	return realClock{}
+}
+
+// SleepContext waits for the duration on the clock or until the context is
+// done.
+func SleepContext(ctx context.Context, clock Clock, d time.Duration) error {
+	timer := clock.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C():
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// WithTimeout is context.WithTimeout measured on the clock. The context fails
+// with context.DeadlineExceeded when the duration passes on the clock.
+func WithTimeout(parent context.Context, clock Clock, d time.Duration) (context.Context, context.CancelFunc) {
+	if _, ok := clock.(realClock); ok {
+		return context.WithTimeout(parent, d)
+	}
+
+	ctx, cancel := context.WithCancel(parent)
+	timeoutCtx := &timeoutContext{Context: ctx, deadline: clock.Now().Add(d)}
+	timer := clock.NewTimer(d)
+
+	go func() {
+		select {
+		case <-timer.C():
+			timeoutCtx.mu.Lock()
+			timeoutCtx.err = context.DeadlineExceeded
+			timeoutCtx.mu.Unlock()
+
+			cancel()
+		case <-ctx.Done():
+			timer.Stop()
+		}
+	}()
+
+	return timeoutCtx, cancel
+}
+
+// Deadline returns the time when the context fails, measured on the clock
+// passed to WithTimeout.
+func Deadline(ctx context.Context) (time.Time, bool) {
+	if deadline, ok := ctx.Value(deadlineKey{}).(time.Time); ok {
+		return deadline, true
+	}
+
+	return ctx.Deadline()
+}
+
+type timeoutContext struct {
+	context.Context
+	deadline time.Time
+	mu       sync.Mutex
+	err      error
+}
+
+type deadlineKey struct{}
+
+// Value gives the deadline on the clock to Deadline. The deadline is not
+// reported by the Deadline method, which network code reads as the time of
+// the system.
+func (c *timeoutContext) Value(key interface{}) interface{} {
+	if key == (deadlineKey{}) {
+		return c.deadline
+	}
+
+	return c.Context.Value(key)
+}
+
+func (c *timeoutContext) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.err != nil {
+		return c.err
+	}
+
+	return c.Context.Err()
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) Since(t time.Time) time.Duration {
+	return time.Since(t)
+}
+
+func (realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+func (realClock) Sleep(d time.Duration) {
+	time.Sleep(d)
+}
+
+func (realClock) NewTimer(d time.Duration) Timer {
+	return realTimer{time.NewTimer(d)}
+}
+
+func (realClock) NewTicker(d time.Duration) Ticker {
+	return realTicker{time.NewTicker(d)}
+}
+
+type realTimer struct {
+	*time.Timer
+}
+
+func (t realTimer) C() <-chan time.Time {
+	return t.Timer.C
+}
+
+type realTicker struct {
+	*time.Ticker
+}
+
+func (t realTicker) C() <-chan time.Time {
+	return t.Ticker.C
+}
@@ -0,0 +1,198 @@
+package clock
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Fake is a clock which only moves when told to. Timers, tickers and sleeps
+// fire when Advance moves the time past their deadline.
+type Fake struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	now     time.Time
+	waiters []*fakeWaiter
+}
+
+type fakeWaiter struct {
+	deadline time.Time
+	period   time.Duration
+	c        chan time.Time
+}
+
+// NewFake returns a fake clock set to the time.
+func NewFake(now time.Time) *Fake {
+	fake := &Fake{now: now}
+	fake.cond = sync.NewCond(&fake.mu)
+
+	return fake
+}
+
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.now
+}
+
+func (f *Fake) Since(t time.Time) time.Duration {
+	return f.Now().Sub(t)
+}
+
+func (f *Fake) After(d time.Duration) <-chan time.Time {
+	return f.NewTimer(d).C()
+}
+
+func (f *Fake) Sleep(d time.Duration) {
+	<-f.After(d)
+}
+
+func (f *Fake) NewTimer(d time.Duration) Timer {
+	timer := &fakeTimer{fake: f, waiter: &fakeWaiter{c: make(chan time.Time, 1)}}
+	timer.Reset(d)
+
+	return timer
+}
+
+func (f *Fake) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("clock: non-positive interval for NewTicker")
+	}
+
+	waiter := &fakeWaiter{period: d, c: make(chan time.Time, 1)}
+
+	f.mu.Lock()
+	waiter.deadline = f.now.Add(d)
+	f.add(waiter)
+	f.mu.Unlock()
+
+	return &fakeTicker{fake: f, waiter: waiter}
+}
+
+// Advance moves the time forward and fires everything whose deadline passed,
+// in the order of the deadlines.
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	end := f.now.Add(d)
+
+	for {
+		sort.Slice(f.waiters, func(i, j int) bool {
+			return f.waiters[i].deadline.Before(f.waiters[j].deadline)
+		})
+
+		if len(f.waiters) == 0 || f.waiters[0].deadline.After(end) {
+			break
+		}
+
+		waiter := f.waiters[0]
+		if waiter.deadline.After(f.now) {
+			f.now = waiter.deadline
+		}
+
+		select {
+		case waiter.c <- f.now:
+		default:
+		}
+
+		if waiter.period > 0 {
+			waiter.deadline = waiter.deadline.Add(waiter.period)
+		} else {
+			f.remove(waiter)
+		}
+	}
+
+	f.now = end
+}
+
+// Waiters returns the number of pending timers, tickers and sleeps.
+func (f *Fake) Waiters() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return len(f.waiters)
+}
+
+// BlockUntil waits until at least n timers, tickers or sleeps are pending, so
+// that a test can advance the clock once the code under test is waiting.
+func (f *Fake) BlockUntil(n int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for len(f.waiters) < n {
+		f.cond.Wait()
+	}
+}
+
+func (f *Fake) add(waiter *fakeWaiter) {
+	f.waiters = append(f.waiters, waiter)
+	f.cond.Broadcast()
+}
+
+func (f *Fake) remove(waiter *fakeWaiter) bool {
+	for i, w := range f.waiters {
+		if w == waiter {
+			f.waiters = append(f.waiters[:i], f.waiters[i+1:]...)
+			f.cond.Broadcast()
+
+			return true
+		}
+	}
+
+	return false
+}
+
+type fakeTimer struct {
+	fake   *Fake
+	waiter *fakeWaiter
+}
+
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.waiter.c
+}
+
+func (t *fakeTimer) Stop() bool {
+	t.fake.mu.Lock()
+	defer t.fake.mu.Unlock()
+
+	return t.fake.remove(t.waiter)
+}
+
+func (t *fakeTimer) Reset(d time.Duration) bool {
+	t.fake.mu.Lock()
+	defer t.fake.mu.Unlock()
+
+	active := t.fake.remove(t.waiter)
+	t.waiter.deadline = t.fake.now.Add(d)
+
+	if d <= 0 {
+		select {
+		case t.waiter.c <- t.fake.now:
+		default:
+		}
+
+		return active
+	}
+
+	t.fake.add(t.waiter)
+
+	return active
+}
+
+type fakeTicker struct {
+	fake   *Fake
+	waiter *fakeWaiter
+}
+
+func (t *fakeTicker) C() <-chan time.Time {
+	return t.waiter.c
+}
+
+func (t *fakeTicker) Stop() {
+	t.fake.mu.Lock()
+	defer t.fake.mu.Unlock()
+
+	t.fake.remove(t.waiter)
+}
@@ -0,0 +1,246 @@
+package clock
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+var testStart = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+type testKey struct{}
+
+// received returns the time sent on the channel, or false when nothing was.
+func received(c <-chan time.Time) (time.Time, bool) {
+	select {
+	case t := <-c:
+		return t, true
+	default:
+		return time.Time{}, false
+	}
+}
+
+func TestFakeAdvanceFiresInDeadlineOrder(t *testing.T) {
+	fake := NewFake(testStart)
+
+	late := fake.NewTimer(3 * time.Second)
+	early := fake.NewTimer(time.Second)
+	middle := fake.After(2 * time.Second)
+	pending := fake.NewTimer(10 * time.Second)
+
+	fake.Advance(5 * time.Second)
+
+	// Each one fires with the time of its own deadline.
+	tests := []struct {
+		name string
+		c    <-chan time.Time
+		want time.Time
+	}{
+		{name: "early", c: early.C(), want: testStart.Add(time.Second)},
+		{name: "middle", c: middle, want: testStart.Add(2 * time.Second)},
+		{name: "late", c: late.C(), want: testStart.Add(3 * time.Second)},
+	}
+
+	for _, test := range tests {
+		got, ok := received(test.c)
+		if !ok || !got.Equal(test.want) {
+			t.Errorf("%s fired at %v (%t), want %v", test.name, got, ok, test.want)
+		}
+	}
+
+	if _, ok := received(pending.C()); ok {
+		t.Errorf("timer fired before its deadline")
+	}
+
+	if now := fake.Now(); !now.Equal(testStart.Add(5 * time.Second)) {
+		t.Errorf("Now() = %v, want %v", now, testStart.Add(5*time.Second))
+	}
+
+	if fake.Waiters() != 1 {
+		t.Errorf("Waiters() = %d, want 1", fake.Waiters())
+	}
+}
+
+func TestFakeTickerReschedules(t *testing.T) {
+	fake := NewFake(testStart)
+
+	ticker := fake.NewTicker(2 * time.Second)
+
+	for i := 1; i <= 3; i++ {
+		fake.Advance(time.Second)
+
+		if _, ok := received(ticker.C()); ok {
+			t.Fatalf("tick %d came a second early", i)
+		}
+
+		fake.Advance(time.Second)
+
+		got, ok := received(ticker.C())
+		want := testStart.Add(time.Duration(2*i) * time.Second)
+		if !ok || !got.Equal(want) {
+			t.Fatalf("tick %d = %v (%t), want %v", i, got, ok, want)
+		}
+	}
+
+	// Ticks are dropped while the previous one is not received.
+	fake.Advance(6 * time.Second)
+
+	got, ok := received(ticker.C())
+	if !ok || !got.Equal(testStart.Add(8*time.Second)) {
+		t.Errorf("tick = %v (%t), want %v", got, ok, testStart.Add(8*time.Second))
+	}
+
+	if _, ok := received(ticker.C()); ok {
+		t.Errorf("more than one tick buffered")
+	}
+
+	ticker.Stop()
+
+	if fake.Waiters() != 0 {
+		t.Errorf("Waiters() = %d after Stop(), want 0", fake.Waiters())
+	}
+}
+
+func TestFakeTimerReset(t *testing.T) {
+	tests := []struct {
+		name       string
+		d          time.Duration
+		wantFired  bool
+		wantActive bool
+	}{
+		{name: "positive", d: time.Second, wantActive: true},
+		{name: "zero", d: 0, wantFired: true},
+		{name: "negative", d: -time.Second, wantFired: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fake := NewFake(testStart)
+
+			timer := fake.NewTimer(time.Minute)
+
+			if !timer.Reset(test.d) {
+				t.Errorf("Reset() = false for a pending timer")
+			}
+
+			got, fired := received(timer.C())
+			if fired != test.wantFired || (fired && !got.Equal(testStart)) {
+				t.Errorf("fired = %v (%t), want %t at %v", got, fired, test.wantFired, testStart)
+			}
+
+			if active := fake.Waiters() == 1; active != test.wantActive {
+				t.Errorf("Waiters() = %d, want the timer pending: %t", fake.Waiters(), test.wantActive)
+			}
+
+			// The old deadline is gone.
+			fake.Advance(time.Minute)
+			_, _ = received(timer.C())
+			fake.Advance(time.Minute)
+
+			if _, ok := received(timer.C()); ok {
+				t.Errorf("timer fired at its old deadline")
+			}
+
+			if timer.Reset(time.Second) {
+				t.Errorf("Reset() = true for a fired timer")
+			}
+
+			if !timer.Stop() {
+				t.Errorf("Stop() = false for a pending timer")
+			}
+		})
+	}
+}
+
+func TestFakeBlockUntil(t *testing.T) {
+	fake := NewFake(testStart)
+
+	done := make(chan struct{})
+	go func() {
+		fake.Sleep(time.Second)
+		close(done)
+	}()
+
+	blocked := make(chan struct{})
+	go func() {
+		fake.BlockUntil(1)
+		close(blocked)
+	}()
+
+	select {
+	case <-blocked:
+	case <-time.After(2 * time.Second):
+		t.Fatal("BlockUntil() has not returned while Sleep() waits")
+	}
+
+	fake.Advance(time.Second)
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Sleep() has not returned after Advance()")
+	}
+}
+
+func TestWithTimeoutOnFake(t *testing.T) {
+	fake := NewFake(testStart)
+
+	ctx, cancel := WithTimeout(context.WithValue(context.Background(), testKey{}, "value"), fake, 5*time.Second)
+	defer cancel()
+
+	deadline, ok := Deadline(ctx)
+	if !ok || !deadline.Equal(testStart.Add(5*time.Second)) {
+		t.Errorf("Deadline() = %v (%t), want %v", deadline, ok, testStart.Add(5*time.Second))
+	}
+
+	// Network code reads the deadline as the time of the system.
+	if _, ok := ctx.Deadline(); ok {
+		t.Errorf("ctx.Deadline() reports the deadline of the fake clock")
+	}
+
+	if ctx.Value(testKey{}) != "value" {
+		t.Errorf("Value() = %v, want the value of the parent", ctx.Value(testKey{}))
+	}
+
+	fake.BlockUntil(1)
+	fake.Advance(4 * time.Second)
+
+	if ctx.Err() != nil {
+		t.Fatalf("Err() = %v before the deadline", ctx.Err())
+	}
+
+	fake.Advance(time.Second)
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(2 * time.Second):
+		t.Fatal("context is not done after the deadline")
+	}
+
+	if ctx.Err() != context.DeadlineExceeded {
+		t.Errorf("Err() = %v, want %v", ctx.Err(), context.DeadlineExceeded)
+	}
+}
+
+func TestWithTimeoutCanceled(t *testing.T) {
+	fake := NewFake(testStart)
+
+	ctx, cancel := WithTimeout(context.Background(), fake, 5*time.Second)
+	cancel()
+
+	<-ctx.Done()
+
+	if ctx.Err() != context.Canceled {
+		t.Errorf("Err() = %v, want %v", ctx.Err(), context.Canceled)
+	}
+
+	// The timer is stopped with the context.
+	deadline := time.Now().Add(2 * time.Second)
+	for fake.Waiters() != 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	if fake.Waiters() != 0 {
+		t.Errorf("Waiters() = %d after cancel, want 0", fake.Waiters())
+	}
+}
@@ -0,0 +1,605 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	samsung "github.com/kpeu3i/go-tizen-tv"
+)
+
+type deviceOutput struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Host     string `json:"host"`
+	MAC      string `json:"mac"`
+	Protocol string `json:"protocol"`
+	Paired   bool   `json:"paired"`
+}
+
+type infoOutput struct {
+	ID        string            `json:"id"`
+	Name      string            `json:"name"`
+	Type      string            `json:"type"`
+	Version   string            `json:"version"`
+	URI       string            `json:"uri"`
+	Remote    string            `json:"remote"`
+	Device    map[string]string `json:"device"`
+	IsSupport map[string]string `json:"is_support"`
+}
+
+type powerOutput struct {
+	State samsung.PowerState `json:"state"`
+}
+
+type appOutput struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Running bool   `json:"running"`
+	Visible bool   `json:"visible"`
+	Version string `json:"version"`
+}
+
+type eventOutput struct {
+	Time  time.Time   `json:"time"`
+	Event string      `json:"event"`
+	Data  interface{} `json:"data,omitempty"`
+}
+
+var deviceHeader = []string{"ID", "NAME", "HOST", "MAC", "PROTOCOL", "PAIRED"}
+
+func (c *cli) discover(ctx context.Context, args []string) error {
+	if len(args) != 0 {
+		return usageError("discover takes no arguments")
+	}
+
+	tvs, err := c.manager.DiscoverContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	ids := map[string]bool{}
+	for _, tv := range tvs {
+		info, err := tv.InfoContext(ctx)
+		if err != nil {
+			return err
+		}
+
+		ids[info.Device.ID()] = true
+	}
+
+	err = c.manager.StoreContext(ctx, tvs...)
+	if err != nil {
+		return err
+	}
+
+	config, err := c.storage.Load()
+	if err != nil {
+		return err
+	}
+
+	var devices []samsung.DeviceConfig
+	for _, deviceConfig := range config.Devices {
+		if ids[deviceConfig.ID] {
+			devices = append(devices, deviceConfig)
+		}
+	}
+
+	return c.writeDevices(devices)
+}
+
+func (c *cli) list(args []string) error {
+	if len(args) != 0 {
+		return usageError("list takes no arguments")
+	}
+
+	config, err := c.storage.Load()
+	if err != nil {
+		return err
+	}
+
+	return c.writeDevices(config.Devices)
+}
+
+func (c *cli) writeDevices(devices []samsung.DeviceConfig) error {
+	values := make([]deviceOutput, 0, len(devices))
+	rows := make([][]string, 0, len(devices))
+	for _, deviceConfig := range devices {
+		value := deviceOutput{
+			ID:       deviceConfig.ID,
+			Name:     deviceConfig.Name,
+			Host:     deviceConfig.Host,
+			MAC:      deviceConfig.MAC,
+			Protocol: valueOr(deviceConfig.Protocol, "tizen"),
+			Paired:   isPaired(deviceConfig),
+		}
+
+		values = append(values, value)
+		rows = append(rows, []string{
+			value.ID,
+			value.Name,
+			value.Host,
+			value.MAC,
+			value.Protocol,
+			yesNo(value.Paired),
+		})
+	}
+
+	return c.out.write(values, deviceHeader, rows)
+}
+
+// pair connects to the TV so that it asks for the client to be allowed, or
+// for a PIN on TVs using the encrypted protocol. The token or session is
+// stored in the config by the manager.
+func (c *cli) pair(ctx context.Context, args []string) error {
+	if len(args) != 0 {
+		return usageError("pair takes no arguments")
+	}
+
+	tv, err := c.readyTV(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = tv.Close()
+	}()
+
+	err = tv.OnPINRequest(func(ctx context.Context) (string, error) {
+		_, _ = fmt.Fprint(c.stderr, "Enter the PIN shown on the TV: ")
+
+		pin, err := bufio.NewReader(c.stdin).ReadString('\n')
+		if err != nil && pin == "" {
+			return "", err
+		}
+
+		return strings.TrimSpace(pin), nil
+	})
+	if err != nil && err != samsung.ErrPairingNotSupported {
+		return err
+	}
+
+	_, _ = fmt.Fprintln(c.stderr, "Allow the connection on the TV if it asks for it")
+
+	err = tv.ConnectContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	deviceConfig, err := c.device()
+	if err != nil {
+		return err
+	}
+
+	return c.writeDevices([]samsung.DeviceConfig{deviceConfig})
+}
+
+func (c *cli) info(ctx context.Context, args []string) error {
+	if len(args) != 0 {
+		return usageError("info takes no arguments")
+	}
+
+	tv, err := c.readyTV(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = tv.Close()
+	}()
+
+	info, err := tv.InfoContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	value := infoOutput{
+		ID:        info.ID,
+		Name:      info.Name,
+		Type:      info.Type,
+		Version:   info.Version,
+		URI:       info.URI,
+		Remote:    info.Remote,
+		Device:    info.Device,
+		IsSupport: info.IsSupport,
+	}
+
+	rows := [][]string{
+		{"id", info.ID},
+		{"name", info.Name},
+		{"type", info.Type},
+		{"version", info.Version},
+		{"uri", info.URI},
+		{"remote", info.Remote},
+	}
+	rows = append(rows, sortedRows("device.", info.Device)...)
+	rows = append(rows, sortedRows("is_support.", info.IsSupport)...)
+
+	return c.out.write(value, []string{"KEY", "VALUE"}, rows)
+}
+
+func (c *cli) power(ctx context.Context, args []string) error {
+	if len(args) != 1 {
+		return usageError("usage: power on|off|status")
+	}
+
+	tv, err := c.tv()
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = tv.Close()
+	}()
+
+	switch args[0] {
+	case "on":
+		return tv.PowerOnContext(ctx)
+	case "off":
+		return tv.PowerOffContext(ctx)
+	case "status":
+		state, err := tv.PowerStateContext(ctx)
+		if err != nil {
+			return err
+		}
+
+		err = c.out.write(powerOutput{State: state}, nil, [][]string{{string(state)}})
+		if err != nil {
+			return err
+		}
+
+		// The state is also reported by the exit code, without a message.
+		switch state {
+		case samsung.PowerStateOff:
+			return withExitCode(exitUnavailable, nil)
+		case samsung.PowerStateStandby:
+			return withExitCode(exitStandby, nil)
+		}
+
+		return nil
+	default:
+		return usageError("unknown power action %q", args[0])
+	}
+}
+
+func (c *cli) key(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return usageError("usage: key <KEY...>")
+	}
+
+	tv, err := c.readyTV(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = tv.Close()
+	}()
+
+	for _, name := range args {
+		err = tv.ClickKeyContext(ctx, samsung.ParseKey(name))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *cli) keys(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return usageError("usage: keys <sequence>")
+	}
+
+	sequence, err := samsung.ParseKeySequence(strings.Join(args, " "))
+	if err != nil {
+		return withExitCode(exitUsage, err)
+	}
+
+	tv, err := c.readyTV(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = tv.Close()
+	}()
+
+	return tv.SendKeysContext(ctx, sequence)
+}
+
+func (c *cli) apps(ctx context.Context, args []string) error {
+	if len(args) != 0 {
+		return usageError("apps takes no arguments")
+	}
+
+	tv, err := c.readyTV(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = tv.Close()
+	}()
+
+	apps, err := tv.AppsContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	sort.Slice(apps, func(i, j int) bool {
+		return strings.ToLower(apps[i].Name) < strings.ToLower(apps[j].Name)
+	})
+
+	values := make([]appOutput, 0, len(apps))
+	rows := make([][]string, 0, len(apps))
+	for _, app := range apps {
+		values = append(values, appOutput{
+			ID:      app.ID,
+			Name:    app.Name,
+			Running: app.IsRunning,
+			Visible: app.IsVisible,
+			Version: app.Version,
+		})
+		rows = append(rows, []string{app.ID, app.Name, yesNo(app.IsRunning), yesNo(app.IsVisible), app.Version})
+	}
+
+	return c.out.write(values, []string{"ID", "NAME", "RUNNING", "VISIBLE", "VERSION"}, rows)
+}
+
+func (c *cli) app(ctx context.Context, args []string) error {
+	if len(args) != 2 {
+		return usageError("usage: app open|close|install <app>")
+	}
+
+	action, id := args[0], args[1]
+	if action != "open" && action != "close" && action != "install" {
+		return usageError("unknown app action %q", action)
+	}
+
+	tv, err := c.readyTV(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = tv.Close()
+	}()
+
+	switch action {
+	case "open":
+		id, err = appID(ctx, tv, id)
+		if err != nil {
+			return err
+		}
+
+		return tv.OpenAppContext(ctx, id)
+	case "close":
+		id, err = appID(ctx, tv, id)
+		if err != nil {
+			return err
+		}
+
+		return tv.CloseAppContext(ctx, id)
+	default:
+		return tv.InstallAppContext(ctx, id)
+	}
+}
+
+// appID resolves an app name to its ID. Names are looked up among installed
+// apps, anything else is taken for an ID.
+func appID(ctx context.Context, tv *samsung.TV, nameOrID string) (string, error) {
+	apps, err := tv.AppsContext(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	for _, app := range apps {
+		if app.ID == nameOrID {
+			return app.ID, nil
+		}
+	}
+
+	for _, app := range apps {
+		if strings.EqualFold(app.Name, nameOrID) {
+			return app.ID, nil
+		}
+	}
+
+	return nameOrID, nil
+}
+
+func (c *cli) browser(ctx context.Context, args []string) error {
+	if len(args) != 1 {
+		return usageError("usage: browser <url>")
+	}
+
+	tv, err := c.readyTV(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = tv.Close()
+	}()
+
+	return tv.OpenBrowserContext(ctx, args[0])
+}
+
+// watch prints events until interrupted. The power state is polled, the
+// remote control connection is reopened whenever the TV turns on, so that
+// client and update events keep coming.
+func (c *cli) watch(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("watch", flag.ContinueOnError)
+	flags.SetOutput(c.stderr)
+	interval := flags.Duration("interval", 5*time.Second, "power state polling interval")
+
+	err := flags.Parse(args)
+	if err != nil {
+		return withExitCode(exitUsage, err)
+	}
+
+	if flags.NArg() != 0 || *interval <= 0 {
+		return usageError("usage: watch [-interval 5s]")
+	}
+
+	tv, err := c.tv()
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = tv.Close()
+	}()
+
+	var mu sync.Mutex
+	emit := func(event string, data interface{}, detail string) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		now := time.Now()
+		line := now.Format("15:04:05") + "  " + event
+		if detail != "" {
+			line += "  " + detail
+		}
+
+		_ = c.out.writeLine(eventOutput{Time: now, Event: event, Data: data}, line)
+	}
+
+	unsubscribes := []func(){
+		tv.OnClientConnect(func(client samsung.TVClient) {
+			emit("client_connect", clientData(client), clientDetail(client))
+		}),
+		tv.OnClientDisconnect(func(client samsung.TVClient) {
+			emit("client_disconnect", clientData(client), clientDetail(client))
+		}),
+		tv.OnUpdate(func(update samsung.TVUpdate) {
+			emit("update", map[string]string{"type": update.Type}, update.Type)
+		}),
+	}
+
+	defer func() {
+		for _, unsubscribe := range unsubscribes {
+			unsubscribe()
+		}
+	}()
+
+	unsubscribeRenderer, err := tv.SubscribeRendererEventsContext(ctx, func(event samsung.RendererEvent) {
+		data, detail := rendererData(event)
+		emit("renderer", data, detail)
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		_, _ = fmt.Fprintf(c.stderr, "tizentv: renderer events are not available: %s\n", err)
+	} else {
+		defer func() {
+			_ = unsubscribeRenderer()
+		}()
+	}
+
+	var previous samsung.PowerState
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+
+	for {
+		state, err := tv.PowerStateContext(ctx)
+		if err != nil {
+			// Interrupting is the normal way to stop watching.
+			return nil
+		}
+
+		if state != previous {
+			emit("power", powerOutput{State: state}, string(state))
+
+			if state == samsung.PowerStateOn {
+				err = tv.ConnectContext(ctx)
+				if err != nil && ctx.Err() == nil {
+					_, _ = fmt.Fprintf(c.stderr, "tizentv: connect: %s\n", err)
+				}
+			}
+
+			previous = state
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+func clientData(client samsung.TVClient) map[string]interface{} {
+	return map[string]interface{}{
+		"id":           client.ID,
+		"name":         client.Name,
+		"device_name":  client.DeviceName,
+		"is_host":      client.IsHost,
+		"connected_at": client.ConnectedAt,
+	}
+}
+
+func clientDetail(client samsung.TVClient) string {
+	return fmt.Sprintf("%s (%s)", valueOr(client.Name, client.ID), valueOr(client.DeviceName, "unknown device"))
+}
+
+func rendererData(event samsung.RendererEvent) (map[string]interface{}, string) {
+	data := map[string]interface{}{}
+	var details []string
+
+	if event.Volume != nil {
+		data["volume"] = *event.Volume
+		details = append(details, "volume="+strconv.Itoa(*event.Volume))
+	}
+
+	if event.Mute != nil {
+		data["mute"] = *event.Mute
+		details = append(details, "mute="+strconv.FormatBool(*event.Mute))
+	}
+
+	if event.PlayerState != "" {
+		data["player_state"] = event.PlayerState
+		details = append(details, "state="+string(event.PlayerState))
+	}
+
+	if event.TrackURL != "" {
+		data["track_url"] = event.TrackURL
+		details = append(details, "track="+event.TrackURL)
+	}
+
+	if event.TrackDuration != 0 {
+		data["track_duration"] = event.TrackDuration.String()
+		details = append(details, "duration="+event.TrackDuration.String())
+	}
+
+	return data, strings.Join(details, " ")
+}
+
+func isPaired(deviceConfig samsung.DeviceConfig) bool {
+	return deviceConfig.WebsocketAPI.Token != "" || deviceConfig.WebsocketAPI.SessionID != ""
+}
+
+func sortedRows(prefix string, values map[string]string) [][]string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	rows := make([][]string, 0, len(keys))
+	for _, key := range keys {
+		rows = append(rows, []string{prefix + key, values[key]})
+	}
+
+	return rows
+}
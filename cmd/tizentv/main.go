@@ -0,0 +1,293 @@
+// Command tizentv controls Samsung TVs from the shell. TVs are found with
+// "discover" and kept in a YAML config, commands select one of them by ID,
+// name or IP address.
+//
+// Exit codes:
+//
+//	0  success, or the TV is on for "power status"
+//	1  the command failed
+//	2  invalid usage
+//	3  the device is not in the config or the selection is ambiguous
+//	4  the TV is off or unreachable
+//	5  the TV is in standby
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	samsung "github.com/kpeu3i/go-tizen-tv"
+)
+
+const (
+	exitOK          = 0
+	exitFailure     = 1
+	exitUsage       = 2
+	exitNotFound    = 3
+	exitUnavailable = 4
+	exitStandby     = 5
+)
+
+const usage = `Usage: tizentv [flags] <command> [arguments]
+
+Commands:
+  discover                    find TVs on the network and add them to the config
+  list                        list TVs in the config
+  pair                        connect to the TV and store the token it issues
+  info                        show information about the TV
+  power on|off|status         power the TV on or off, or show its power state
+  key <KEY...>                click keys, e.g. "key home volup"
+  keys <sequence>             send a key sequence, e.g. "keys home, right*3 1s, enter"
+  apps                        list installed apps
+  app open|close|install <app>
+                              open or close an app by ID or name, install one by ID
+  browser <url>               open a URL in the browser
+  watch [-interval 5s]        print power, client and renderer events until interrupted
+  remote                      full-screen remote control, with text entry and app launch
+
+Flags:
+`
+
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string {
+	return e.err.Error()
+}
+
+func withExitCode(code int, err error) error {
+	return &exitError{code: code, err: err}
+}
+
+func usageError(format string, args ...interface{}) error {
+	return withExitCode(exitUsage, fmt.Errorf(format, args...))
+}
+
+type cli struct {
+	manager  *samsung.TVManager
+	storage  *samsung.TVManagerConfigStorageYAML
+	selector string
+	timeout  time.Duration
+	out      *output
+	stdin    io.Reader
+	stderr   io.Writer
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("tizentv", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		_, _ = fmt.Fprint(stderr, usage)
+		flags.PrintDefaults()
+	}
+
+	configPath := flags.String("config", envOr("TIZENTV_CONFIG", "config.yaml"), "config file")
+	selector := flags.String("tv", os.Getenv("TIZENTV_DEVICE"), "ID, name or IP address of the TV, may be omitted when only one is configured")
+	format := flags.String("o", "table", "output format, table or json")
+	timeout := flags.Duration("timeout", 1*time.Minute, "timeout of the command, or of each request of watch and remote")
+
+	err := flags.Parse(args)
+	if err != nil {
+		if err == flag.ErrHelp {
+			return exitOK
+		}
+
+		return exitUsage
+	}
+
+	if *format != "table" && *format != "json" {
+		_, _ = fmt.Fprintf(stderr, "tizentv: unknown output format %q\n", *format)
+
+		return exitUsage
+	}
+
+	if flags.NArg() == 0 {
+		flags.Usage()
+
+		return exitUsage
+	}
+
+	storage := samsung.NewTVManagerConfigStorageYAML(*configPath)
+
+	c := &cli{
+		manager:  samsung.NewTVManager(samsung.WithTVManagerConfigStorage(storage)),
+		storage:  storage,
+		selector: *selector,
+		timeout:  *timeout,
+		out:      &output{json: *format == "json", w: stdout},
+		stdin:    stdin,
+		stderr:   stderr,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	go func() {
+		select {
+		case <-signals:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	err = c.run(ctx, flags.Arg(0), flags.Args()[1:])
+	if err == nil {
+		return exitOK
+	}
+
+	code := exitFailure
+	if e, ok := err.(*exitError); ok {
+		code = e.code
+		err = e.err
+	}
+
+	// Errors without a message only set the exit code.
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "tizentv: %s\n", err)
+	}
+
+	return code
+}
+
+func (c *cli) run(ctx context.Context, command string, args []string) error {
+	// Interactive commands apply the timeout to each request instead.
+	if command != "watch" && command != "remote" && c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	switch command {
+	case "discover":
+		return c.discover(ctx, args)
+	case "list":
+		return c.list(args)
+	case "pair":
+		return c.pair(ctx, args)
+	case "info":
+		return c.info(ctx, args)
+	case "power":
+		return c.power(ctx, args)
+	case "key":
+		return c.key(ctx, args)
+	case "keys":
+		return c.keys(ctx, args)
+	case "apps":
+		return c.apps(ctx, args)
+	case "app":
+		return c.app(ctx, args)
+	case "browser":
+		return c.browser(ctx, args)
+	case "watch":
+		return c.watch(ctx, args)
+	case "remote":
+		return c.remote(ctx, args)
+	default:
+		return usageError("unknown command %q", command)
+	}
+}
+
+// device returns the configured device matching the selector. The ID and the
+// host must match exactly, the name is compared case-insensitively.
+func (c *cli) device() (samsung.DeviceConfig, error) {
+	config, err := c.storage.Load()
+	if err != nil {
+		return samsung.DeviceConfig{}, err
+	}
+
+	if len(config.Devices) == 0 {
+		return samsung.DeviceConfig{}, withExitCode(exitNotFound, errors.New("no TVs are configured, run discover first"))
+	}
+
+	if c.selector == "" {
+		if len(config.Devices) > 1 {
+			return samsung.DeviceConfig{}, usageError("several TVs are configured, select one with -tv")
+		}
+
+		return config.Devices[0], nil
+	}
+
+	for _, deviceConfig := range config.Devices {
+		if deviceConfig.ID == c.selector || deviceConfig.Host == c.selector {
+			return deviceConfig, nil
+		}
+	}
+
+	var matches []samsung.DeviceConfig
+	for _, deviceConfig := range config.Devices {
+		if strings.EqualFold(deviceConfig.Name, c.selector) {
+			matches = append(matches, deviceConfig)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return samsung.DeviceConfig{}, withExitCode(exitNotFound, fmt.Errorf("tv %q not found", c.selector))
+	case 1:
+		return matches[0], nil
+	default:
+		return samsung.DeviceConfig{}, withExitCode(
+			exitNotFound,
+			fmt.Errorf("several TVs are named %q, select one by ID or IP address", c.selector),
+		)
+	}
+}
+
+func (c *cli) tv() (*samsung.TV, error) {
+	deviceConfig, err := c.device()
+	if err != nil {
+		return nil, err
+	}
+
+	return c.manager.LoadByID(deviceConfig.ID)
+}
+
+// readyTV returns the selected TV once it is known to be on, so that commands
+// fail with a distinct exit code when it is not.
+func (c *cli) readyTV(ctx context.Context) (*samsung.TV, error) {
+	tv, err := c.tv()
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := tv.PowerStateContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	switch state {
+	case samsung.PowerStateOff:
+		return nil, withExitCode(exitUnavailable, errors.New("tv is off or unreachable"))
+	case samsung.PowerStateStandby:
+		return nil, withExitCode(exitStandby, errors.New("tv is in standby"))
+	}
+
+	return tv, nil
+}
+
+func envOr(name string, fallback string) string {
+	value := os.Getenv(name)
+	if value == "" {
+		return fallback
+	}
+
+	return value
+}
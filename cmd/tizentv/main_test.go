@@ -0,0 +1,150 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	samsung "github.com/kpeu3i/go-tizen-tv"
+)
+
+var testDevices = []samsung.DeviceConfig{
+	{ID: "uuid:living-room", Name: "Living room", Host: "192.0.2.10"},
+	{ID: "uuid:bedroom", Name: "Bedroom", Host: "192.0.2.11"},
+	{ID: "uuid:kitchen-1", Name: "Kitchen", Host: "192.0.2.12"},
+	{ID: "uuid:kitchen-2", Name: "kitchen", Host: "192.0.2.13"},
+	// Named like the address of another TV, which is selected by its address.
+	{ID: "uuid:confusing", Name: "192.0.2.10", Host: "192.0.2.14"},
+}
+
+// writeTestConfig stores the devices in a config file and returns its path and
+// a function removing it.
+func writeTestConfig(t *testing.T, devices ...samsung.DeviceConfig) (string, func()) {
+	t.Helper()
+
+	dir, err := ioutil.TempDir("", "tizentv")
+	if err != nil {
+		t.Fatalf("TempDir() = %v", err)
+	}
+
+	path := filepath.Join(dir, "config.yaml")
+
+	err = samsung.NewTVManagerConfigStorageYAML(path).Store(samsung.TVManagerConfig{Devices: devices})
+	if err != nil {
+		t.Fatalf("Store() = %v", err)
+	}
+
+	return path, func() {
+		_ = os.RemoveAll(dir)
+	}
+}
+
+func TestRunExitCodes(t *testing.T) {
+	path, remove := writeTestConfig(t, testDevices...)
+	defer remove()
+
+	emptyPath, removeEmpty := writeTestConfig(t)
+	defer removeEmpty()
+
+	tests := []struct {
+		name string
+		args []string
+		want int
+	}{
+		{name: "help", args: []string{"-h"}, want: exitOK},
+		{name: "list", args: []string{"-config", path, "list"}, want: exitOK},
+		{name: "no command", args: []string{"-config", path}, want: exitUsage},
+		{name: "unknown flag", args: []string{"-config", path, "-unknown", "list"}, want: exitUsage},
+		{name: "unknown format", args: []string{"-config", path, "-o", "xml", "list"}, want: exitUsage},
+		{name: "unknown command", args: []string{"-config", path, "rewind"}, want: exitUsage},
+		{name: "extra arguments", args: []string{"-config", path, "list", "all"}, want: exitUsage},
+		{name: "no selection", args: []string{"-config", path, "-tv", "", "info"}, want: exitUsage},
+		{name: "invalid power", args: []string{"-config", path, "-tv", "Bedroom", "power", "toggle"}, want: exitUsage},
+		{name: "not configured", args: []string{"-config", emptyPath, "-tv", "", "info"}, want: exitNotFound},
+		{name: "not found", args: []string{"-config", path, "-tv", "Garage", "info"}, want: exitNotFound},
+		{name: "ambiguous", args: []string{"-config", path, "-tv", "KITCHEN", "info"}, want: exitNotFound},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+
+			code := run(test.args, strings.NewReader(""), &stdout, &stderr)
+			if code != test.want {
+				t.Errorf("run(%q) = %d, want %d, stderr: %s", test.args, code, test.want, stderr.String())
+			}
+
+			if code != exitOK && stderr.Len() == 0 {
+				t.Errorf("run(%q) failed without a message", test.args)
+			}
+		})
+	}
+}
+
+func TestRunList(t *testing.T) {
+	path, remove := writeTestConfig(t, testDevices...)
+	defer remove()
+
+	var stdout, stderr bytes.Buffer
+
+	code := run([]string{"-config", path, "-o", "json", "list"}, strings.NewReader(""), &stdout, &stderr)
+	if code != exitOK {
+		t.Fatalf("run() = %d, stderr: %s", code, stderr.String())
+	}
+
+	for _, deviceConfig := range testDevices {
+		if !strings.Contains(stdout.String(), `"`+deviceConfig.ID+`"`) {
+			t.Errorf("output has no device %s: %s", deviceConfig.ID, stdout.String())
+		}
+	}
+}
+
+func TestCLIDevice(t *testing.T) {
+	path, remove := writeTestConfig(t, testDevices...)
+	defer remove()
+
+	singlePath, removeSingle := writeTestConfig(t, testDevices[1])
+	defer removeSingle()
+
+	tests := []struct {
+		name     string
+		path     string
+		selector string
+		wantID   string
+		wantCode int
+	}{
+		{name: "ID", path: path, selector: "uuid:bedroom", wantID: "uuid:bedroom"},
+		{name: "IP address", path: path, selector: "192.0.2.11", wantID: "uuid:bedroom"},
+		{name: "name in another case", path: path, selector: "living ROOM", wantID: "uuid:living-room"},
+		{name: "address before name", path: path, selector: "192.0.2.10", wantID: "uuid:living-room"},
+		{name: "ID is exact", path: path, selector: "UUID:BEDROOM", wantCode: exitNotFound},
+		{name: "ambiguous name", path: path, selector: "Kitchen", wantCode: exitNotFound},
+		{name: "ambiguous name by ID", path: path, selector: "uuid:kitchen-2", wantID: "uuid:kitchen-2"},
+		{name: "several without selector", path: path, wantCode: exitUsage},
+		{name: "only one", path: singlePath, wantID: "uuid:bedroom"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := &cli{storage: samsung.NewTVManagerConfigStorageYAML(test.path), selector: test.selector}
+
+			deviceConfig, err := c.device()
+
+			if test.wantCode != 0 {
+				e, ok := err.(*exitError)
+				if !ok || e.code != test.wantCode {
+					t.Fatalf("device() = %+v, %v, want exit code %d", deviceConfig, err, test.wantCode)
+				}
+
+				return
+			}
+
+			if err != nil || deviceConfig.ID != test.wantID {
+				t.Errorf("device() = %s, %v, want %s", deviceConfig.ID, err, test.wantID)
+			}
+		})
+	}
+}
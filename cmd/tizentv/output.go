@@ -0,0 +1,70 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+type output struct {
+	json bool
+	w    io.Writer
+}
+
+// write prints value as indented JSON or rows as a table with a header.
+func (o *output) write(value interface{}, header []string, rows [][]string) error {
+	if o.json {
+		encoder := json.NewEncoder(o.w)
+		encoder.SetIndent("", "  ")
+
+		return encoder.Encode(value)
+	}
+
+	w := tabwriter.NewWriter(o.w, 0, 4, 2, ' ', 0)
+
+	if header != nil {
+		_, err := fmt.Fprintln(w, strings.Join(header, "\t"))
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, row := range rows {
+		_, err := fmt.Fprintln(w, strings.Join(row, "\t"))
+		if err != nil {
+			return err
+		}
+	}
+
+	return w.Flush()
+}
+
+// writeLine prints value as a single line of JSON or line as it is, for
+// streamed output.
+func (o *output) writeLine(value interface{}, line string) error {
+	if o.json {
+		return json.NewEncoder(o.w).Encode(value)
+	}
+
+	_, err := fmt.Fprintln(o.w, line)
+
+	return err
+}
+
+func yesNo(value bool) string {
+	if value {
+		return "yes"
+	}
+
+	return "no"
+}
+
+func valueOr(value string, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+
+	return value
+}
@@ -0,0 +1,629 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	samsung "github.com/kpeu3i/go-tizen-tv"
+)
+
+const (
+	remotePowerPollInterval = 3 * time.Second
+	remoteReleaseTimeout    = 2 * time.Second
+	remoteActionsBufferSize = 16
+	remoteHelpColumnWidth   = 26
+	remoteAppSelectors      = "123456789abcdefghijklmnopqrstuvwxyz"
+)
+
+type remoteMode int
+
+const (
+	remoteModeKeys remoteMode = iota
+	remoteModeText
+	remoteModeApps
+)
+
+const (
+	connectionDisconnected = "disconnected"
+	connectionConnecting   = "connecting"
+	connectionConnected    = "connected"
+)
+
+// remoteAction runs on the worker, so that the screen stays responsive while
+// the TV answers. Its done function runs on the main loop with the result.
+type remoteAction struct {
+	run  func(ctx context.Context) error
+	done func(err error)
+}
+
+type remoteResult struct {
+	action remoteAction
+	err    error
+}
+
+type remote struct {
+	cli        *cli
+	tv         *samsung.TV
+	device     samsung.DeviceConfig
+	term       *terminal
+	mode       remoteMode
+	power      samsung.PowerState
+	connection string
+	paired     bool
+	text       []rune
+	apps       []samsung.TVApp
+	holdNext   bool
+	held       samsung.Key
+	pending    int
+	last       string
+	message    string
+	actions    chan remoteAction
+}
+
+// remote runs a full-screen remote control until "q" or Ctrl-C is pressed.
+func (c *cli) remote(ctx context.Context, args []string) error {
+	if len(args) != 0 {
+		return usageError("remote takes no arguments")
+	}
+
+	stdin, ok := c.stdin.(*os.File)
+	if !ok {
+		return errors.New("the remote needs a terminal")
+	}
+
+	deviceConfig, err := c.device()
+	if err != nil {
+		return err
+	}
+
+	tv, err := c.manager.LoadByID(deviceConfig.ID)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = tv.Close()
+	}()
+
+	err = tv.OnPINRequest(func(ctx context.Context) (string, error) {
+		return "", errors.New(`the TV asks for a PIN, pair it with "tizentv pair" first`)
+	})
+	if err != nil && err != samsung.ErrPairingNotSupported {
+		return err
+	}
+
+	term, err := openTerminal(int(stdin.Fd()))
+	if err != nil {
+		return fmt.Errorf("the remote needs a terminal: %s", err)
+	}
+
+	defer func() {
+		_ = term.restore()
+	}()
+
+	// Switch to the alternate screen and hide the cursor until exiting.
+	_, _ = fmt.Fprint(c.out.w, "\x1b[?1049h\x1b[?25l")
+	defer func() {
+		_, _ = fmt.Fprint(c.out.w, "\x1b[?25h\x1b[?1049l")
+	}()
+
+	r := &remote{
+		cli:        c,
+		tv:         tv,
+		device:     deviceConfig,
+		term:       term,
+		connection: connectionDisconnected,
+		paired:     isPaired(deviceConfig),
+		actions:    make(chan remoteAction, remoteActionsBufferSize),
+	}
+
+	err = r.run(ctx, stdin)
+
+	if r.held != "" {
+		releaseCtx, cancel := context.WithTimeout(context.Background(), remoteReleaseTimeout)
+		defer cancel()
+
+		_ = tv.ReleaseKeyContext(releaseCtx, r.held)
+	}
+
+	return err
+}
+
+func (r *remote) run(ctx context.Context, stdin *os.File) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	inputs := make(chan []byte)
+	go func() {
+		defer close(inputs)
+
+		for {
+			b := make([]byte, 64)
+			n, err := stdin.Read(b)
+			if err != nil {
+				return
+			}
+
+			select {
+			case inputs <- b[:n]:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	results := make(chan remoteResult)
+	go func() {
+		for {
+			select {
+			case action := <-r.actions:
+				actionCtx, cancel := ctx, context.CancelFunc(func() {})
+				if r.cli.timeout > 0 {
+					actionCtx, cancel = context.WithTimeout(ctx, r.cli.timeout)
+				}
+
+				err := action.run(actionCtx)
+				cancel()
+
+				select {
+				case results <- remoteResult{action: action, err: err}:
+				case <-ctx.Done():
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	powerStates := make(chan samsung.PowerState)
+	go func() {
+		ticker := time.NewTicker(remotePowerPollInterval)
+		defer ticker.Stop()
+
+		for {
+			state, err := r.tv.PowerStateContext(ctx)
+			if err != nil {
+				return
+			}
+
+			select {
+			case powerStates <- state:
+			case <-ctx.Done():
+				return
+			}
+
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	for {
+		r.render()
+
+		select {
+		case b, ok := <-inputs:
+			if !ok {
+				return nil
+			}
+
+			for _, input := range decodeInput(b) {
+				if !r.handleInput(input) {
+					return nil
+				}
+			}
+		case result := <-results:
+			r.pending--
+			result.action.done(result.err)
+		case state := <-powerStates:
+			r.setPower(state)
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+func (r *remote) setPower(state samsung.PowerState) {
+	r.power = state
+
+	if state != samsung.PowerStateOn {
+		r.connection = connectionDisconnected
+
+		return
+	}
+
+	if r.connection == connectionDisconnected {
+		r.connect()
+	}
+}
+
+// handleInput handles an input and reports whether the remote keeps running.
+func (r *remote) handleInput(input string) bool {
+	if input == inputCtrlC {
+		return false
+	}
+
+	r.message = ""
+
+	// A held key is released by the next input, which does nothing else.
+	if r.held != "" {
+		key := r.held
+		r.held = ""
+		r.enqueue(func(ctx context.Context) error {
+			return r.tv.ReleaseKeyContext(ctx, key)
+		}, r.keyDone("release "+string(key)))
+
+		return true
+	}
+
+	switch r.mode {
+	case remoteModeText:
+		r.handleTextInput(input)
+	case remoteModeApps:
+		r.handleAppsInput(input)
+	default:
+		return r.handleKeysInput(input)
+	}
+
+	return true
+}
+
+func (r *remote) handleKeysInput(input string) bool {
+	switch input {
+	case "q":
+		return false
+	case "t":
+		r.mode = remoteModeText
+	case "a":
+		r.mode = remoteModeApps
+		r.loadApps()
+	case "P":
+		r.togglePower()
+	case "H":
+		r.holdNext = !r.holdNext
+	case inputEsc:
+		r.holdNext = false
+	default:
+		key, ok := bindingKey(input)
+		if !ok {
+			r.message = fmt.Sprintf("%s is not bound", displayInput(input))
+
+			return true
+		}
+
+		if r.holdNext {
+			r.holdNext = false
+			r.held = key
+			r.enqueue(func(ctx context.Context) error {
+				return r.tv.PressKeyContext(ctx, key)
+			}, r.keyDone("press "+string(key)))
+
+			return true
+		}
+
+		r.enqueue(func(ctx context.Context) error {
+			return r.tv.ClickKeyContext(ctx, key)
+		}, r.keyDone(string(key)))
+	}
+
+	return true
+}
+
+func (r *remote) handleTextInput(input string) {
+	switch input {
+	case inputEsc:
+		r.mode = remoteModeKeys
+	case inputBackspace:
+		if len(r.text) > 0 {
+			r.text = r.text[:len(r.text)-1]
+		}
+	case inputEnter:
+		text := string(r.text)
+		r.text = nil
+		r.enqueue(func(ctx context.Context) error {
+			err := r.tv.SendTextContext(ctx, text)
+			if err != nil {
+				return err
+			}
+
+			return r.tv.EndTextInputContext(ctx)
+		}, r.keyDone(fmt.Sprintf("text %q", text)))
+	default:
+		if utf8.RuneCountInString(input) == 1 {
+			r.text = append(r.text, []rune(input)...)
+		}
+	}
+}
+
+func (r *remote) handleAppsInput(input string) {
+	switch input {
+	case inputEsc, inputBackspace:
+		r.mode = remoteModeKeys
+
+		return
+	}
+
+	i := strings.Index(remoteAppSelectors, input)
+	if i < 0 || len(input) != 1 {
+		return
+	}
+
+	if i >= len(r.apps) {
+		r.message = fmt.Sprintf("no app on %s", input)
+
+		return
+	}
+
+	app := r.apps[i]
+	r.enqueue(func(ctx context.Context) error {
+		return r.tv.OpenAppContext(ctx, app.ID)
+	}, func(err error) {
+		r.keyDone("open " + app.Name)(err)
+
+		if err == nil {
+			r.mode = remoteModeKeys
+		}
+	})
+}
+
+func (r *remote) connect() {
+	r.connection = connectionConnecting
+	if !r.paired {
+		r.message = "allow the connection on the TV if it asks for it"
+	}
+
+	r.enqueue(r.tv.ConnectContext, func(err error) {
+		if err != nil {
+			r.connection = connectionDisconnected
+			r.message = err.Error()
+
+			return
+		}
+
+		r.connection = connectionConnected
+		r.message = ""
+
+		// The manager stores the token, so the config tells whether it was issued.
+		deviceConfig, err := r.cli.device()
+		if err == nil {
+			r.paired = isPaired(deviceConfig)
+		}
+	})
+}
+
+func (r *remote) loadApps() {
+	var apps []samsung.TVApp
+
+	r.enqueue(func(ctx context.Context) error {
+		var err error
+		apps, err = r.tv.AppsContext(ctx)
+
+		return err
+	}, func(err error) {
+		if err != nil {
+			r.message = err.Error()
+
+			return
+		}
+
+		sort.Slice(apps, func(i, j int) bool {
+			return strings.ToLower(apps[i].Name) < strings.ToLower(apps[j].Name)
+		})
+
+		r.apps = apps
+	})
+}
+
+func (r *remote) togglePower() {
+	on := r.power != samsung.PowerStateOn
+	if on {
+		r.message = "powering on"
+	} else {
+		r.message = "powering off"
+	}
+
+	r.enqueue(func(ctx context.Context) error {
+		return r.tv.SetPowerContext(ctx, on)
+	}, func(err error) {
+		if err != nil {
+			r.message = err.Error()
+
+			return
+		}
+
+		r.message = ""
+		if on {
+			r.setPower(samsung.PowerStateOn)
+		} else {
+			r.setPower(samsung.PowerStateOff)
+		}
+	})
+}
+
+// keyDone returns the done function of an action sending input to the TV,
+// which keeps the connection state up to date.
+func (r *remote) keyDone(description string) func(err error) {
+	return func(err error) {
+		if err != nil {
+			r.message = fmt.Sprintf("%s: %s", description, err)
+
+			return
+		}
+
+		r.last = description
+		r.connection = connectionConnected
+	}
+}
+
+func (r *remote) enqueue(run func(ctx context.Context) error, done func(err error)) {
+	select {
+	case r.actions <- remoteAction{run: run, done: done}:
+		r.pending++
+	default:
+		r.message = "the TV is busy, input was dropped"
+	}
+}
+
+func (r *remote) render() {
+	width, height := r.term.size()
+
+	name := valueOr(r.device.Name, r.device.ID)
+	lines := []string{
+		fmt.Sprintf("tizentv remote: %s (%s)", name, r.device.Host),
+		fmt.Sprintf(
+			"power: %s   connection: %s   paired: %s",
+			valueOr(string(r.power), "unknown"),
+			r.connection,
+			yesNo(r.paired),
+		),
+		r.modeLine(),
+		"",
+	}
+
+	switch r.mode {
+	case remoteModeText:
+		lines = append(lines,
+			"text: "+string(r.text)+"_",
+			"",
+			"enter  send    backspace  delete    esc  back",
+		)
+	case remoteModeApps:
+		lines = append(lines, r.appLines(height-len(lines)-4)...)
+		lines = append(lines, "", "press the key of an app to open it    esc  back")
+	default:
+		lines = append(lines, helpLines(width)...)
+	}
+
+	lines = append(lines, "")
+	if r.last != "" {
+		lines = append(lines, "last: "+r.last)
+	}
+
+	if r.pending > 0 {
+		lines = append(lines, "sending...")
+	}
+
+	if r.message != "" {
+		lines = append(lines, r.message)
+	}
+
+	if len(lines) > height {
+		lines = lines[:height]
+	}
+
+	for i, line := range lines {
+		lines[i] = truncate(line, width)
+	}
+
+	_, _ = fmt.Fprint(r.cli.out.w, "\x1b[H\x1b[2J"+strings.Join(lines, "\r\n"))
+}
+
+func (r *remote) modeLine() string {
+	switch r.mode {
+	case remoteModeText:
+		return "mode: text"
+	case remoteModeApps:
+		return "mode: apps"
+	}
+
+	switch {
+	case r.held != "":
+		return fmt.Sprintf("mode: keys, holding %s until the next key", r.held)
+	case r.holdNext:
+		return "mode: keys, the next key is held"
+	default:
+		return "mode: keys"
+	}
+}
+
+func (r *remote) appLines(limit int) []string {
+	if r.apps == nil {
+		return []string{"loading apps..."}
+	}
+
+	if len(r.apps) == 0 {
+		return []string{"no apps are installed"}
+	}
+
+	var lines []string
+	for i, app := range r.apps {
+		if i >= len(remoteAppSelectors) || i >= limit {
+			break
+		}
+
+		line := fmt.Sprintf("%c  %s", remoteAppSelectors[i], app.Name)
+		if app.IsRunning {
+			line += " (running)"
+		}
+
+		lines = append(lines, line)
+	}
+
+	return lines
+}
+
+// helpLines lays the bindings out in as many columns as the width allows.
+func helpLines(width int) []string {
+	entries := []string{"0-9        digits"}
+	for _, binding := range keyBindings {
+		if binding.label != "" {
+			entries = append(entries, fmt.Sprintf("%-10s %s", displayInput(binding.input), binding.label))
+		}
+	}
+
+	entries = append(entries,
+		"t          type text",
+		"a          apps",
+		"P          power on/off",
+		"H          hold next key",
+		"q          quit",
+	)
+
+	columns := width / remoteHelpColumnWidth
+	if columns < 1 {
+		columns = 1
+	}
+
+	rows := (len(entries) + columns - 1) / columns
+
+	lines := make([]string, rows)
+	for i, entry := range entries {
+		row := i % rows
+		if i/rows < columns-1 {
+			entry = fmt.Sprintf("%-*s", remoteHelpColumnWidth, entry)
+		}
+
+		lines[row] += entry
+	}
+
+	return lines
+}
+
+func displayInput(input string) string {
+	if input == " " {
+		return "space"
+	}
+
+	if len(input) > 2 && strings.HasPrefix(input, "<") && strings.HasSuffix(input, ">") {
+		return input[1 : len(input)-1]
+	}
+
+	return input
+}
+
+func truncate(line string, width int) string {
+	if utf8.RuneCountInString(line) <= width {
+		return line
+	}
+
+	return string([]rune(line)[:width])
+}
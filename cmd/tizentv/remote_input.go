@@ -0,0 +1,179 @@
+package main
+
+import (
+	"unicode/utf8"
+
+	samsung "github.com/kpeu3i/go-tizen-tv"
+)
+
+// Names of non-printable inputs. Printable ones are named by their character.
+const (
+	inputUp        = "<up>"
+	inputDown      = "<down>"
+	inputLeft      = "<left>"
+	inputRight     = "<right>"
+	inputEnter     = "<enter>"
+	inputBackspace = "<backspace>"
+	inputTab       = "<tab>"
+	inputEsc       = "<esc>"
+	inputCtrlC     = "<ctrl-c>"
+	inputF1        = "<f1>"
+	inputF2        = "<f2>"
+	inputF3        = "<f3>"
+	inputF4        = "<f4>"
+)
+
+type keyBinding struct {
+	input string
+	key   samsung.Key
+	// label is shown in the help, bindings without one are aliases.
+	label string
+}
+
+var keyBindings = []keyBinding{
+	{input: inputUp, key: samsung.KEY_UP, label: "move up"},
+	{input: inputDown, key: samsung.KEY_DOWN, label: "move down"},
+	{input: inputLeft, key: samsung.KEY_LEFT, label: "move left"},
+	{input: inputRight, key: samsung.KEY_RIGHT, label: "move right"},
+	{input: inputEnter, key: samsung.KEY_ENTER, label: "select"},
+	{input: inputBackspace, key: samsung.KEY_RETURN, label: "back"},
+	{input: "0", key: samsung.KEY_0},
+	{input: "1", key: samsung.KEY_1},
+	{input: "2", key: samsung.KEY_2},
+	{input: "3", key: samsung.KEY_3},
+	{input: "4", key: samsung.KEY_4},
+	{input: "5", key: samsung.KEY_5},
+	{input: "6", key: samsung.KEY_6},
+	{input: "7", key: samsung.KEY_7},
+	{input: "8", key: samsung.KEY_8},
+	{input: "9", key: samsung.KEY_9},
+	{input: "h", key: samsung.KEY_HOME, label: "home"},
+	{input: "u", key: samsung.KEY_MENU, label: "menu"},
+	{input: "e", key: samsung.KEY_TOOLS, label: "tools"},
+	{input: "i", key: samsung.KEY_INFO, label: "info"},
+	{input: "g", key: samsung.KEY_GUIDE, label: "guide"},
+	{input: "o", key: samsung.KEY_SOURCE, label: "source"},
+	{input: "+", key: samsung.KEY_VOLUP, label: "volume up"},
+	{input: "=", key: samsung.KEY_VOLUP},
+	{input: "-", key: samsung.KEY_VOLDOWN, label: "volume down"},
+	{input: "m", key: samsung.KEY_MUTE, label: "mute"},
+	{input: "]", key: samsung.KEY_CHUP, label: "channel up"},
+	{input: "[", key: samsung.KEY_CHDOWN, label: "channel down"},
+	{input: "l", key: samsung.KEY_CH_LIST, label: "channels"},
+	{input: "p", key: samsung.KEY_PLAY, label: "play"},
+	{input: " ", key: samsung.KEY_PAUSE, label: "pause"},
+	{input: "s", key: samsung.KEY_STOP, label: "stop"},
+	{input: "<", key: samsung.KEY_REWIND, label: "rewind"},
+	{input: ">", key: samsung.KEY_FF, label: "forward"},
+	{input: "r", key: samsung.KEY_REC, label: "record"},
+	{input: inputF1, key: samsung.KEY_RED, label: "red"},
+	{input: inputF2, key: samsung.KEY_GREEN, label: "green"},
+	{input: inputF3, key: samsung.KEY_YELLOW, label: "yellow"},
+	{input: inputF4, key: samsung.KEY_CYAN, label: "blue"},
+}
+
+func bindingKey(input string) (samsung.Key, bool) {
+	for _, binding := range keyBindings {
+		if binding.input == input {
+			return binding.key, true
+		}
+	}
+
+	return "", false
+}
+
+// decodeInput splits bytes read from a raw terminal into inputs. An escape
+// which ends the read is taken for the escape key, because terminals write
+// escape sequences at once.
+func decodeInput(b []byte) []string {
+	var inputs []string
+
+	for i := 0; i < len(b); {
+		c := b[i]
+
+		switch {
+		case c == 0x1b:
+			if i+1 < len(b) && (b[i+1] == '[' || b[i+1] == 'O') {
+				n, input := decodeEscapeSequence(b[i:])
+				if input != "" {
+					inputs = append(inputs, input)
+				}
+
+				i += n
+
+				continue
+			}
+
+			inputs = append(inputs, inputEsc)
+		case c == '\r' || c == '\n':
+			inputs = append(inputs, inputEnter)
+		case c == 0x7f || c == 0x08:
+			inputs = append(inputs, inputBackspace)
+		case c == '\t':
+			inputs = append(inputs, inputTab)
+		case c == 0x03:
+			inputs = append(inputs, inputCtrlC)
+		case c < 0x20:
+		default:
+			r, size := utf8.DecodeRune(b[i:])
+			if r != utf8.RuneError {
+				inputs = append(inputs, string(r))
+			}
+
+			i += size
+
+			continue
+		}
+
+		i++
+	}
+
+	return inputs
+}
+
+// decodeEscapeSequence decodes a CSI or SS3 sequence at the start of b and
+// returns its length. Unknown sequences are skipped with an empty input.
+func decodeEscapeSequence(b []byte) (int, string) {
+	end := 2
+	for end < len(b) && (b[end] < 0x40 || b[end] > 0x7e) {
+		end++
+	}
+
+	if end == len(b) {
+		return len(b), ""
+	}
+
+	params := string(b[2:end])
+
+	switch b[end] {
+	case 'A':
+		return end + 1, inputUp
+	case 'B':
+		return end + 1, inputDown
+	case 'C':
+		return end + 1, inputRight
+	case 'D':
+		return end + 1, inputLeft
+	case 'P':
+		return end + 1, inputF1
+	case 'Q':
+		return end + 1, inputF2
+	case 'R':
+		return end + 1, inputF3
+	case 'S':
+		return end + 1, inputF4
+	case '~':
+		switch params {
+		case "11":
+			return end + 1, inputF1
+		case "12":
+			return end + 1, inputF2
+		case "13":
+			return end + 1, inputF3
+		case "14":
+			return end + 1, inputF4
+		}
+	}
+
+	return end + 1, ""
+}
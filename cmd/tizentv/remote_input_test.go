@@ -0,0 +1,61 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDecodeInput(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{name: "CSI arrows", input: "\x1b[A\x1b[B\x1b[C\x1b[D", want: []string{inputUp, inputDown, inputRight, inputLeft}},
+		{name: "SS3 arrows", input: "\x1bOA\x1bOB\x1bOC\x1bOD", want: []string{inputUp, inputDown, inputRight, inputLeft}},
+		{name: "arrow with modifier", input: "\x1b[1;5A", want: []string{inputUp}},
+		{name: "SS3 function keys", input: "\x1bOP\x1bOQ\x1bOR\x1bOS", want: []string{inputF1, inputF2, inputF3, inputF4}},
+		{name: "CSI function keys", input: "\x1b[11~\x1b[12~\x1b[13~\x1b[14~", want: []string{inputF1, inputF2, inputF3, inputF4}},
+		{name: "unknown sequence", input: "\x1b[15~h", want: []string{"h"}},
+		{name: "lone escape", input: "\x1b", want: []string{inputEsc}},
+		{name: "escape at the end", input: "h\x1b", want: []string{"h", inputEsc}},
+		{name: "escape before a character", input: "\x1bh", want: []string{inputEsc, "h"}},
+		{name: "truncated sequence", input: "\x1b[1;", want: nil},
+		{name: "control characters", input: "\r\n\x7f\x08\t\x03\x01", want: []string{
+			inputEnter, inputEnter, inputBackspace, inputBackspace, inputTab, inputCtrlC,
+		}},
+		{name: "UTF-8", input: "aé€😀", want: []string{"a", "é", "€", "😀"}},
+		{name: "invalid UTF-8", input: "a\xffb", want: []string{"a", "b"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := decodeInput([]byte(test.input))
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("decodeInput(%q) = %q, want %q", test.input, got, test.want)
+			}
+		})
+	}
+}
+
+func TestDecodeEscapeSequence(t *testing.T) {
+	tests := []struct {
+		input     string
+		wantLen   int
+		wantInput string
+	}{
+		{input: "\x1b[Ax", wantLen: 3, wantInput: inputUp},
+		{input: "\x1bOPx", wantLen: 3, wantInput: inputF1},
+		{input: "\x1b[14~x", wantLen: 5, wantInput: inputF4},
+		{input: "\x1b[1;2Dx", wantLen: 6, wantInput: inputLeft},
+		{input: "\x1b[200~x", wantLen: 6, wantInput: ""},
+		{input: "\x1b[12", wantLen: 4, wantInput: ""},
+	}
+
+	for _, test := range tests {
+		n, input := decodeEscapeSequence([]byte(test.input))
+		if n != test.wantLen || input != test.wantInput {
+			t.Errorf("decodeEscapeSequence(%q) = %d, %q, want %d, %q", test.input, n, input, test.wantLen, test.wantInput)
+		}
+	}
+}
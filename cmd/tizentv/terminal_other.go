@@ -0,0 +1,22 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd
+
+package main
+
+import (
+	"errors"
+)
+
+type terminal struct{}
+
+func openTerminal(fd int) (*terminal, error) {
+	return nil, errors.New("the remote is not supported on this platform")
+}
+
+func (t *terminal) restore() error {
+	return nil
+}
+
+func (t *terminal) size() (int, int) {
+	return 80, 24
+}
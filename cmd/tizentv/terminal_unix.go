@@ -0,0 +1,58 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+// +build darwin dragonfly freebsd linux netbsd openbsd
+
+package main
+
+import (
+	"golang.org/x/sys/unix"
+)
+
+const (
+	defaultTerminalWidth  = 80
+	defaultTerminalHeight = 24
+)
+
+// terminal puts a terminal into raw mode, so that keys are read one by one
+// without being echoed, and restores it afterwards.
+type terminal struct {
+	fd    int
+	state unix.Termios
+}
+
+func openTerminal(fd int) (*terminal, error) {
+	state, err := unix.IoctlGetTermios(fd, ioctlReadTermios)
+	if err != nil {
+		return nil, err
+	}
+
+	raw := *state
+	raw.Iflag &^= unix.IGNBRK | unix.BRKINT | unix.PARMRK | unix.ISTRIP | unix.INLCR | unix.IGNCR | unix.ICRNL | unix.IXON
+	raw.Oflag &^= unix.OPOST
+	raw.Lflag &^= unix.ECHO | unix.ECHONL | unix.ICANON | unix.ISIG | unix.IEXTEN
+	raw.Cflag &^= unix.CSIZE | unix.PARENB
+	raw.Cflag |= unix.CS8
+	raw.Cc[unix.VMIN] = 1
+	raw.Cc[unix.VTIME] = 0
+
+	err = unix.IoctlSetTermios(fd, ioctlWriteTermios, &raw)
+	if err != nil {
+		return nil, err
+	}
+
+	return &terminal{fd: fd, state: *state}, nil
+}
+
+func (t *terminal) restore() error {
+	return unix.IoctlSetTermios(t.fd, ioctlWriteTermios, &t.state)
+}
+
+// size returns the width and height of the terminal, or the size of a classic
+// one when it is unknown.
+func (t *terminal) size() (int, int) {
+	size, err := unix.IoctlGetWinsize(t.fd, unix.TIOCGWINSZ)
+	if err != nil || size.Col == 0 || size.Row == 0 {
+		return defaultTerminalWidth, defaultTerminalHeight
+	}
+
+	return int(size.Col), int(size.Row)
+}
@@ -0,0 +1,53 @@
+// Command wolrelay rebroadcasts Wake-on-LAN packets for authenticated requests.
+// Run it on the network of the TVs and point the "relay" of their device
+// config to it.
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/kpeu3i/go-tizen-tv/tizenapi"
+	"github.com/kpeu3i/go-tizen-tv/wolrelay"
+)
+
+func main() {
+	httpAddress := flag.String("http", ":9009", "HTTP listen address, empty to disable")
+	udpAddress := flag.String("udp", "", "UDP listen address, empty to disable")
+	subnet := flag.String("subnet", "255.255.255.255", "broadcast address packets are sent to besides the interfaces")
+	flag.Parse()
+
+	secret := os.Getenv("WOLRELAY_SECRET")
+	if secret == "" {
+		log.Fatal("WOLRELAY_SECRET is not set")
+	}
+
+	server := wolrelay.NewServer(
+		[]byte(secret),
+		wolrelay.WithHTTPListenAddress(*httpAddress),
+		wolrelay.WithUDPListenAddress(*udpAddress),
+		wolrelay.WithUDPAPIOptions(tizenapi.WithUDPSubnet(*subnet)),
+		wolrelay.WithErrorHandler(func(err error) {
+			log.Printf("request error: %s", err)
+		}),
+	)
+
+	err := server.Start()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	log.Printf("listening on http=%v udp=%v", server.HTTPAddr(), server.UDPAddr())
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	<-signals
+
+	err = server.Close()
+	if err != nil {
+		log.Fatal(err)
+	}
+}
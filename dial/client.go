@@ -0,0 +1,319 @@
+package dial
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	AppStateRunning = "running"
+	AppStateStopped = "stopped"
+	AppStateHidden  = "hidden"
+
+	applicationURLHeader   = "Application-URL"
+	defaultDialTimeout     = 1 * time.Second
+	defaultRequestTimeout  = 10 * time.Second
+	defaultContentType     = "text/plain; charset=utf-8"
+	installableStatePrefix = "installable="
+)
+
+var ErrAppNotFound = errors.New("app not found")
+
+type Option func(*Client)
+
+func WithDialTimeout(timeout time.Duration) Option {
+	return func(client *Client) {
+		client.dialTimeout = timeout
+	}
+}
+
+func WithRequestTimeout(timeout time.Duration) Option {
+	return func(client *Client) {
+		client.requestTimeout = timeout
+	}
+}
+
+// App is the state of an app reported by the DIAL server. InstallURL is set
+// when the app is not installed but can be.
+type App struct {
+	Name           string
+	State          string
+	AllowStop      bool
+	InstanceURL    string
+	InstallURL     string
+	AdditionalData string
+}
+
+func (a App) IsRunning() bool {
+	return a.State == AppStateRunning
+}
+
+func (a App) IsInstallable() bool {
+	return a.InstallURL != ""
+}
+
+type appResponse struct {
+	XMLName xml.Name `xml:"service"`
+	Name    string   `xml:"name"`
+	Options struct {
+		AllowStop string `xml:"allowStop,attr"`
+	} `xml:"options"`
+	State string `xml:"state"`
+	Link  struct {
+		Rel  string `xml:"rel,attr"`
+		Href string `xml:"href,attr"`
+	} `xml:"link"`
+	AdditionalData struct {
+		InnerXML string `xml:",innerxml"`
+	} `xml:"additionalData"`
+}
+
+// Client talks to a DIAL server. The application URL is the base URL the app
+// names are appended to, e.g. "http://192.168.1.2:8080/ws/apps/".
+type Client struct {
+	applicationURL string
+	dialTimeout    time.Duration
+	requestTimeout time.Duration
+	httpClient     *http.Client
+}
+
+func NewClient(applicationURL string, options ...Option) *Client {
+	client := &Client{
+		applicationURL: applicationURL,
+		dialTimeout:    defaultDialTimeout,
+		requestTimeout: defaultRequestTimeout,
+	}
+
+	for _, option := range options {
+		option(client)
+	}
+
+	client.httpClient = newHTTPClient(client.dialTimeout, client.requestTimeout)
+
+	return client
+}
+
+func (c *Client) ApplicationURL() string {
+	return c.applicationURL
+}
+
+func (c *Client) GetApp(name string) (App, error) {
+	return c.GetAppContext(context.Background(), name)
+}
+
+func (c *Client) GetAppContext(ctx context.Context, name string) (App, error) {
+	appURL := c.appURL(name)
+
+	response, err := c.do(ctx, http.MethodGet, appURL, "", nil)
+	if err != nil {
+		return App{}, err
+	}
+
+	defer func() {
+		_ = response.Body.Close()
+	}()
+
+	if response.StatusCode == http.StatusNotFound {
+		return App{}, ErrAppNotFound
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return App{}, fmt.Errorf("invalid response status: %d", response.StatusCode)
+	}
+
+	data, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return App{}, err
+	}
+
+	return parseApp(data, appURL)
+}
+
+// LaunchApp starts the app with the payload as the POST body and returns the
+// URL of the started instance. The URL is empty when the server does not
+// report one, e.g. when the app was already running.
+func (c *Client) LaunchApp(name string, payload string) (string, error) {
+	return c.LaunchAppContext(context.Background(), name, payload)
+}
+
+func (c *Client) LaunchAppContext(ctx context.Context, name string, payload string) (string, error) {
+	appURL := c.appURL(name)
+
+	response, err := c.do(ctx, http.MethodPost, appURL, defaultContentType, []byte(payload))
+	if err != nil {
+		return "", err
+	}
+
+	defer func() {
+		_ = response.Body.Close()
+	}()
+
+	switch response.StatusCode {
+	case http.StatusCreated, http.StatusOK:
+	case http.StatusNotFound:
+		return "", ErrAppNotFound
+	default:
+		return "", fmt.Errorf("invalid response status: %d", response.StatusCode)
+	}
+
+	location := response.Header.Get("Location")
+	if location == "" {
+		return "", nil
+	}
+
+	return resolveURL(appURL+"/", location), nil
+}
+
+func (c *Client) StopApp(instanceURL string) error {
+	return c.StopAppContext(context.Background(), instanceURL)
+}
+
+func (c *Client) StopAppContext(ctx context.Context, instanceURL string) error {
+	response, err := c.do(ctx, http.MethodDelete, instanceURL, "", nil)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = response.Body.Close()
+	}()
+
+	switch response.StatusCode {
+	case http.StatusOK, http.StatusNoContent:
+		return nil
+	case http.StatusNotFound:
+		return ErrAppNotFound
+	default:
+		return fmt.Errorf("invalid response status: %d", response.StatusCode)
+	}
+}
+
+// DiscoverApplicationURL fetches a device description and returns the
+// Application-URL header which DIAL servers add to it.
+func DiscoverApplicationURL(ctx context.Context, location string, options ...Option) (string, error) {
+	client := NewClient("", options...)
+
+	response, err := client.do(ctx, http.MethodGet, location, "", nil)
+	if err != nil {
+		return "", err
+	}
+
+	defer func() {
+		_ = response.Body.Close()
+	}()
+
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("invalid response status: %d", response.StatusCode)
+	}
+
+	applicationURL := response.Header.Get(applicationURLHeader)
+	if applicationURL == "" {
+		return "", errors.New("device does not provide a DIAL application URL")
+	}
+
+	return applicationURL, nil
+}
+
+// WakeUpMAC returns the MAC address from the WAKEUP header which DIAL servers
+// supporting Wake-on-LAN add to their SSDP responses, or an empty string.
+func WakeUpMAC(header string) string {
+	for _, field := range strings.Split(header, ";") {
+		parts := strings.SplitN(strings.TrimSpace(field), "=", 2)
+		if len(parts) != 2 || !strings.EqualFold(parts[0], "MAC") {
+			continue
+		}
+
+		mac, err := net.ParseMAC(strings.TrimSpace(parts[1]))
+		if err != nil {
+			return ""
+		}
+
+		return mac.String()
+	}
+
+	return ""
+}
+
+func (c *Client) appURL(name string) string {
+	return strings.TrimSuffix(c.applicationURL, "/") + "/" + url.PathEscape(name)
+}
+
+func (c *Client) do(
+	ctx context.Context,
+	method string,
+	u string,
+	contentType string,
+	body []byte,
+) (*http.Response, error) {
+	request, err := http.NewRequest(method, u, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	if contentType != "" && len(body) > 0 {
+		request.Header.Set("Content-Type", contentType)
+	}
+
+	return c.httpClient.Do(request.WithContext(ctx))
+}
+
+func parseApp(data []byte, appURL string) (App, error) {
+	response := appResponse{}
+	err := xml.Unmarshal(data, &response)
+	if err != nil {
+		return App{}, err
+	}
+
+	app := App{
+		Name:           response.Name,
+		State:          strings.TrimSpace(response.State),
+		AllowStop:      response.Options.AllowStop != "false",
+		AdditionalData: strings.TrimSpace(response.AdditionalData.InnerXML),
+	}
+
+	if strings.HasPrefix(app.State, installableStatePrefix) {
+		app.InstallURL = strings.TrimPrefix(app.State, installableStatePrefix)
+	}
+
+	if response.Link.Rel == "run" && response.Link.Href != "" {
+		app.InstanceURL = resolveURL(appURL+"/", response.Link.Href)
+	}
+
+	return app, nil
+}
+
+func resolveURL(base string, ref string) string {
+	baseURL, err := url.Parse(base)
+	if err != nil {
+		return ref
+	}
+
+	refURL, err := url.Parse(ref)
+	if err != nil {
+		return ref
+	}
+
+	return baseURL.ResolveReference(refURL).String()
+}
+
+func newHTTPClient(dialTimeout time.Duration, requestTimeout time.Duration) *http.Client {
+	return &http.Client{
+		Timeout: requestTimeout,
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+				dialer := net.Dialer{Timeout: dialTimeout}
+
+				return dialer.DialContext(ctx, network, addr)
+			},
+		},
+	}
+}
@@ -0,0 +1,223 @@
+package dial
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+const (
+	testRunningApp = `<?xml version="1.0" encoding="UTF-8"?>
+<service xmlns="urn:dial-multiscreen-org:schemas:dial" dialVer="2.1">
+  <name>YouTube</name>
+  <options allowStop="true"/>
+  <state>running</state>
+  <link rel="run" href="run"/>
+  <additionalData><screenId>abc</screenId></additionalData>
+</service>`
+
+	testInstallableApp = `<?xml version="1.0" encoding="UTF-8"?>
+<service xmlns="urn:dial-multiscreen-org:schemas:dial">
+  <name>Netflix</name>
+  <options allowStop="false"/>
+  <state>installable=http://store.example.com/netflix</state>
+</service>`
+
+	testStoppedApp = `<?xml version="1.0" encoding="UTF-8"?>
+<service xmlns="urn:dial-multiscreen-org:schemas:dial">
+  <name>Spotify</name>
+  <state>stopped</state>
+</service>`
+)
+
+func TestClientGetApp(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/apps/YouTube", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(testRunningApp))
+	})
+	mux.HandleFunc("/apps/Netflix", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(testInstallableApp))
+	})
+	mux.HandleFunc("/apps/Spotify", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(testStoppedApp))
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := NewClient(server.URL + "/apps/")
+
+	tests := []struct {
+		name    string
+		want    App
+		wantErr error
+	}{
+		{
+			name: "YouTube",
+			want: App{
+				Name:           "YouTube",
+				State:          AppStateRunning,
+				AllowStop:      true,
+				InstanceURL:    server.URL + "/apps/YouTube/run",
+				AdditionalData: "<screenId>abc</screenId>",
+			},
+		},
+		{
+			name: "Netflix",
+			want: App{
+				Name:       "Netflix",
+				State:      "installable=http://store.example.com/netflix",
+				InstallURL: "http://store.example.com/netflix",
+			},
+		},
+		{
+			// Without options the app can be stopped.
+			name: "Spotify",
+			want: App{Name: "Spotify", State: AppStateStopped, AllowStop: true},
+		},
+		{
+			name:    "Missing",
+			wantErr: ErrAppNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			app, err := client.GetApp(test.name)
+			if err != test.wantErr {
+				t.Fatalf("GetApp() = %v, want %v", err, test.wantErr)
+			}
+
+			if app != test.want {
+				t.Errorf("GetApp() = %+v, want %+v", app, test.want)
+			}
+
+			if app.IsInstallable() != (test.want.InstallURL != "") || app.IsRunning() != (test.want.State == AppStateRunning) {
+				t.Errorf("IsInstallable() = %t, IsRunning() = %t", app.IsInstallable(), app.IsRunning())
+			}
+		})
+	}
+}
+
+func TestClientLaunchApp(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		location string
+		want     string
+		wantErr  error
+	}{
+		{name: "relative location", status: http.StatusCreated, location: "run", want: "/apps/YouTube/run"},
+		{name: "absolute path", status: http.StatusCreated, location: "/apps/YouTube/1", want: "/apps/YouTube/1"},
+		{name: "already running", status: http.StatusOK},
+		{name: "not found", status: http.StatusNotFound, wantErr: ErrAppNotFound},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var body, contentType string
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost || r.URL.Path != "/apps/YouTube" {
+					http.NotFound(w, r)
+
+					return
+				}
+
+				data, _ := ioutil.ReadAll(r.Body)
+
+				mu.Lock()
+				body = string(data)
+				contentType = r.Header.Get("Content-Type")
+				mu.Unlock()
+
+				if test.location != "" {
+					w.Header().Set("Location", test.location)
+				}
+
+				w.WriteHeader(test.status)
+			}))
+			defer server.Close()
+
+			instanceURL, err := NewClient(server.URL+"/apps").LaunchApp("YouTube", "v=dQw4w9WgXcQ")
+			if err != test.wantErr {
+				t.Fatalf("LaunchApp() = %v, want %v", err, test.wantErr)
+			}
+
+			want := test.want
+			if want != "" {
+				want = server.URL + want
+			}
+
+			if instanceURL != want {
+				t.Errorf("LaunchApp() = %q, want %q", instanceURL, want)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if body != "v=dQw4w9WgXcQ" || contentType != defaultContentType {
+				t.Errorf("request body = %q (%s), want the payload as %s", body, contentType, defaultContentType)
+			}
+		})
+	}
+}
+
+func TestDiscoverApplicationURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		header  string
+		want    string
+		wantErr bool
+	}{
+		{name: "DIAL server", status: http.StatusOK, header: "http://192.0.2.1:8080/ws/apps/", want: "http://192.0.2.1:8080/ws/apps/"},
+		{name: "no header", status: http.StatusOK, wantErr: true},
+		{name: "error status", status: http.StatusInternalServerError, header: "http://192.0.2.1:8080/ws/apps/", wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if test.header != "" {
+					w.Header().Set(applicationURLHeader, test.header)
+				}
+
+				w.WriteHeader(test.status)
+			}))
+			defer server.Close()
+
+			applicationURL, err := DiscoverApplicationURL(context.Background(), server.URL+"/dd.xml")
+			if (err != nil) != test.wantErr {
+				t.Fatalf("DiscoverApplicationURL() = %v, want an error: %t", err, test.wantErr)
+			}
+
+			if applicationURL != test.want {
+				t.Errorf("DiscoverApplicationURL() = %q, want %q", applicationURL, test.want)
+			}
+		})
+	}
+}
+
+func TestWakeUpMAC(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{header: "MAC=10:dd:b1:c9:00:e4;Timeout=10", want: "10:dd:b1:c9:00:e4"},
+		{header: "Timeout=10; mac=10-DD-B1-C9-00-E4", want: "10:dd:b1:c9:00:e4"},
+		{header: "MAC=invalid;Timeout=10", want: ""},
+		{header: "Timeout=10", want: ""},
+		{header: "", want: ""},
+	}
+
+	for _, test := range tests {
+		if got := WakeUpMAC(test.header); got != test.want {
+			t.Errorf("WakeUpMAC(%q) = %q, want %q", test.header, got, test.want)
+		}
+	}
+}
@@ -5,5 +5,6 @@ go 1.12
 require (
 	github.com/gorilla/websocket v1.4.1
 	github.com/koron/go-ssdp v0.0.2
+	golang.org/x/sys v0.0.0-20200323222414-85ca7c5b95cd
 	gopkg.in/yaml.v3 v3.0.0-20210107192922-496545a6307b
 )
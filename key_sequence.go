@@ -1,7 +1,12 @@
 package samsung
 
 import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
+	"unicode"
 )
 
 type keyAction int
@@ -80,3 +85,79 @@ func (k *KeySequence) Repeat(n int) *KeySequence {
 
 	return k
 }
+
+// ParseKey returns the key with the given name. The name is case-insensitive
+// and the "KEY_" prefix may be omitted, so "volup" is KEY_VOLUP.
+func ParseKey(name string) Key {
+	name = strings.ToUpper(strings.TrimSpace(name))
+	if !strings.HasPrefix(name, "KEY_") {
+		name = "KEY_" + name
+	}
+
+	return Key(name)
+}
+
+// ParseKeySequence parses a sequence of keys separated by commas or spaces.
+// A key is clicked, "+key" presses and "-key" releases it, "key*3" clicks it
+// three times and a duration such as "2s" sets the wait after the previous
+// key, e.g. "home, right*3 1s, enter".
+func ParseKeySequence(s string) (KeySequence, error) {
+	tokens := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	})
+
+	sequence := KeySequence{}
+	for _, token := range tokens {
+		if token[0] >= '0' && token[0] <= '9' {
+			wait, err := time.ParseDuration(token)
+			if err != nil {
+				return nil, fmt.Errorf("invalid wait %q: %s", token, err)
+			}
+
+			if len(sequence) == 0 {
+				return nil, fmt.Errorf("wait %q is not preceded by a key", token)
+			}
+
+			sequence.Wait(wait)
+
+			continue
+		}
+
+		switch token[0] {
+		case '+':
+			if len(token) == 1 {
+				return nil, fmt.Errorf("invalid key %q", token)
+			}
+
+			sequence.Press(ParseKey(token[1:]))
+		case '-':
+			if len(token) == 1 {
+				return nil, fmt.Errorf("invalid key %q", token)
+			}
+
+			sequence.Release(ParseKey(token[1:]))
+		default:
+			name, count := token, 1
+			if i := strings.LastIndexByte(token, '*'); i >= 0 {
+				n, err := strconv.Atoi(token[i+1:])
+				if err != nil || n < 1 {
+					return nil, fmt.Errorf("invalid repeat count in %q", token)
+				}
+
+				name, count = token[:i], n
+			}
+
+			if name == "" {
+				return nil, fmt.Errorf("invalid key %q", token)
+			}
+
+			sequence.Click(ParseKey(name)).Repeat(count - 1)
+		}
+	}
+
+	if len(sequence) == 0 {
+		return nil, errors.New("key sequence is empty")
+	}
+
+	return sequence, nil
+}
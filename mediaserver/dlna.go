@@ -0,0 +1,66 @@
+package mediaserver
+
+import (
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	dlnaTransferModeHeader    = "transferMode.dlna.org"
+	dlnaContentFeaturesHeader = "contentFeatures.dlna.org"
+	dlnaTransferModeStreaming = "Streaming"
+	dlnaTransferModeInteract  = "Interactive"
+
+	// Byte seek supported, not transcoded, streaming with DLNA 1.5 flags.
+	dlnaContentFeatures = "DLNA.ORG_OP=01;DLNA.ORG_CI=0;DLNA.ORG_FLAGS=01700000000000000000000000000000"
+)
+
+var mimeTypes = map[string]string{
+	".mp4":  "video/mp4",
+	".m4v":  "video/mp4",
+	".mkv":  "video/x-matroska",
+	".webm": "video/webm",
+	".avi":  "video/x-msvideo",
+	".mov":  "video/quicktime",
+	".ts":   "video/mp2t",
+	".mpg":  "video/mpeg",
+	".mpeg": "video/mpeg",
+	".wmv":  "video/x-ms-wmv",
+	".mp3":  "audio/mpeg",
+	".m4a":  "audio/mp4",
+	".aac":  "audio/aac",
+	".flac": "audio/flac",
+	".wav":  "audio/wav",
+	".ogg":  "audio/ogg",
+	".jpg":  "image/jpeg",
+	".jpeg": "image/jpeg",
+	".png":  "image/png",
+	".gif":  "image/gif",
+}
+
+// MIMEType guesses the MIME type of a media file by its extension.
+func MIMEType(name string) string {
+	ext := strings.ToLower(filepath.Ext(name))
+	if mimeType, ok := mimeTypes[ext]; ok {
+		return mimeType
+	}
+
+	mimeType := mime.TypeByExtension(ext)
+	if mimeType != "" {
+		return mimeType
+	}
+
+	return "application/octet-stream"
+}
+
+func setDLNAHeaders(header http.Header, mimeType string) {
+	transferMode := dlnaTransferModeStreaming
+	if strings.HasPrefix(mimeType, "image/") {
+		transferMode = dlnaTransferModeInteract
+	}
+
+	header.Set(dlnaTransferModeHeader, transferMode)
+	header.Set(dlnaContentFeaturesHeader, dlnaContentFeatures)
+}
@@ -0,0 +1,339 @@
+package mediaserver
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kpeu3i/go-tizen-tv/upnp"
+)
+
+const (
+	defaultListenAddress   = ":0"
+	defaultShutdownTimeout = 5 * time.Second
+	mediaPathPrefix        = "/media/"
+)
+
+var ErrMediaNotFound = errors.New("media not found")
+
+type Option func(server *Server)
+
+// WithListenAddress sets the address the server listens on. By default it
+// listens on all interfaces on a random port.
+func WithListenAddress(address string) Option {
+	return func(server *Server) {
+		server.listenAddress = address
+	}
+}
+
+// WithAdvertisedHost sets the host put into media URLs instead of the address
+// of the interface which routes to the TV.
+func WithAdvertisedHost(host string) Option {
+	return func(server *Server) {
+		server.advertisedHost = host
+	}
+}
+
+type Media struct {
+	ID       string
+	Name     string
+	MIMEType string
+	Size     int64
+	ModTime  time.Time
+}
+
+type media struct {
+	Media
+	path   string
+	reader io.ReadSeeker
+	mu     sync.Mutex
+}
+
+// Server is an HTTP media server which serves published files and readers to
+// DLNA renderers. Range requests are supported, so renderers can seek.
+type Server struct {
+	listenAddress  string
+	advertisedHost string
+	mu             sync.Mutex
+	listener       net.Listener
+	httpServer     *http.Server
+	media          map[string]*media
+}
+
+func NewServer(options ...Option) *Server {
+	server := &Server{
+		listenAddress: defaultListenAddress,
+		media:         map[string]*media{},
+	}
+
+	for _, option := range options {
+		option(server)
+	}
+
+	return server
+}
+
+// Start starts listening. Calling Start on a started server does nothing.
+func (s *Server) Start() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.listener != nil {
+		return nil
+	}
+
+	listener, err := net.Listen("tcp", s.listenAddress)
+	if err != nil {
+		return err
+	}
+
+	httpServer := &http.Server{Handler: http.HandlerFunc(s.handle)}
+	s.listener = listener
+	s.httpServer = httpServer
+
+	go func() {
+		_ = httpServer.Serve(listener)
+	}()
+
+	return nil
+}
+
+func (s *Server) IsStarted() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.listener != nil
+}
+
+// Port returns the port the server listens on or an empty string when it is
+// not started.
+func (s *Server) Port() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.listener == nil {
+		return ""
+	}
+
+	_, port, _ := net.SplitHostPort(s.listener.Addr().String())
+
+	return port
+}
+
+func (s *Server) PublishFile(path string) (Media, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return Media{}, err
+	}
+
+	if info.IsDir() {
+		return Media{}, fmt.Errorf("%s is a directory", path)
+	}
+
+	m := &media{
+		Media: Media{
+			Name:     filepath.Base(path),
+			MIMEType: MIMEType(path),
+			Size:     info.Size(),
+			ModTime:  info.ModTime(),
+		},
+		path: path,
+	}
+
+	return s.publish(m)
+}
+
+// PublishReader publishes a reader as media. The reader is shared by all
+// requests, reads are serialized unless it implements io.ReaderAt.
+func (s *Server) PublishReader(name string, mimeType string, reader io.ReadSeeker) (Media, error) {
+	size, err := reader.Seek(0, io.SeekEnd)
+	if err != nil {
+		return Media{}, err
+	}
+
+	_, err = reader.Seek(0, io.SeekStart)
+	if err != nil {
+		return Media{}, err
+	}
+
+	if mimeType == "" {
+		mimeType = MIMEType(name)
+	}
+
+	m := &media{
+		Media: Media{
+			Name:     name,
+			MIMEType: mimeType,
+			Size:     size,
+			ModTime:  time.Now(),
+		},
+		reader: reader,
+	}
+
+	return s.publish(m)
+}
+
+func (s *Server) Unpublish(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.media, id)
+}
+
+// URL returns the URL of the media as seen from the remote host. The host of
+// the URL is the address of the local interface which routes to it.
+func (s *Server) URL(id string, remoteHost string) (string, error) {
+	s.mu.Lock()
+	m, ok := s.media[id]
+	listener := s.listener
+	s.mu.Unlock()
+
+	if !ok {
+		return "", ErrMediaNotFound
+	}
+
+	if listener == nil {
+		return "", errors.New("media server is not started")
+	}
+
+	host := s.advertisedHost
+	if host == "" {
+		ip, err := upnp.LocalIP(remoteHost)
+		if err != nil {
+			return "", err
+		}
+
+		host = ip.String()
+	}
+
+	_, port, err := net.SplitHostPort(listener.Addr().String())
+	if err != nil {
+		return "", err
+	}
+
+	u := url.URL{
+		Scheme: "http",
+		Host:   net.JoinHostPort(host, port),
+		Path:   mediaPathPrefix + m.ID + "/" + m.Name,
+	}
+
+	return u.String(), nil
+}
+
+func (s *Server) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultShutdownTimeout)
+	defer cancel()
+
+	return s.CloseContext(ctx)
+}
+
+func (s *Server) CloseContext(ctx context.Context) error {
+	s.mu.Lock()
+	httpServer := s.httpServer
+	s.listener = nil
+	s.httpServer = nil
+	s.mu.Unlock()
+
+	if httpServer == nil {
+		return nil
+	}
+
+	return httpServer.Shutdown(ctx)
+}
+
+func (s *Server) publish(m *media) (Media, error) {
+	id, err := generateID()
+	if err != nil {
+		return Media{}, err
+	}
+
+	m.ID = id
+
+	s.mu.Lock()
+	s.media[id] = m
+	s.mu.Unlock()
+
+	return m.Media, nil
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+
+		return
+	}
+
+	id := strings.TrimPrefix(r.URL.Path, mediaPathPrefix)
+	if id == r.URL.Path {
+		http.NotFound(w, r)
+
+		return
+	}
+
+	if i := strings.Index(id, "/"); i >= 0 {
+		id = id[:i]
+	}
+
+	s.mu.Lock()
+	m, ok := s.media[id]
+	s.mu.Unlock()
+
+	if !ok {
+		http.NotFound(w, r)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", m.MIMEType)
+	setDLNAHeaders(w.Header(), m.MIMEType)
+
+	if m.path != "" {
+		file, err := os.Open(m.path)
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+
+			return
+		}
+
+		defer func() {
+			_ = file.Close()
+		}()
+
+		http.ServeContent(w, r, m.Name, m.ModTime, file)
+
+		return
+	}
+
+	if readerAt, ok := m.reader.(io.ReaderAt); ok {
+		http.ServeContent(w, r, m.Name, m.ModTime, io.NewSectionReader(readerAt, 0, m.Size))
+
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	http.ServeContent(w, r, m.Name, m.ModTime, m.reader)
+}
+
+func generateID() (string, error) {
+	b := make([]byte, 8)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
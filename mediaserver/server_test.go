@@ -0,0 +1,213 @@
+package mediaserver
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+const testMediaContent = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+// seekOnlyReader hides io.ReaderAt, so that reads are serialized.
+type seekOnlyReader struct {
+	io.ReadSeeker
+}
+
+func startTestServer(t *testing.T) *Server {
+	t.Helper()
+
+	server := NewServer(WithListenAddress("127.0.0.1:0"), WithAdvertisedHost("127.0.0.1"))
+
+	err := server.Start()
+	if err != nil {
+		t.Fatalf("Start() = %v", err)
+	}
+
+	return server
+}
+
+func getRange(u string, byteRange string) (*http.Response, string, error) {
+	request, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, "", err
+	}
+
+	request.Header.Set("Range", byteRange)
+
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return nil, "", err
+	}
+
+	defer func() {
+		_ = response.Body.Close()
+	}()
+
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return response, string(body), nil
+}
+
+func TestServerServesRanges(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mediaserver")
+	if err != nil {
+		t.Fatalf("TempDir() = %v", err)
+	}
+
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+
+	path := filepath.Join(dir, "movie.mp4")
+
+	err = ioutil.WriteFile(path, []byte(testMediaContent), 0600)
+	if err != nil {
+		t.Fatalf("WriteFile() = %v", err)
+	}
+
+	server := startTestServer(t)
+	defer func() {
+		_ = server.Close()
+	}()
+
+	tests := []struct {
+		name             string
+		publish          func() (Media, error)
+		wantMIMEType     string
+		wantTransferMode string
+	}{
+		{
+			name:             "file",
+			publish:          func() (Media, error) { return server.PublishFile(path) },
+			wantMIMEType:     "video/mp4",
+			wantTransferMode: dlnaTransferModeStreaming,
+		},
+		{
+			name: "reader at",
+			publish: func() (Media, error) {
+				return server.PublishReader("song.mp3", "", bytes.NewReader([]byte(testMediaContent)))
+			},
+			wantMIMEType:     "audio/mpeg",
+			wantTransferMode: dlnaTransferModeStreaming,
+		},
+		{
+			name: "serialized reader",
+			publish: func() (Media, error) {
+				return server.PublishReader("photo.jpg", "", seekOnlyReader{bytes.NewReader([]byte(testMediaContent))})
+			},
+			wantMIMEType:     "image/jpeg",
+			wantTransferMode: dlnaTransferModeInteract,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m, err := test.publish()
+			if err != nil {
+				t.Fatalf("publish = %v", err)
+			}
+
+			u, err := server.URL(m.ID, "127.0.0.1")
+			if err != nil {
+				t.Fatalf("URL() = %v", err)
+			}
+
+			// Renderers seek with concurrent requests, each gets its own range.
+			ranges := []struct {
+				byteRange    string
+				wantBody     string
+				contentRange string
+			}{
+				{byteRange: "bytes=0-9", wantBody: testMediaContent[:10], contentRange: "bytes 0-9/36"},
+				{byteRange: "bytes=10-19", wantBody: testMediaContent[10:20], contentRange: "bytes 10-19/36"},
+				{byteRange: "bytes=-6", wantBody: testMediaContent[30:], contentRange: "bytes 30-35/36"},
+			}
+
+			var wg sync.WaitGroup
+
+			for _, r := range ranges {
+				r := r
+
+				wg.Add(1)
+
+				go func() {
+					defer wg.Done()
+
+					response, body, err := getRange(u, r.byteRange)
+					if err != nil {
+						t.Errorf("GET %s = %v", r.byteRange, err)
+
+						return
+					}
+
+					if response.StatusCode != http.StatusPartialContent {
+						t.Errorf("%s: status = %d, want %d", r.byteRange, response.StatusCode, http.StatusPartialContent)
+					}
+
+					if body != r.wantBody {
+						t.Errorf("%s: body = %q, want %q", r.byteRange, body, r.wantBody)
+					}
+
+					if got := response.Header.Get("Content-Range"); got != r.contentRange {
+						t.Errorf("%s: Content-Range = %q, want %q", r.byteRange, got, r.contentRange)
+					}
+
+					if got := response.Header.Get("Content-Type"); got != test.wantMIMEType {
+						t.Errorf("%s: Content-Type = %q, want %q", r.byteRange, got, test.wantMIMEType)
+					}
+
+					if got := response.Header.Get(dlnaTransferModeHeader); got != test.wantTransferMode {
+						t.Errorf("%s: %s = %q, want %q", r.byteRange, dlnaTransferModeHeader, got, test.wantTransferMode)
+					}
+
+					if got := response.Header.Get(dlnaContentFeaturesHeader); got != dlnaContentFeatures {
+						t.Errorf("%s: %s = %q, want %q", r.byteRange, dlnaContentFeaturesHeader, got, dlnaContentFeatures)
+					}
+				}()
+			}
+
+			wg.Wait()
+		})
+	}
+}
+
+func TestServerUnpublish(t *testing.T) {
+	server := startTestServer(t)
+	defer func() {
+		_ = server.Close()
+	}()
+
+	m, err := server.PublishReader("movie.mp4", "", bytes.NewReader([]byte(testMediaContent)))
+	if err != nil {
+		t.Fatalf("PublishReader() = %v", err)
+	}
+
+	u, err := server.URL(m.ID, "127.0.0.1")
+	if err != nil {
+		t.Fatalf("URL() = %v", err)
+	}
+
+	server.Unpublish(m.ID)
+
+	response, _, err := getRange(u, "bytes=0-9")
+	if err != nil {
+		t.Fatalf("GET = %v", err)
+	}
+
+	if response.StatusCode != http.StatusNotFound {
+		t.Errorf("status = %d, want %d", response.StatusCode, http.StatusNotFound)
+	}
+
+	_, err = server.URL(m.ID, "127.0.0.1")
+	if err != ErrMediaNotFound {
+		t.Errorf("URL() = %v, want %v", err, ErrMediaNotFound)
+	}
+}
@@ -1,6 +1,7 @@
 package ssdp
 
 import (
+	"context"
 	"time"
 
 	"github.com/koron/go-ssdp"
@@ -35,6 +36,9 @@ type Service struct {
 	USN      string
 	Location string
 	Server   string
+	// WakeUp is the WAKEUP header of DIAL servers which can be woken up, e.g.
+	// "MAC=10:dd:b1:c9:00:e4;Timeout=10".
+	WakeUp string
 }
 
 type Discoverer struct {
@@ -56,11 +60,35 @@ func NewDiscoverer(options ...Option) *Discoverer {
 }
 
 func (d *Discoverer) Discover() ([]Service, error) {
+	return d.DiscoverContext(context.Background())
+}
+
+func (d *Discoverer) DiscoverContext(ctx context.Context) ([]Service, error) {
+	type result struct {
+		response []ssdp.Service
+		err      error
+	}
+
 	sec := int(d.searchDuration / time.Second)
 
-	response, err := ssdp.Search(string(d.searchType), sec, "")
-	if err != nil {
-		return nil, err
+	// The underlying search is not cancellable, so it runs in the background and
+	// its result is dropped when the context is done first.
+	results := make(chan result, 1)
+	go func() {
+		response, err := ssdp.Search(string(d.searchType), sec, "")
+		results <- result{response: response, err: err}
+	}()
+
+	var response []ssdp.Service
+	select {
+	case r := <-results:
+		if r.err != nil {
+			return nil, r.err
+		}
+
+		response = r.response
+	case <-ctx.Done():
+		return nil, ctx.Err()
 	}
 
 	services := make([]Service, 0, len(response))
@@ -70,6 +98,7 @@ func (d *Discoverer) Discover() ([]Service, error) {
 			USN:      srv.USN,
 			Location: srv.Location,
 			Server:   srv.Server,
+			WakeUp:   srv.Header().Get("WAKEUP"),
 		}
 
 		services = append(services, service)
@@ -0,0 +1,72 @@
+package tizenapi
+
+import (
+	"encoding/json"
+)
+
+type ArtRequestMessage struct {
+	Method string `json:"method"`
+	Params struct {
+		Event string `json:"event"`
+		To    string `json:"to"`
+		Data  string `json:"data"`
+	} `json:"params"`
+}
+
+type ArtResponseData struct {
+	Event          string          `json:"event"`
+	ID             string          `json:"id"`
+	RequestID      string          `json:"request_id"`
+	Value          json.RawMessage `json:"value"`
+	Status         string          `json:"status"`
+	ValidValues    json.RawMessage `json:"valid_values"`
+	ErrorCode      json.RawMessage `json:"error_code"`
+	RequestData    string          `json:"request_data"`
+	ContentID      string          `json:"content_id"`
+	ContentList    string          `json:"content_list"`
+	CategoryID     string          `json:"category_id"`
+	Type           string          `json:"type"`
+	ConnInfo       string          `json:"conn_info"`
+	MatteTypeList  string          `json:"matte_type_list"`
+	MatteColorList string          `json:"matte_color_list"`
+	Raw            json.RawMessage `json:"-"`
+}
+
+type ArtContent struct {
+	ContentID       string      `json:"content_id"`
+	CategoryID      string      `json:"category_id"`
+	Width           json.Number `json:"width"`
+	Height          json.Number `json:"height"`
+	MatteID         string      `json:"matte_id"`
+	PortraitMatteID string      `json:"portrait_matte_id"`
+	ImageDate       string      `json:"image_date"`
+	ContentType     string      `json:"content_type"`
+}
+
+type ArtMatteList struct {
+	Types  []string
+	Colors []string
+}
+
+type ArtSlideshowStatus struct {
+	Value      string
+	CategoryID string
+	Type       string
+}
+
+type ArtConnInfo struct {
+	IP      string      `json:"ip"`
+	Port    json.Number `json:"port"`
+	Key     string      `json:"key"`
+	Secured bool        `json:"secured"`
+}
+
+type ArtUploadHeader struct {
+	Num        int    `json:"num"`
+	Total      int    `json:"total"`
+	FileLength int    `json:"fileLength"`
+	FileName   string `json:"fileName"`
+	FileType   string `json:"fileType"`
+	SecKey     string `json:"secKey"`
+	Version    string `json:"version"`
+}
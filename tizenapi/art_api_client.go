@@ -0,0 +1,467 @@
+package tizenapi
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	artChannel            = "com.samsung.art-app"
+	artRequestEvent       = "art_app_request"
+	artResponseEvent      = "d2d_service_message"
+	artResponseEventError = "error"
+
+	artModeChangedEvent              = "art_mode_changed"
+	artBrightnessChangedEvent        = "brightness_changed"
+	artColorTemperatureChangedEvent  = "color_temperature_changed"
+	artMotionTimerChangedEvent       = "motion_timer_changed"
+	artMotionSensitivityChangedEvent = "motion_sensitivity_changed"
+
+	defaultArtResponsesBufferSize = 8
+)
+
+type ArtEventHandler func(data ArtResponseData)
+
+type ArtAPIClient struct {
+	websocketClient *WebsocketAPIClient
+	mu              sync.Mutex
+	pending         map[string]chan ArtResponseData
+	watchers        map[string][]chan ArtResponseData
+	subscriptions   []artSubscription
+	subscriptionID  int
+}
+
+type artSubscription struct {
+	id      int
+	handler ArtEventHandler
+}
+
+func NewArtAPIClient(host string, clientID string, options ...WebsocketAPIOption) *ArtAPIClient {
+	options = append(options, WithWebsocketChannel(artChannel))
+
+	client := &ArtAPIClient{
+		websocketClient: NewWebsocketAPIClient(host, clientID, options...),
+		pending:         map[string]chan ArtResponseData{},
+		watchers:        map[string][]chan ArtResponseData{},
+	}
+
+	client.websocketClient.Subscribe(artResponseEvent, client.handleMessage)
+
+	return client
+}
+
+func (c *ArtAPIClient) WebsocketClient() *WebsocketAPIClient {
+	return c.websocketClient
+}
+
+func (c *ArtAPIClient) IsConnected() bool {
+	return c.websocketClient.IsConnected()
+}
+
+func (c *ArtAPIClient) Connect(token string) (ConnectResponseMessage, error) {
+	return c.ConnectContext(context.Background(), token)
+}
+
+func (c *ArtAPIClient) ConnectContext(ctx context.Context, token string) (ConnectResponseMessage, error) {
+	return c.websocketClient.ConnectContext(ctx, token)
+}
+
+// Subscribe registers a handler for art events which are not a reply to a
+// request, such as "art_mode_changed" sent when the mode is toggled on the TV.
+func (c *ArtAPIClient) Subscribe(handler ArtEventHandler) func() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.subscriptionID++
+	id := c.subscriptionID
+
+	c.subscriptions = append(c.subscriptions, artSubscription{id: id, handler: handler})
+
+	return func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		for i, subscription := range c.subscriptions {
+			if subscription.id == id {
+				c.subscriptions = append(c.subscriptions[:i], c.subscriptions[i+1:]...)
+
+				return
+			}
+		}
+	}
+}
+
+func (c *ArtAPIClient) GetArtModeStatus() (bool, error) {
+	return c.GetArtModeStatusContext(context.Background())
+}
+
+func (c *ArtAPIClient) GetArtModeStatusContext(ctx context.Context) (bool, error) {
+	value, err := c.getValue(ctx, "get_artmode_status")
+	if err != nil {
+		return false, err
+	}
+
+	return value == "on", nil
+}
+
+func (c *ArtAPIClient) SetArtModeStatus(on bool) error {
+	return c.SetArtModeStatusContext(context.Background(), on)
+}
+
+func (c *ArtAPIClient) SetArtModeStatusContext(ctx context.Context, on bool) error {
+	value := "off"
+	if on {
+		value = "on"
+	}
+
+	return c.Change(ctx, map[string]interface{}{"request": "set_artmode_status", "value": value}, artModeChangedEvent)
+}
+
+func (c *ArtAPIClient) GetBrightness() (int, error) {
+	return c.GetBrightnessContext(context.Background())
+}
+
+func (c *ArtAPIClient) GetBrightnessContext(ctx context.Context) (int, error) {
+	return c.getIntValue(ctx, "get_brightness")
+}
+
+func (c *ArtAPIClient) SetBrightness(value int) error {
+	return c.SetBrightnessContext(context.Background(), value)
+}
+
+func (c *ArtAPIClient) SetBrightnessContext(ctx context.Context, value int) error {
+	return c.setValue(ctx, "set_brightness", value, artBrightnessChangedEvent)
+}
+
+func (c *ArtAPIClient) GetColorTemperature() (int, error) {
+	return c.GetColorTemperatureContext(context.Background())
+}
+
+func (c *ArtAPIClient) GetColorTemperatureContext(ctx context.Context) (int, error) {
+	return c.getIntValue(ctx, "get_color_temperature")
+}
+
+func (c *ArtAPIClient) SetColorTemperature(value int) error {
+	return c.SetColorTemperatureContext(context.Background(), value)
+}
+
+func (c *ArtAPIClient) SetColorTemperatureContext(ctx context.Context, value int) error {
+	return c.setValue(ctx, "set_color_temperature", value, artColorTemperatureChangedEvent)
+}
+
+func (c *ArtAPIClient) GetMotionTimer() (string, error) {
+	return c.GetMotionTimerContext(context.Background())
+}
+
+func (c *ArtAPIClient) GetMotionTimerContext(ctx context.Context) (string, error) {
+	return c.getValue(ctx, "get_motion_timer")
+}
+
+func (c *ArtAPIClient) SetMotionTimer(value string) error {
+	return c.SetMotionTimerContext(context.Background(), value)
+}
+
+func (c *ArtAPIClient) SetMotionTimerContext(ctx context.Context, value string) error {
+	return c.setValue(ctx, "set_motion_timer", value, artMotionTimerChangedEvent)
+}
+
+func (c *ArtAPIClient) GetMotionSensitivity() (int, error) {
+	return c.GetMotionSensitivityContext(context.Background())
+}
+
+func (c *ArtAPIClient) GetMotionSensitivityContext(ctx context.Context) (int, error) {
+	return c.getIntValue(ctx, "get_motion_sensitivity")
+}
+
+func (c *ArtAPIClient) SetMotionSensitivity(value int) error {
+	return c.SetMotionSensitivityContext(context.Background(), value)
+}
+
+func (c *ArtAPIClient) SetMotionSensitivityContext(ctx context.Context, value int) error {
+	return c.setValue(ctx, "set_motion_sensitivity", value, artMotionSensitivityChangedEvent)
+}
+
+func (c *ArtAPIClient) Close() error {
+	return c.CloseContext(context.Background())
+}
+
+func (c *ArtAPIClient) CloseContext(ctx context.Context) error {
+	return c.websocketClient.CloseContext(ctx)
+}
+
+// Request sends a raw art app request and waits for the reply carrying the
+// same request ID. The "request" field of data selects the operation.
+func (c *ArtAPIClient) Request(ctx context.Context, data map[string]interface{}) (ArtResponseData, error) {
+	id, requestMessage, err := buildArtRequestMessage(data)
+	if err != nil {
+		return ArtResponseData{}, err
+	}
+
+	responses := c.register(id)
+	defer c.unregister(id)
+
+	err = c.websocketClient.send(ctx, requestMessage)
+	if err != nil {
+		return ArtResponseData{}, err
+	}
+
+	return c.await(ctx, responses)
+}
+
+// Change sends a raw art app request changing a setting and waits for the TV
+// to confirm it with the event. Some firmwares send the confirmation without
+// the request ID, so any event of that name is taken, while an error must
+// carry the request ID.
+func (c *ArtAPIClient) Change(ctx context.Context, data map[string]interface{}, event string) error {
+	id, requestMessage, err := buildArtRequestMessage(data)
+	if err != nil {
+		return err
+	}
+
+	responses := c.register(id)
+	defer c.unregister(id)
+
+	unwatch := c.watch(event, responses)
+	defer unwatch()
+
+	err = c.websocketClient.send(ctx, requestMessage)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.await(ctx, responses, event)
+
+	return err
+}
+
+// Emit sends a raw art app request without waiting for a reply.
+func (c *ArtAPIClient) Emit(ctx context.Context, data map[string]interface{}) error {
+	_, requestMessage, err := buildArtRequestMessage(data)
+	if err != nil {
+		return err
+	}
+
+	return c.websocketClient.send(ctx, requestMessage)
+}
+
+func (c *ArtAPIClient) register(id string) chan ArtResponseData {
+	responses := make(chan ArtResponseData, defaultArtResponsesBufferSize)
+
+	c.mu.Lock()
+	c.pending[id] = responses
+	c.mu.Unlock()
+
+	return responses
+}
+
+func (c *ArtAPIClient) unregister(id string) {
+	c.mu.Lock()
+	delete(c.pending, id)
+	c.mu.Unlock()
+}
+
+// watch delivers the events of the name to responses until the returned
+// function is called.
+func (c *ArtAPIClient) watch(event string, responses chan ArtResponseData) func() {
+	c.mu.Lock()
+	c.watchers[event] = append(c.watchers[event], responses)
+	c.mu.Unlock()
+
+	return func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		watchers := c.watchers[event]
+		for i, watcher := range watchers {
+			if watcher == responses {
+				c.watchers[event] = append(watchers[:i], watchers[i+1:]...)
+
+				break
+			}
+		}
+
+		if len(c.watchers[event]) == 0 {
+			delete(c.watchers, event)
+		}
+	}
+}
+
+// await waits for the next reply to a request. When events are given, replies
+// with other event names are skipped, because some operations report progress
+// through several messages sharing one request ID.
+func (c *ArtAPIClient) await(
+	ctx context.Context,
+	responses <-chan ArtResponseData,
+	events ...string,
+) (ArtResponseData, error) {
+	for {
+		select {
+		case response := <-responses:
+			if response.Event == artResponseEventError {
+				return ArtResponseData{}, fmt.Errorf("art request error: %s", string(response.ErrorCode))
+			}
+
+			if len(events) == 0 {
+				return response, nil
+			}
+
+			for _, event := range events {
+				if response.Event == event {
+					return response, nil
+				}
+			}
+		case <-c.websocketClient.clock.After(c.websocketClient.readTimeout):
+			return ArtResponseData{}, fmt.Errorf("response waiting timeout: %s", c.websocketClient.readTimeout)
+		case <-ctx.Done():
+			return ArtResponseData{}, ctx.Err()
+		}
+	}
+}
+
+func (c *ArtAPIClient) getValue(ctx context.Context, request string) (string, error) {
+	response, err := c.Request(ctx, map[string]interface{}{"request": request})
+	if err != nil {
+		return "", err
+	}
+
+	return decodeArtValue(response.Value)
+}
+
+func (c *ArtAPIClient) getIntValue(ctx context.Context, request string) (int, error) {
+	value, err := c.getValue(ctx, request)
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.Atoi(value)
+}
+
+func (c *ArtAPIClient) setValue(ctx context.Context, request string, value interface{}, event string) error {
+	return c.Change(ctx, map[string]interface{}{"request": request, "value": value}, event)
+}
+
+func (c *ArtAPIClient) handleMessage(message EventMessage) {
+	// The art app wraps its payload as a JSON string inside the data field.
+	var payload string
+	err := json.Unmarshal(message.Data, &payload)
+	if err != nil {
+		payload = string(message.Data)
+	}
+
+	data := ArtResponseData{}
+	err = json.Unmarshal([]byte(payload), &data)
+	if err != nil {
+		return
+	}
+
+	data.Raw = json.RawMessage(payload)
+
+	id := data.RequestID
+	if id == "" {
+		id = data.ID
+	}
+
+	if id == "" && data.RequestData != "" {
+		request := ArtResponseData{}
+		if json.Unmarshal([]byte(data.RequestData), &request) == nil {
+			id = request.RequestID
+			if id == "" {
+				id = request.ID
+			}
+		}
+	}
+
+	c.mu.Lock()
+	responses, isReply := c.pending[id]
+	watchers := append([]chan ArtResponseData(nil), c.watchers[data.Event]...)
+	subscriptions := append([]artSubscription(nil), c.subscriptions...)
+	c.mu.Unlock()
+
+	if isReply {
+		deliverArtResponse(responses, data)
+	}
+
+	for _, watcher := range watchers {
+		if watcher != responses {
+			deliverArtResponse(watcher, data)
+		}
+	}
+
+	// Change events tell subscribers about the new state even when they
+	// confirm a request.
+	if isReply && len(watchers) == 0 {
+		return
+	}
+
+	for _, subscription := range subscriptions {
+		subscription.handler(data)
+	}
+}
+
+func deliverArtResponse(responses chan<- ArtResponseData, data ArtResponseData) {
+	select {
+	case responses <- data:
+	default:
+	}
+}
+
+func buildArtRequestMessage(data map[string]interface{}) (string, []byte, error) {
+	id, err := generateUUID()
+	if err != nil {
+		return "", nil, err
+	}
+
+	data["id"] = id
+	data["request_id"] = id
+
+	requestData, err := json.Marshal(data)
+	if err != nil {
+		return "", nil, err
+	}
+
+	request := &ArtRequestMessage{}
+	request.Method = "ms.channel.emit"
+	request.Params.Event = artRequestEvent
+	request.Params.To = "host"
+	request.Params.Data = string(requestData)
+
+	requestMessage, err := json.Marshal(request)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return id, requestMessage, nil
+}
+
+func decodeArtValue(raw json.RawMessage) (string, error) {
+	if len(raw) == 0 {
+		return "", errors.New("art response has no value")
+	}
+
+	var value string
+	err := json.Unmarshal(raw, &value)
+	if err == nil {
+		return value, nil
+	}
+
+	return strings.Trim(string(raw), `"`), nil
+}
+
+func generateUUID() (string, error) {
+	b := make([]byte, 16)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
@@ -0,0 +1,207 @@
+package tizenapi_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kpeu3i/go-tizen-tv/tizenapi"
+	"github.com/kpeu3i/go-tizen-tv/tizentest"
+)
+
+func startArtServer(t *testing.T) (*tizentest.ArtServer, *tizenapi.ArtAPIClient, func()) {
+	t.Helper()
+
+	server := tizentest.NewArtServer()
+
+	err := server.Start()
+	if err != nil {
+		t.Fatalf("start art server: %v", err)
+	}
+
+	client := tizenapi.NewArtAPIClient(
+		server.Host(),
+		"tizentest",
+		tizenapi.WithWebsocketPort(server.Port()),
+		tizenapi.WithWebsocketReconnect(false),
+		tizenapi.WithWebsocketReadTimeout(2*time.Second),
+	)
+
+	_, err = client.Connect(server.Token)
+	if err != nil {
+		_ = server.Close()
+		t.Fatalf("connect: %v", err)
+	}
+
+	stop := func() {
+		_ = client.Close()
+		_ = server.Close()
+	}
+
+	return server, client, stop
+}
+
+func TestArtAPIClientSettings(t *testing.T) {
+	server, client, stop := startArtServer(t)
+	defer stop()
+
+	tests := []struct {
+		name  string
+		set   func() error
+		check func() bool
+	}{
+		{
+			name:  "art mode",
+			set:   func() error { return client.SetArtModeStatus(true) },
+			check: server.ArtMode,
+		},
+		{
+			name:  "brightness",
+			set:   func() error { return client.SetBrightness(7) },
+			check: func() bool { return server.Brightness() == 7 },
+		},
+		{
+			name:  "color temperature",
+			set:   func() error { return client.SetColorTemperature(-2) },
+			check: func() bool { return server.ColorTemperature() == -2 },
+		},
+		{
+			name:  "motion timer",
+			set:   func() error { return client.SetMotionTimer("15") },
+			check: func() bool { return server.MotionTimer() == "15" },
+		},
+		{
+			name:  "motion sensitivity",
+			set:   func() error { return client.SetMotionSensitivity(3) },
+			check: func() bool { return server.MotionSensitivity() == 3 },
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.set()
+			if err != nil {
+				t.Fatalf("set: %v", err)
+			}
+
+			if !test.check() {
+				t.Fatal("setting has not been changed")
+			}
+		})
+	}
+
+	on, err := client.GetArtModeStatus()
+	if err != nil || !on {
+		t.Errorf("GetArtModeStatus() = %v, %v, want true", on, err)
+	}
+
+	brightness, err := client.GetBrightness()
+	if err != nil || brightness != 7 {
+		t.Errorf("GetBrightness() = %d, %v, want 7", brightness, err)
+	}
+
+	sensitivity, err := client.GetMotionSensitivity()
+	if err != nil || sensitivity != 3 {
+		t.Errorf("GetMotionSensitivity() = %d, %v, want 3", sensitivity, err)
+	}
+}
+
+func TestArtAPIClientSettingRejected(t *testing.T) {
+	server, client, stop := startArtServer(t)
+	defer stop()
+
+	err := client.SetBrightness(42)
+	if err == nil {
+		t.Fatal("SetBrightness() succeeded for a rejected value")
+	}
+
+	if server.Brightness() != 5 {
+		t.Errorf("brightness = %d, want 5", server.Brightness())
+	}
+}
+
+func TestArtAPIClientSettingUnanswered(t *testing.T) {
+	server, client, stop := startArtServer(t)
+	defer stop()
+
+	server.DropRequests("set_motion_timer")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	err := client.SetMotionTimerContext(ctx, "30")
+	if err != context.DeadlineExceeded {
+		t.Fatalf("SetMotionTimerContext() = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestArtAPIClientSubscribe(t *testing.T) {
+	server, client, stop := startArtServer(t)
+	defer stop()
+
+	events := make(chan tizenapi.ArtResponseData, 2)
+	unsubscribe := client.Subscribe(func(data tizenapi.ArtResponseData) {
+		events <- data
+	})
+	defer unsubscribe()
+
+	server.SetArtMode(true)
+
+	select {
+	case data := <-events:
+		if data.Event != "art_mode_changed" || data.Status != "on" {
+			t.Errorf("event = %s %s, want art_mode_changed on", data.Event, data.Status)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("art mode change has not been received")
+	}
+
+	// A change requested by the client is reported to subscribers as well.
+	err := client.SetArtModeStatus(false)
+	if err != nil {
+		t.Fatalf("SetArtModeStatus() = %v", err)
+	}
+
+	select {
+	case data := <-events:
+		if data.Status != "off" {
+			t.Errorf("status = %s, want off", data.Status)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("art mode change has not been received")
+	}
+}
+
+func TestArtAPIClientUploadImage(t *testing.T) {
+	server, client, stop := startArtServer(t)
+	defer stop()
+
+	image := []byte("\x89PNG tizentest")
+
+	contentID, err := client.UploadImage(image, "png", "")
+	if err != nil {
+		t.Fatalf("UploadImage() = %v", err)
+	}
+
+	if string(server.Image(contentID)) != string(image) {
+		t.Errorf("stored image = %q, want %q", server.Image(contentID), image)
+	}
+
+	contents, err := client.GetContentList(tizenapi.ArtCategoryMyPhotos)
+	if err != nil {
+		t.Fatalf("GetContentList() = %v", err)
+	}
+
+	if len(contents) != 1 || contents[0].ContentID != contentID {
+		t.Errorf("GetContentList() = %+v, want %s", contents, contentID)
+	}
+
+	err = client.DeleteImages(contentID)
+	if err != nil {
+		t.Fatalf("DeleteImages() = %v", err)
+	}
+
+	if len(server.Contents()) != 0 {
+		t.Errorf("contents = %+v, want none", server.Contents())
+	}
+}
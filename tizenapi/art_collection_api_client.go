@@ -0,0 +1,393 @@
+package tizenapi
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"time"
+)
+
+const (
+	ArtCategoryMyPhotos  = "MY-C0002"
+	ArtCategoryFavorites = "MY-C0004"
+
+	defaultArtMatteID          = "none"
+	defaultArtPortraitMatteID  = "shadowbox_polar"
+	defaultArtUploadFileName   = "image"
+	defaultArtUploadVersion    = "0.0.1"
+	artUploadImageDateFormat   = "2006:01:02 15:04:05"
+	artUploadReadyEvent        = "ready_to_use"
+	artUploadImageAddedEvent   = "image_added"
+	artImageDeletedEvent       = "image_deleted"
+	artImageSelectedEvent      = "image_selected"
+	artMatteChangedEvent       = "matte_changed"
+	artFavoriteChangedEvent    = "favorite_changed"
+	artSlideshowChangedEvent   = "slideshow_changed"
+	artSlideshowShuffleType    = "shuffleslideshow"
+	artSlideshowSequentialType = "slideshow"
+)
+
+func (c *ArtAPIClient) GetContentList(category string) ([]ArtContent, error) {
+	return c.GetContentListContext(context.Background(), category)
+}
+
+func (c *ArtAPIClient) GetContentListContext(ctx context.Context, category string) ([]ArtContent, error) {
+	data := map[string]interface{}{"request": "get_content_list"}
+	if category != "" {
+		data["category"] = category
+	}
+
+	response, err := c.Request(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var contents []ArtContent
+	err = json.Unmarshal([]byte(response.ContentList), &contents)
+	if err != nil {
+		return nil, err
+	}
+
+	return contents, nil
+}
+
+func (c *ArtAPIClient) GetCurrentArtwork() (ArtContent, error) {
+	return c.GetCurrentArtworkContext(context.Background())
+}
+
+func (c *ArtAPIClient) GetCurrentArtworkContext(ctx context.Context) (ArtContent, error) {
+	response, err := c.Request(ctx, map[string]interface{}{"request": "get_current_artwork"})
+	if err != nil {
+		return ArtContent{}, err
+	}
+
+	content := ArtContent{}
+	err = json.Unmarshal(response.Raw, &content)
+	if err != nil {
+		return ArtContent{}, err
+	}
+
+	return content, nil
+}
+
+// UploadImage stores an image on the TV and returns its content ID. The image
+// is transferred over a secondary socket which the TV opens for the request.
+func (c *ArtAPIClient) UploadImage(image []byte, fileType string, matteID string) (string, error) {
+	return c.UploadImageContext(context.Background(), image, fileType, matteID)
+}
+
+func (c *ArtAPIClient) UploadImageContext(
+	ctx context.Context,
+	image []byte,
+	fileType string,
+	matteID string,
+) (string, error) {
+	if matteID == "" {
+		matteID = defaultArtMatteID
+	}
+
+	connectionID, err := rand.Int(rand.Reader, big.NewInt(4*1024*1024*1024))
+	if err != nil {
+		return "", err
+	}
+
+	connectionUUID, err := generateUUID()
+	if err != nil {
+		return "", err
+	}
+
+	data := map[string]interface{}{
+		"request":           "send_image",
+		"file_type":         fileType,
+		"image_date":        c.websocketClient.clock.Now().Format(artUploadImageDateFormat),
+		"matte_id":          matteID,
+		"portrait_matte_id": defaultArtPortraitMatteID,
+		"file_size":         len(image),
+		"conn_info": map[string]interface{}{
+			"d2d_mode":      "socket",
+			"connection_id": connectionID.Int64(),
+			"id":            connectionUUID,
+		},
+	}
+
+	id, requestMessage, err := buildArtRequestMessage(data)
+	if err != nil {
+		return "", err
+	}
+
+	responses := c.register(id)
+	defer c.unregister(id)
+
+	err = c.websocketClient.send(ctx, requestMessage)
+	if err != nil {
+		return "", err
+	}
+
+	response, err := c.await(ctx, responses, artUploadReadyEvent)
+	if err != nil {
+		return "", err
+	}
+
+	connInfo := ArtConnInfo{}
+	err = json.Unmarshal([]byte(response.ConnInfo), &connInfo)
+	if err != nil {
+		return "", err
+	}
+
+	err = connInfo.validate()
+	if err != nil {
+		return "", err
+	}
+
+	err = c.transferImage(ctx, connInfo, image, fileType)
+	if err != nil {
+		return "", err
+	}
+
+	response, err = c.await(ctx, responses, artUploadImageAddedEvent)
+	if err != nil {
+		return "", err
+	}
+
+	return response.ContentID, nil
+}
+
+func (c *ArtAPIClient) DeleteImages(contentIDs ...string) error {
+	return c.DeleteImagesContext(context.Background(), contentIDs...)
+}
+
+func (c *ArtAPIClient) DeleteImagesContext(ctx context.Context, contentIDs ...string) error {
+	if len(contentIDs) == 0 {
+		return nil
+	}
+
+	contentIDList := make([]map[string]string, 0, len(contentIDs))
+	for _, contentID := range contentIDs {
+		contentIDList = append(contentIDList, map[string]string{"content_id": contentID})
+	}
+
+	response, err := c.Request(ctx, map[string]interface{}{
+		"request":         "delete_image_list",
+		"content_id_list": contentIDList,
+	})
+	if err != nil {
+		return err
+	}
+
+	if response.Event != artImageDeletedEvent {
+		return fmt.Errorf("invalid response: %s", string(response.Raw))
+	}
+
+	return nil
+}
+
+func (c *ArtAPIClient) SelectImage(contentID string, show bool) error {
+	return c.SelectImageContext(context.Background(), contentID, show)
+}
+
+func (c *ArtAPIClient) SelectImageContext(ctx context.Context, contentID string, show bool) error {
+	data := map[string]interface{}{
+		"request":     "select_image",
+		"content_id":  contentID,
+		"category_id": nil,
+		"show":        show,
+	}
+
+	return c.Change(ctx, data, artImageSelectedEvent)
+}
+
+func (c *ArtAPIClient) GetMatteList() (ArtMatteList, error) {
+	return c.GetMatteListContext(context.Background())
+}
+
+func (c *ArtAPIClient) GetMatteListContext(ctx context.Context) (ArtMatteList, error) {
+	response, err := c.Request(ctx, map[string]interface{}{"request": "get_matte_list"})
+	if err != nil {
+		return ArtMatteList{}, err
+	}
+
+	var types []struct {
+		MatteType string `json:"matte_type"`
+	}
+
+	err = json.Unmarshal([]byte(response.MatteTypeList), &types)
+	if err != nil {
+		return ArtMatteList{}, err
+	}
+
+	var colors []struct {
+		Color string `json:"color"`
+	}
+
+	if response.MatteColorList != "" {
+		err = json.Unmarshal([]byte(response.MatteColorList), &colors)
+		if err != nil {
+			return ArtMatteList{}, err
+		}
+	}
+
+	matteList := ArtMatteList{}
+	for _, t := range types {
+		matteList.Types = append(matteList.Types, t.MatteType)
+	}
+
+	for _, color := range colors {
+		matteList.Colors = append(matteList.Colors, color.Color)
+	}
+
+	return matteList, nil
+}
+
+func (c *ArtAPIClient) ChangeMatte(contentID string, matteID string) error {
+	return c.ChangeMatteContext(context.Background(), contentID, matteID)
+}
+
+func (c *ArtAPIClient) ChangeMatteContext(ctx context.Context, contentID string, matteID string) error {
+	data := map[string]interface{}{
+		"request":    "change_matte",
+		"content_id": contentID,
+		"matte_id":   matteID,
+	}
+
+	return c.Change(ctx, data, artMatteChangedEvent)
+}
+
+func (c *ArtAPIClient) ChangeFavorite(contentID string, favorite bool) error {
+	return c.ChangeFavoriteContext(context.Background(), contentID, favorite)
+}
+
+func (c *ArtAPIClient) ChangeFavoriteContext(ctx context.Context, contentID string, favorite bool) error {
+	status := "off"
+	if favorite {
+		status = "on"
+	}
+
+	data := map[string]interface{}{
+		"request":    "change_favorite",
+		"content_id": contentID,
+		"status":     status,
+	}
+
+	return c.Change(ctx, data, artFavoriteChangedEvent)
+}
+
+func (c *ArtAPIClient) GetSlideshowStatus() (ArtSlideshowStatus, error) {
+	return c.GetSlideshowStatusContext(context.Background())
+}
+
+func (c *ArtAPIClient) GetSlideshowStatusContext(ctx context.Context) (ArtSlideshowStatus, error) {
+	response, err := c.Request(ctx, map[string]interface{}{"request": "get_slideshow_status"})
+	if err != nil {
+		return ArtSlideshowStatus{}, err
+	}
+
+	value, err := decodeArtValue(response.Value)
+	if err != nil {
+		return ArtSlideshowStatus{}, err
+	}
+
+	status := ArtSlideshowStatus{
+		Value:      value,
+		CategoryID: response.CategoryID,
+		Type:       response.Type,
+	}
+
+	return status, nil
+}
+
+// SetSlideshowStatus configures the slideshow. The value is "off" or the
+// interval in minutes between pieces.
+func (c *ArtAPIClient) SetSlideshowStatus(value string, categoryID string, shuffle bool) error {
+	return c.SetSlideshowStatusContext(context.Background(), value, categoryID, shuffle)
+}
+
+func (c *ArtAPIClient) SetSlideshowStatusContext(
+	ctx context.Context,
+	value string,
+	categoryID string,
+	shuffle bool,
+) error {
+	slideshowType := artSlideshowSequentialType
+	if shuffle {
+		slideshowType = artSlideshowShuffleType
+	}
+
+	data := map[string]interface{}{
+		"request":     "set_slideshow_status",
+		"value":       value,
+		"category_id": categoryID,
+		"type":        slideshowType,
+	}
+
+	return c.Change(ctx, data, artSlideshowChangedEvent)
+}
+
+func (c *ArtAPIClient) transferImage(ctx context.Context, connInfo ArtConnInfo, image []byte, fileType string) error {
+	header, err := json.Marshal(ArtUploadHeader{
+		Num:        0,
+		Total:      1,
+		FileLength: len(image),
+		FileName:   defaultArtUploadFileName,
+		FileType:   fileType,
+		SecKey:     connInfo.Key,
+		Version:    defaultArtUploadVersion,
+	})
+	if err != nil {
+		return err
+	}
+
+	if connInfo.IP == "" {
+		connInfo.IP = c.websocketClient.host
+	}
+
+	dialer := net.Dialer{Timeout: c.websocketClient.dialTimeout}
+
+	connection, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(connInfo.IP, connInfo.Port.String()))
+	if err != nil {
+		return err
+	}
+
+	if connInfo.Secured {
+		connection = tls.Client(connection, &tls.Config{InsecureSkipVerify: true})
+	}
+
+	defer func() {
+		_ = connection.Close()
+	}()
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(c.websocketClient.writeTimeout)
+	}
+
+	err = connection.SetWriteDeadline(deadline)
+	if err != nil {
+		return err
+	}
+
+	length := make([]byte, 4)
+	binary.BigEndian.PutUint32(length, uint32(len(header)))
+
+	for _, chunk := range [][]byte{length, header, image} {
+		_, err = connection.Write(chunk)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (d ArtConnInfo) validate() error {
+	if d.Port == "" {
+		return errors.New("art upload connection info has no port")
+	}
+
+	return nil
+}
@@ -0,0 +1,586 @@
+package tizenapi
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	EncryptedProtocol = "encrypted"
+
+	defaultEncryptedPort         = "8000"
+	defaultEncryptedDialTimeout  = 1 * time.Second
+	defaultEncryptedReadTimeout  = 30 * time.Second
+	defaultEncryptedWriteTimeout = 30 * time.Second
+	encryptedNamespace           = "/com.samsung.companion"
+	socketIOHeartbeat            = "2::"
+)
+
+var ErrEncryptedNotSupported = errors.New("not supported by the encrypted remote protocol")
+
+type EncryptedAPIOption func(*EncryptedAPIClient)
+
+func WithEncryptedPort(port string) EncryptedAPIOption {
+	return func(client *EncryptedAPIClient) {
+		client.port = port
+	}
+}
+
+func WithEncryptedDialTimeout(timeout time.Duration) EncryptedAPIOption {
+	return func(client *EncryptedAPIClient) {
+		client.dialTimeout = timeout
+	}
+}
+
+func WithEncryptedReadTimeout(timeout time.Duration) EncryptedAPIOption {
+	return func(client *EncryptedAPIClient) {
+		client.readTimeout = timeout
+	}
+}
+
+func WithEncryptedWriteTimeout(timeout time.Duration) EncryptedAPIOption {
+	return func(client *EncryptedAPIClient) {
+		client.writeTimeout = timeout
+	}
+}
+
+// WithEncryptedSession sets the credentials of an earlier pairing, so that
+// connecting does not ask for a PIN again.
+func WithEncryptedSession(session PairingSession) EncryptedAPIOption {
+	return func(client *EncryptedAPIClient) {
+		client.session = session
+	}
+}
+
+// WithEncryptedPairingOptions adds options of the pairing client used when
+// the client has no session yet.
+func WithEncryptedPairingOptions(options ...PairingAPIOption) EncryptedAPIOption {
+	return func(client *EncryptedAPIClient) {
+		client.pairingOptions = append(client.pairingOptions, options...)
+	}
+}
+
+type PairingSessionHandler func(session PairingSession) error
+
+// EncryptedAPIClient sends keys to 2014-2015 (H/J series) TVs. Commands are
+// AES encrypted with the session key and sent over socket.io on port 8000. The
+// client pairs on connect when it has no session yet and opens a new connection
+// with the session when the TV drops it. Apps, text input and the touchpad are
+// not part of the protocol and return ErrEncryptedNotSupported.
+type EncryptedAPIClient struct {
+	host           string
+	port           string
+	clientID       string
+	dialTimeout    time.Duration
+	readTimeout    time.Duration
+	writeTimeout   time.Duration
+	pairingOptions []PairingAPIOption
+	connectionMu   sync.Mutex
+	mu             sync.Mutex
+	writeMu        sync.Mutex
+	session        PairingSession
+	pinHandler     PINHandler
+	sessionHandler PairingSessionHandler
+	connection     *websocket.Conn
+	opened         bool
+}
+
+func NewEncryptedAPIClient(host string, clientID string, options ...EncryptedAPIOption) *EncryptedAPIClient {
+	client := &EncryptedAPIClient{
+		host:         host,
+		port:         defaultEncryptedPort,
+		clientID:     clientID,
+		dialTimeout:  defaultEncryptedDialTimeout,
+		readTimeout:  defaultEncryptedReadTimeout,
+		writeTimeout: defaultEncryptedWriteTimeout,
+	}
+
+	for _, option := range options {
+		option(client)
+	}
+
+	return client
+}
+
+func (c *EncryptedAPIClient) Protocol() string {
+	return EncryptedProtocol
+}
+
+func (c *EncryptedAPIClient) Host() string {
+	return c.host
+}
+
+func (c *EncryptedAPIClient) Port() string {
+	return c.port
+}
+
+func (c *EncryptedAPIClient) IsSecure() bool {
+	return false
+}
+
+func (c *EncryptedAPIClient) DialTimeout() time.Duration {
+	return c.dialTimeout
+}
+
+func (c *EncryptedAPIClient) ReadTimeout() time.Duration {
+	return c.readTimeout
+}
+
+func (c *EncryptedAPIClient) WriteTimeout() time.Duration {
+	return c.writeTimeout
+}
+
+func (c *EncryptedAPIClient) ClientID() string {
+	return c.clientID
+}
+
+func (c *EncryptedAPIClient) Session() PairingSession {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.session
+}
+
+// OnPINRequest sets the handler asked for the PIN shown on the TV when pairing.
+func (c *EncryptedAPIClient) OnPINRequest(handler PINHandler) {
+	c.mu.Lock()
+	c.pinHandler = handler
+	c.mu.Unlock()
+}
+
+// OnSession sets the handler called with the credentials of a new pairing.
+func (c *EncryptedAPIClient) OnSession(handler PairingSessionHandler) {
+	c.mu.Lock()
+	c.sessionHandler = handler
+	c.mu.Unlock()
+}
+
+func (c *EncryptedAPIClient) IsAvailable() bool {
+	return c.IsAvailableContext(context.Background())
+}
+
+func (c *EncryptedAPIClient) IsAvailableContext(ctx context.Context) bool {
+	dialer := net.Dialer{Timeout: c.dialTimeout}
+
+	connection, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(c.host, c.port))
+	if err != nil {
+		return false
+	}
+
+	defer func() {
+		_ = connection.Close()
+	}()
+
+	return true
+}
+
+func (c *EncryptedAPIClient) IsConnected() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.connection != nil
+}
+
+func (c *EncryptedAPIClient) IsReconnecting() bool {
+	return false
+}
+
+// Connect pairs when there is no session and opens the command connection. The
+// protocol has no tokens, the one given is ignored.
+func (c *EncryptedAPIClient) Connect(token string) (ConnectResponseMessage, error) {
+	return c.ConnectContext(context.Background(), token)
+}
+
+func (c *EncryptedAPIClient) ConnectContext(ctx context.Context, token string) (ConnectResponseMessage, error) {
+	c.connectionMu.Lock()
+	defer c.connectionMu.Unlock()
+
+	if c.IsConnected() {
+		return ConnectResponseMessage{}, errors.New("connection has been already opened")
+	}
+
+	c.mu.Lock()
+	c.opened = true
+	c.mu.Unlock()
+
+	err := c.pair(ctx)
+	if err == nil {
+		_, err = c.open(ctx)
+	}
+
+	if err != nil {
+		c.mu.Lock()
+		c.opened = false
+		c.mu.Unlock()
+
+		return ConnectResponseMessage{}, err
+	}
+
+	response := ConnectResponseMessage{Event: string(WebsocketEventChannelConnect)}
+	response.Data.ID = c.clientID
+
+	return response, nil
+}
+
+func (c *EncryptedAPIClient) GetApps() (GetAppsResponseMessage, error) {
+	return c.GetAppsContext(context.Background())
+}
+
+func (c *EncryptedAPIClient) GetAppsContext(ctx context.Context) (GetAppsResponseMessage, error) {
+	return GetAppsResponseMessage{}, ErrEncryptedNotSupported
+}
+
+func (c *EncryptedAPIClient) OpenApp(id string, actionType WebsocketOpenAppActionType, metaTag string) error {
+	return c.OpenAppContext(context.Background(), id, actionType, metaTag)
+}
+
+func (c *EncryptedAPIClient) OpenAppContext(
+	ctx context.Context,
+	id string,
+	actionType WebsocketOpenAppActionType,
+	metaTag string,
+) error {
+	return ErrEncryptedNotSupported
+}
+
+func (c *EncryptedAPIClient) SendKey(key string, state WebsocketKeyState) error {
+	return c.SendKeyContext(context.Background(), key, state)
+}
+
+func (c *EncryptedAPIClient) SendKeyContext(ctx context.Context, key string, state WebsocketKeyState) error {
+	command, err := encryptCommand(c.Session(), key, state)
+	if err != nil {
+		return err
+	}
+
+	c.connectionMu.Lock()
+	defer c.connectionMu.Unlock()
+
+	connection, err := c.activeConnection(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = c.write(ctx, connection, command)
+	if err == nil {
+		return nil
+	}
+
+	// The TV drops idle connections, a key is retried once on a new one.
+	c.dropConnection(connection)
+
+	connection, err = c.activeConnection(ctx)
+	if err != nil {
+		return err
+	}
+
+	return c.write(ctx, connection, command)
+}
+
+func (c *EncryptedAPIClient) SendMouseMove(dx, dy int) error {
+	return c.SendMouseMoveContext(context.Background(), dx, dy)
+}
+
+func (c *EncryptedAPIClient) SendMouseMoveContext(ctx context.Context, dx, dy int) error {
+	return ErrEncryptedNotSupported
+}
+
+func (c *EncryptedAPIClient) SendMouseClick(button WebsocketMouseButton) error {
+	return c.SendMouseClickContext(context.Background(), button)
+}
+
+func (c *EncryptedAPIClient) SendMouseClickContext(ctx context.Context, button WebsocketMouseButton) error {
+	return ErrEncryptedNotSupported
+}
+
+func (c *EncryptedAPIClient) SendInputString(text string) error {
+	return c.SendInputStringContext(context.Background(), text)
+}
+
+func (c *EncryptedAPIClient) SendInputStringContext(ctx context.Context, text string) error {
+	return ErrEncryptedNotSupported
+}
+
+func (c *EncryptedAPIClient) SendInputEnd() error {
+	return c.SendInputEndContext(context.Background())
+}
+
+func (c *EncryptedAPIClient) SendInputEndContext(ctx context.Context) error {
+	return ErrEncryptedNotSupported
+}
+
+// Subscribe never calls the handler, the protocol has no events.
+func (c *EncryptedAPIClient) Subscribe(event WebsocketEvent, handler WebsocketEventHandler) func() {
+	return func() {}
+}
+
+// OnReconnect never calls the handler, keys reconnect on their own.
+func (c *EncryptedAPIClient) OnReconnect(handler WebsocketReconnectHandler) {}
+
+func (c *EncryptedAPIClient) Close() error {
+	return c.CloseContext(context.Background())
+}
+
+func (c *EncryptedAPIClient) CloseContext(ctx context.Context) error {
+	c.mu.Lock()
+	connection := c.connection
+	c.connection = nil
+	c.opened = false
+	c.mu.Unlock()
+
+	if connection == nil {
+		return nil
+	}
+
+	return connection.Close()
+}
+
+// pair runs the PIN pairing when the client has no session yet.
+func (c *EncryptedAPIClient) pair(ctx context.Context) error {
+	c.mu.Lock()
+	session := c.session
+	pinHandler := c.pinHandler
+	sessionHandler := c.sessionHandler
+	c.mu.Unlock()
+
+	if !session.IsEmpty() {
+		return nil
+	}
+
+	paired, err := NewPairingAPIClient(c.host, c.pairingOptions...).Pair(ctx, pinHandler)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	c.session = paired
+	c.mu.Unlock()
+
+	if sessionHandler == nil {
+		return nil
+	}
+
+	return sessionHandler(paired)
+}
+
+// activeConnection returns the open connection. When the TV has dropped it, a
+// new one is opened with the session.
+func (c *EncryptedAPIClient) activeConnection(ctx context.Context) (*websocket.Conn, error) {
+	c.mu.Lock()
+	connection := c.connection
+	opened := c.opened
+	c.mu.Unlock()
+
+	if connection != nil {
+		return connection, nil
+	}
+
+	if !opened {
+		return nil, ErrWebsocketNotConnected
+	}
+
+	return c.open(ctx)
+}
+
+// open connects and starts the reader, unless the client has been closed in
+// the meantime.
+func (c *EncryptedAPIClient) open(ctx context.Context) (*websocket.Conn, error) {
+	connection, err := c.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.opened {
+		_ = connection.Close()
+
+		return nil, errors.New("connection has been closed")
+	}
+
+	c.connection = connection
+
+	go c.runReader(connection)
+
+	return connection, nil
+}
+
+func (c *EncryptedAPIClient) dropConnection(connection *websocket.Conn) {
+	c.mu.Lock()
+	if c.connection == connection {
+		c.connection = nil
+	}
+	c.mu.Unlock()
+
+	_ = connection.Close()
+}
+
+func (c *EncryptedAPIClient) connect(ctx context.Context) (*websocket.Conn, error) {
+	httpClient := &http.Client{
+		Timeout: c.readTimeout,
+		Transport: &http.Transport{
+			DialContext: (&net.Dialer{Timeout: c.dialTimeout}).DialContext,
+		},
+	}
+
+	handshakeURL := url.URL{
+		Scheme:   "http",
+		Host:     net.JoinHostPort(c.host, c.port),
+		Path:     "/socket.io/1/",
+		RawQuery: "t=" + strconv.FormatInt(time.Now().UnixNano()/int64(time.Millisecond), 10),
+	}
+
+	request, err := http.NewRequest(http.MethodGet, handshakeURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := httpClient.Do(request.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = response.Body.Close()
+	}()
+
+	data, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("invalid response status: %d", response.StatusCode)
+	}
+
+	sessionToken := strings.SplitN(string(data), ":", 2)[0]
+
+	websocketURL := url.URL{
+		Scheme: "ws",
+		Host:   net.JoinHostPort(c.host, c.port),
+		Path:   "/socket.io/1/websocket/" + sessionToken,
+	}
+
+	dialer := websocket.Dialer{HandshakeTimeout: c.dialTimeout}
+
+	connection, _, err := dialer.DialContext(ctx, websocketURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.write(ctx, connection, []byte("1::"+encryptedNamespace))
+	if err != nil {
+		_ = connection.Close()
+
+		return nil, err
+	}
+
+	return connection, nil
+}
+
+// runReader answers socket.io heartbeats until the connection breaks.
+func (c *EncryptedAPIClient) runReader(connection *websocket.Conn) {
+	defer c.dropConnection(connection)
+
+	for {
+		_, message, err := connection.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		if strings.HasPrefix(string(message), socketIOHeartbeat) {
+			err = c.write(context.Background(), connection, []byte(socketIOHeartbeat))
+			if err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (c *EncryptedAPIClient) write(ctx context.Context, connection *websocket.Conn, message []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(c.writeTimeout)
+	}
+
+	err := connection.SetWriteDeadline(deadline)
+	if err != nil {
+		return err
+	}
+
+	return connection.WriteMessage(websocket.TextMessage, message)
+}
+
+func encryptCommand(session PairingSession, key string, state WebsocketKeyState) ([]byte, error) {
+	command, err := json.Marshal(map[string]interface{}{
+		"method": "POST",
+		"body": map[string]interface{}{
+			"plugin":  "RemoteControl",
+			"param1":  "uuid:12345",
+			"param2":  string(state),
+			"param3":  key,
+			"param4":  false,
+			"api":     "SendRemoteKey",
+			"version": "1.000",
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	encrypted, err := encryptAESECB(session.Key, command)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]string, len(encrypted))
+	for i, b := range encrypted {
+		values[i] = strconv.Itoa(int(b))
+	}
+
+	message := fmt.Sprintf(
+		`5::%s:{"name":"callCommon","args":[{"Session_Id":%s,"body":"[%s]"}]}`,
+		encryptedNamespace,
+		session.ID,
+		strings.Join(values, ","),
+	)
+
+	return []byte(message), nil
+}
+
+// encryptAESECB encrypts with AES in ECB mode and PKCS#7 padding, which is
+// what the TV expects.
+func encryptAESECB(key []byte, data []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	padding := block.BlockSize() - len(data)%block.BlockSize()
+	data = append(data, bytes.Repeat([]byte{byte(padding)}, padding)...)
+
+	encrypted := make([]byte, len(data))
+	for i := 0; i < len(data); i += block.BlockSize() {
+		block.Encrypt(encrypted[i:i+block.BlockSize()], data[i:i+block.BlockSize()])
+	}
+
+	return encrypted, nil
+}
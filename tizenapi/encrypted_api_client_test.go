@@ -0,0 +1,183 @@
+package tizenapi_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kpeu3i/go-tizen-tv/tizenapi"
+	"github.com/kpeu3i/go-tizen-tv/tizentest"
+)
+
+func newPairingServerClient(server *tizentest.PairingServer, options ...tizenapi.EncryptedAPIOption) *tizenapi.EncryptedAPIClient {
+	options = append([]tizenapi.EncryptedAPIOption{
+		tizenapi.WithEncryptedPort(server.Port()),
+		tizenapi.WithEncryptedPairingOptions(
+			tizenapi.WithPairingPort(server.Port()),
+			tizenapi.WithPairingCrypto(tizentest.PairingCrypto{}),
+		),
+	}, options...)
+
+	return tizenapi.NewEncryptedAPIClient(server.Host(), "tizentest", options...)
+}
+
+func waitForPairingServerKeys(t *testing.T, server *tizentest.PairingServer, want []tizentest.ReceivedKey) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(server.Keys()) < len(want) && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	keys := server.Keys()
+	if !reflect.DeepEqual(keys, want) {
+		t.Fatalf("Keys() = %+v, want %+v", keys, want)
+	}
+}
+
+func TestEncryptedAPIClientPairsAndSendsKeys(t *testing.T) {
+	server := startPairingServer(t)
+	defer func() {
+		_ = server.Close()
+	}()
+
+	client := newPairingServerClient(server)
+	defer func() {
+		_ = client.Close()
+	}()
+
+	client.OnPINRequest(func(ctx context.Context) (string, error) {
+		return server.PIN, nil
+	})
+
+	var sessions []tizenapi.PairingSession
+	client.OnSession(func(session tizenapi.PairingSession) error {
+		sessions = append(sessions, session)
+
+		return nil
+	})
+
+	_, err := client.ConnectContext(context.Background(), "")
+	if err != nil {
+		t.Fatalf("ConnectContext() = %v", err)
+	}
+
+	if len(sessions) != 1 || sessions[0].ID != server.SessionID || !reflect.DeepEqual(sessions[0], client.Session()) {
+		t.Fatalf("sessions = %+v, want the session of the client", sessions)
+	}
+
+	for _, state := range []tizenapi.WebsocketKeyState{
+		tizenapi.WebsocketKeyStateClick,
+		tizenapi.WebsocketKeyStatePress,
+		tizenapi.WebsocketKeyStateRelease,
+	} {
+		err = client.SendKey("KEY_VOLUP", state)
+		if err != nil {
+			t.Fatalf("SendKey(%s) = %v", state, err)
+		}
+	}
+
+	waitForPairingServerKeys(t, server, []tizentest.ReceivedKey{
+		{Key: "KEY_VOLUP", Cmd: "Click"},
+		{Key: "KEY_VOLUP", Cmd: "Press"},
+		{Key: "KEY_VOLUP", Cmd: "Release"},
+	})
+}
+
+func TestEncryptedAPIClientReconnectsWithSession(t *testing.T) {
+	server := startPairingServer(t)
+	defer func() {
+		_ = server.Close()
+	}()
+
+	client := newPairingServerClient(server)
+	defer func() {
+		_ = client.Close()
+	}()
+
+	client.OnPINRequest(func(ctx context.Context) (string, error) {
+		return server.PIN, nil
+	})
+
+	_, err := client.ConnectContext(context.Background(), "")
+	if err != nil {
+		t.Fatalf("ConnectContext() = %v", err)
+	}
+
+	session := client.Session()
+
+	// Only the stored session can be used from now on.
+	client.OnPINRequest(func(ctx context.Context) (string, error) {
+		t.Errorf("PIN requested again after the connection was dropped")
+
+		return server.PIN, nil
+	})
+
+	server.DropConnections()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for client.IsConnected() && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if client.IsConnected() {
+		t.Fatalf("IsConnected() = true after the TV dropped the connection")
+	}
+
+	err = client.SendKey("KEY_MUTE", tizenapi.WebsocketKeyStateClick)
+	if err != nil {
+		t.Fatalf("SendKey() = %v", err)
+	}
+
+	waitForPairingServerKeys(t, server, []tizentest.ReceivedKey{{Key: "KEY_MUTE", Cmd: "Click"}})
+
+	if !reflect.DeepEqual(client.Session(), session) {
+		t.Errorf("Session() = %+v, want %+v", client.Session(), session)
+	}
+}
+
+func TestEncryptedAPIClientUsesStoredSession(t *testing.T) {
+	server := startPairingServer(t)
+	defer func() {
+		_ = server.Close()
+	}()
+
+	paired, err := tizenapi.NewPairingAPIClient(
+		server.Host(),
+		tizenapi.WithPairingPort(server.Port()),
+		tizenapi.WithPairingCrypto(tizentest.PairingCrypto{}),
+	).Pair(context.Background(), func(ctx context.Context) (string, error) {
+		return server.PIN, nil
+	})
+	if err != nil {
+		t.Fatalf("Pair() = %v", err)
+	}
+
+	client := newPairingServerClient(server, tizenapi.WithEncryptedSession(paired))
+	defer func() {
+		_ = client.Close()
+	}()
+
+	_, err = client.ConnectContext(context.Background(), "")
+	if err != nil {
+		t.Fatalf("ConnectContext() = %v", err)
+	}
+
+	err = client.SendKey("KEY_HOME", tizenapi.WebsocketKeyStateClick)
+	if err != nil {
+		t.Fatalf("SendKey() = %v", err)
+	}
+
+	waitForPairingServerKeys(t, server, []tizentest.ReceivedKey{{Key: "KEY_HOME", Cmd: "Click"}})
+
+	err = client.Close()
+	if err != nil {
+		t.Fatalf("Close() = %v", err)
+	}
+
+	err = client.SendKey("KEY_HOME", tizenapi.WebsocketKeyStateClick)
+	if err != tizenapi.ErrWebsocketNotConnected {
+		t.Errorf("SendKey() after Close() = %v, want %v", err, tizenapi.ErrWebsocketNotConnected)
+	}
+}
@@ -0,0 +1,203 @@
+package tizenapi_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"testing"
+
+	samsung "github.com/kpeu3i/go-tizen-tv"
+	"github.com/kpeu3i/go-tizen-tv/tizenapi"
+	"github.com/kpeu3i/go-tizen-tv/tizentest"
+)
+
+func TestGetInfoResponseFixtures(t *testing.T) {
+	years := map[int]bool{}
+
+	for _, fixture := range tizentest.Fixtures(tizentest.FixtureInfo) {
+		t.Run(fixture.Name, func(t *testing.T) {
+			years[fixture.ModelYear] = true
+
+			response := tizenapi.GetInfoResponse{}
+
+			err := json.Unmarshal([]byte(fixture.Body), &response)
+			if err != nil {
+				t.Fatalf("Unmarshal() = %v", err)
+			}
+
+			if response.Remote != "1.0" || response.Version != "2.0.25" || response.Type != "Samsung SmartTV" {
+				t.Errorf("response = %+v", response)
+			}
+
+			device := samsung.TVDevice(response.Device)
+			year := fixture.ModelYear
+
+			wantID := fmt.Sprintf("uuid:00000000-0000-4000-8000-%012d", year)
+			if device.ID() != wantID || response.ID != wantID {
+				t.Errorf("ID() = %q, response ID %q, want %q", device.ID(), response.ID, wantID)
+			}
+
+			wantIP := fmt.Sprintf("192.0.2.%d", year%100)
+			if device.IP() != wantIP {
+				t.Errorf("IP() = %q, want %q", device.IP(), wantIP)
+			}
+
+			wantMAC := fmt.Sprintf("02:00:5e:00:20:%02d", year%100)
+			if device.MAC() != wantMAC {
+				t.Errorf("MAC() = %q, want %q", device.MAC(), wantMAC)
+			}
+
+			if device.Name() == "" || device.Name() != response.Name {
+				t.Errorf("Name() = %q, response name %q", device.Name(), response.Name)
+			}
+
+			// Token authentication came with the 2018 models, power states a year later.
+			if device.TokenAuthSupport() != (year >= 2018) {
+				t.Errorf("TokenAuthSupport() = %v", device.TokenAuthSupport())
+			}
+
+			if (device.PowerState() != "") != (year >= 2019) {
+				t.Errorf("PowerState() = %q", device.PowerState())
+			}
+
+			// Booleans and numbers sent as such end up as their string forms.
+			if device["developerMode"] != "0" || device["smartHubAgreement"] != "true" {
+				t.Errorf("developerMode = %q, smartHubAgreement = %q", device["developerMode"], device["smartHubAgreement"])
+			}
+
+			isSupport, err := tizenapi.ParseIsSupport(response.IsSupport)
+			if err != nil {
+				t.Fatalf("ParseIsSupport(%q) = %v", response.IsSupport, err)
+			}
+
+			if isSupport["TokenAuthSupport"] != strconv.FormatBool(year >= 2018) {
+				t.Errorf("isSupport TokenAuthSupport = %q", isSupport["TokenAuthSupport"])
+			}
+
+			if isSupport["FrameTVSupport"] != device["FrameTVSupport"] {
+				t.Errorf("isSupport FrameTVSupport = %q, device %q", isSupport["FrameTVSupport"], device["FrameTVSupport"])
+			}
+
+			if isSupport["remote"] != "true" || isSupport["DMP_DRM_PLAYREADY"] != "false" {
+				t.Errorf("isSupport = %v", isSupport)
+			}
+		})
+	}
+
+	for year := 2016; year <= 2024; year++ {
+		if !years[year] {
+			t.Errorf("no info fixture of %d", year)
+		}
+	}
+}
+
+func TestGetAppResponseFixtures(t *testing.T) {
+	tests := map[string]tizenapi.GetAppResponse{
+		"2016-k-app": {ID: "111299001912", Name: "YouTube", Version: "2.1.0"},
+		"2022-b-app": {ID: "3201907018807", Name: "Netflix", Running: true, Visible: true, Version: "5.3.10"},
+		"2023-c-app": {ID: "111299001912", Name: "YouTube", Running: true, Version: "3.1.2"},
+	}
+
+	for _, fixture := range tizentest.Fixtures(tizentest.FixtureApp) {
+		t.Run(fixture.Name, func(t *testing.T) {
+			want, ok := tests[fixture.Name]
+			if !ok {
+				t.Fatal("no expectation for the fixture")
+			}
+
+			response := tizenapi.GetAppResponse{}
+
+			err := json.Unmarshal([]byte(fixture.Body), &response)
+			if err != nil {
+				t.Fatalf("Unmarshal() = %v", err)
+			}
+
+			if response != want {
+				t.Errorf("response = %+v, want %+v", response, want)
+			}
+		})
+	}
+}
+
+func TestConnectResponseMessageFixtures(t *testing.T) {
+	tests := map[string]struct {
+		token      string
+		clientName string
+	}{
+		"2016-k-connect": {token: "", clientName: "R29UaXplblRW"},
+		"2018-n-connect": {token: "12345678", clientName: "R29UaXplblRW"},
+		"2020-t-connect": {token: "87654321", clientName: ""},
+		"2023-c-connect": {token: "11223344", clientName: "R29UaXplblRW"},
+	}
+
+	for _, fixture := range tizentest.Fixtures(tizentest.FixtureConnect) {
+		t.Run(fixture.Name, func(t *testing.T) {
+			want, ok := tests[fixture.Name]
+			if !ok {
+				t.Fatal("no expectation for the fixture")
+			}
+
+			message := tizenapi.ConnectResponseMessage{}
+
+			err := json.Unmarshal([]byte(fixture.Body), &message)
+			if err != nil {
+				t.Fatalf("Unmarshal() = %v", err)
+			}
+
+			if message.Event != "ms.channel.connect" || message.Data.Token != want.token {
+				t.Errorf("event = %q, token = %q, want token %q", message.Event, message.Data.Token, want.token)
+			}
+
+			if len(message.Data.Clients) != 1 {
+				t.Fatalf("clients = %+v", message.Data.Clients)
+			}
+
+			client := message.Data.Clients[0]
+			if client.ID != message.Data.ID || client.ConnectTime <= 0 || client.IsHost || client.DeviceName == "" {
+				t.Errorf("client = %+v", client)
+			}
+
+			if client.Attributes.Name != want.clientName {
+				t.Errorf("client name = %q, want %q", client.Attributes.Name, want.clientName)
+			}
+		})
+	}
+}
+
+func TestGetAppsResponseMessageFixtures(t *testing.T) {
+	tests := map[string][]string{
+		"2016-k-installed-apps": {"111299001912", "org.tizen.browser"},
+		"2020-t-installed-apps": {"3201907018807", "111299001912"},
+		"2023-c-installed-apps": {"3201907018807", "3201512006785"},
+	}
+
+	for _, fixture := range tizentest.Fixtures(tizentest.FixtureInstalledApps) {
+		t.Run(fixture.Name, func(t *testing.T) {
+			want, ok := tests[fixture.Name]
+			if !ok {
+				t.Fatal("no expectation for the fixture")
+			}
+
+			message := tizenapi.GetAppsResponseMessage{}
+
+			err := json.Unmarshal([]byte(fixture.Body), &message)
+			if err != nil {
+				t.Fatalf("Unmarshal() = %v", err)
+			}
+
+			if message.Event != "ed.installedApp.get" || message.From != "host" {
+				t.Errorf("event = %q, from = %q", message.Event, message.From)
+			}
+
+			if len(message.Data.Data) != len(want) {
+				t.Fatalf("apps = %+v, want %v", message.Data.Data, want)
+			}
+
+			for i, app := range message.Data.Data {
+				if app.AppId != want[i] || app.Name == "" || app.AppType == 0 {
+					t.Errorf("app %d = %+v, want ID %s", i, app, want[i])
+				}
+			}
+		})
+	}
+}
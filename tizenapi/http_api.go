@@ -1,5 +1,9 @@
 package tizenapi
 
+import (
+	"encoding/json"
+)
+
 type GetAppResponse struct {
 	ID      string `json:"id"`
 	Name    string `json:"name"`
@@ -18,3 +22,61 @@ type GetInfoResponse struct {
 	Device    map[string]string `json:"device"`
 	IsSupport string            `json:"isSupport"`
 }
+
+func (r *GetAppResponse) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		ID      jsonString `json:"id"`
+		Name    jsonString `json:"name"`
+		Running jsonBool   `json:"running"`
+		Visible jsonBool   `json:"visible"`
+		Version jsonString `json:"version"`
+	}
+
+	err := json.Unmarshal(data, &raw)
+	if err != nil {
+		return err
+	}
+
+	*r = GetAppResponse{
+		ID:      string(raw.ID),
+		Name:    string(raw.Name),
+		Running: bool(raw.Running),
+		Visible: bool(raw.Visible),
+		Version: string(raw.Version),
+	}
+
+	return nil
+}
+
+// UnmarshalJSON accepts isSupport as a string holding a JSON object, as most
+// TVs send it, or as an object, and device values which are not strings.
+func (r *GetInfoResponse) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		ID        jsonString            `json:"id"`
+		Type      jsonString            `json:"type"`
+		Name      jsonString            `json:"name"`
+		Version   jsonString            `json:"version"`
+		URI       jsonString            `json:"uri"`
+		Remote    jsonString            `json:"remote"`
+		Device    map[string]jsonString `json:"device"`
+		IsSupport jsonObjectString      `json:"isSupport"`
+	}
+
+	err := json.Unmarshal(data, &raw)
+	if err != nil {
+		return err
+	}
+
+	*r = GetInfoResponse{
+		ID:        string(raw.ID),
+		Type:      string(raw.Type),
+		Name:      string(raw.Name),
+		Version:   string(raw.Version),
+		URI:       string(raw.URI),
+		Remote:    string(raw.Remote),
+		Device:    toStringMap(raw.Device),
+		IsSupport: string(raw.IsSupport),
+	}
+
+	return nil
+}
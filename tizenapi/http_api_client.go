@@ -1,6 +1,7 @@
 package tizenapi
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -69,7 +70,9 @@ func NewHTTPAPIClient(host string, options ...HTTPAPIOption) *HTTPAPIClient {
 		Timeout: client.requestTimeout,
 		Transport: &http.Transport{
 			DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
-				return net.DialTimeout(network, addr, client.dialTimeout)
+				dialer := net.Dialer{Timeout: client.dialTimeout}
+
+				return dialer.DialContext(ctx, network, addr)
 			},
 			ResponseHeaderTimeout: client.responseTimeout,
 		},
@@ -99,7 +102,13 @@ func (c *HTTPAPIClient) ResponseTimeout() time.Duration {
 }
 
 func (c *HTTPAPIClient) IsAvailable() bool {
-	connection, err := net.DialTimeout("tcp", fmt.Sprintf("%s:%s", c.host, c.port), c.dialTimeout)
+	return c.IsAvailableContext(context.Background())
+}
+
+func (c *HTTPAPIClient) IsAvailableContext(ctx context.Context) bool {
+	dialer := net.Dialer{Timeout: c.dialTimeout}
+
+	connection, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(c.host, c.port))
 	if err != nil {
 		return false
 	}
@@ -112,7 +121,11 @@ func (c *HTTPAPIClient) IsAvailable() bool {
 }
 
 func (c *HTTPAPIClient) GetInfo() (GetInfoResponse, error) {
-	response, err := c.httpClient.Get(c.generateHTTPAPIServiceURL())
+	return c.GetInfoContext(context.Background())
+}
+
+func (c *HTTPAPIClient) GetInfoContext(ctx context.Context) (GetInfoResponse, error) {
+	response, err := c.do(ctx, http.MethodGet, c.generateHTTPAPIServiceURL())
 	if err != nil {
 		return GetInfoResponse{}, err
 	}
@@ -140,7 +153,11 @@ func (c *HTTPAPIClient) GetInfo() (GetInfoResponse, error) {
 }
 
 func (c *HTTPAPIClient) GetApp(id string) (GetAppResponse, error) {
-	response, err := c.httpClient.Get(c.generateAppURL(id))
+	return c.GetAppContext(context.Background(), id)
+}
+
+func (c *HTTPAPIClient) GetAppContext(ctx context.Context, id string) (GetAppResponse, error) {
+	response, err := c.do(ctx, http.MethodGet, c.generateAppURL(id))
 	if err != nil {
 		return GetAppResponse{}, err
 	}
@@ -168,7 +185,11 @@ func (c *HTTPAPIClient) GetApp(id string) (GetAppResponse, error) {
 }
 
 func (c *HTTPAPIClient) OpenApp(id string) error {
-	response, err := c.httpClient.Post(c.generateAppURL(id), "application/json", nil)
+	return c.OpenAppContext(context.Background(), id)
+}
+
+func (c *HTTPAPIClient) OpenAppContext(ctx context.Context, id string) error {
+	response, err := c.do(ctx, http.MethodPost, c.generateAppURL(id))
 	if err != nil {
 		return err
 	}
@@ -186,6 +207,11 @@ func (c *HTTPAPIClient) OpenApp(id string) error {
 		return err
 	}
 
+	// Depending on the firmware the body is empty, a boolean or {"ok":...}.
+	if len(bytes.TrimSpace(bodyData)) == 0 {
+		return nil
+	}
+
 	var body interface{}
 
 	err = json.Unmarshal(bodyData, &body)
@@ -193,16 +219,17 @@ func (c *HTTPAPIClient) OpenApp(id string) error {
 		return err
 	}
 
+	ok := false
 	switch v := body.(type) {
 	case bool:
-		if !v {
-			return fmt.Errorf("invalid response body: %s", string(bodyData))
-		}
+		ok = v
+	case string:
+		ok = v == "true"
 	case map[string]interface{}:
-		if val, key := v["ok"].(bool); !key || !val {
-			return fmt.Errorf("invalid response body: %s", string(bodyData))
-		}
-	default:
+		ok = v["ok"] == true || v["ok"] == "true"
+	}
+
+	if !ok {
 		return fmt.Errorf("invalid response body: %s", string(bodyData))
 	}
 
@@ -210,12 +237,11 @@ func (c *HTTPAPIClient) OpenApp(id string) error {
 }
 
 func (c *HTTPAPIClient) InstallApp(id string) error {
-	request, err := http.NewRequest(http.MethodPut, c.generateAppURL(id), nil)
-	if err != nil {
-		panic(err)
-	}
+	return c.InstallAppContext(context.Background(), id)
+}
 
-	response, err := c.httpClient.Do(request)
+func (c *HTTPAPIClient) InstallAppContext(ctx context.Context, id string) error {
+	response, err := c.do(ctx, http.MethodPut, c.generateAppURL(id))
 	if err != nil {
 		return err
 	}
@@ -232,12 +258,11 @@ func (c *HTTPAPIClient) InstallApp(id string) error {
 }
 
 func (c *HTTPAPIClient) CloseApp(id string) error {
-	request, err := http.NewRequest("DELETE", c.generateAppURL(id), nil)
-	if err != nil {
-		return err
-	}
+	return c.CloseAppContext(context.Background(), id)
+}
 
-	response, err := c.httpClient.Do(request)
+func (c *HTTPAPIClient) CloseAppContext(ctx context.Context, id string) error {
+	response, err := c.do(ctx, http.MethodDelete, c.generateAppURL(id))
 	if err != nil {
 		return err
 	}
@@ -253,11 +278,24 @@ func (c *HTTPAPIClient) CloseApp(id string) error {
 	return nil
 }
 
+func (c *HTTPAPIClient) do(ctx context.Context, method string, url string) (*http.Response, error) {
+	request, err := http.NewRequest(method, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if method == http.MethodPost {
+		request.Header.Set("Content-Type", "application/json")
+	}
+
+	return c.httpClient.Do(request.WithContext(ctx))
+}
+
 func (c *HTTPAPIClient) generateHTTPAPIServiceURL() string {
 	return fmt.Sprintf(
 		"http://%s:%s/api/v2/",
 		c.host,
-		defaultHTTPPort,
+		c.port,
 	)
 }
 
@@ -265,7 +303,7 @@ func (c *HTTPAPIClient) generateAppURL(id string) string {
 	return fmt.Sprintf(
 		"http://%s:%s/api/v2/applications/%s",
 		c.host,
-		defaultHTTPPort,
+		c.port,
 		id,
 	)
 }
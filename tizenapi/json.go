@@ -0,0 +1,158 @@
+package tizenapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+)
+
+// Payloads differ between firmware versions: numbers and booleans are sent as
+// strings on some TVs and the other way round on others. The types below
+// accept both.
+
+// jsonString decodes a string, a number, a boolean or null into a string.
+type jsonString string
+
+func (s *jsonString) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+
+	switch {
+	case bytes.Equal(data, []byte("null")):
+		*s = ""
+	case len(data) > 0 && data[0] == '"':
+		var value string
+
+		err := json.Unmarshal(data, &value)
+		if err != nil {
+			return err
+		}
+
+		*s = jsonString(value)
+	default:
+		*s = jsonString(data)
+	}
+
+	return nil
+}
+
+// jsonBool decodes a boolean, a "true"/"false" string or a number into a
+// boolean.
+type jsonBool bool
+
+func (b *jsonBool) UnmarshalJSON(data []byte) error {
+	var value jsonString
+
+	err := value.UnmarshalJSON(data)
+	if err != nil {
+		return err
+	}
+
+	if value == "" {
+		*b = false
+
+		return nil
+	}
+
+	parsed, err := strconv.ParseBool(string(value))
+	if err != nil {
+		return err
+	}
+
+	*b = jsonBool(parsed)
+
+	return nil
+}
+
+// jsonNumber decodes a number or a numeric string.
+type jsonNumber float64
+
+func (n *jsonNumber) UnmarshalJSON(data []byte) error {
+	var value jsonString
+
+	err := value.UnmarshalJSON(data)
+	if err != nil {
+		return err
+	}
+
+	if value == "" {
+		*n = 0
+
+		return nil
+	}
+
+	parsed, err := strconv.ParseFloat(string(value), 64)
+	if err != nil {
+		return err
+	}
+
+	*n = jsonNumber(parsed)
+
+	return nil
+}
+
+// jsonObjectString decodes a JSON object encoded either as a string or as an
+// object into its string form.
+type jsonObjectString string
+
+func (s *jsonObjectString) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '{' {
+		var compacted bytes.Buffer
+
+		err := json.Compact(&compacted, data)
+		if err != nil {
+			return err
+		}
+
+		*s = jsonObjectString(compacted.String())
+
+		return nil
+	}
+
+	var value jsonString
+
+	err := value.UnmarshalJSON(data)
+	if err != nil {
+		return err
+	}
+
+	*s = jsonObjectString(value)
+
+	return nil
+}
+
+func toStringMap(values map[string]jsonString) map[string]string {
+	if values == nil {
+		return nil
+	}
+
+	m := make(map[string]string, len(values))
+	for key, value := range values {
+		m[key] = string(value)
+	}
+
+	return m
+}
+
+// ParseIsSupport decodes the isSupport field of GetInfoResponse. Values which
+// are not strings are converted to strings and an empty field gives an empty
+// map.
+func ParseIsSupport(isSupport string) (map[string]string, error) {
+	if isSupport == "" {
+		return map[string]string{}, nil
+	}
+
+	var values map[string]jsonString
+
+	err := json.Unmarshal([]byte(isSupport), &values)
+	if err != nil {
+		return nil, err
+	}
+
+	m := toStringMap(values)
+	if m == nil {
+		m = map[string]string{}
+	}
+
+	return m, nil
+}
@@ -0,0 +1,470 @@
+package tizenapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+)
+
+const (
+	LegacyProtocol = "legacy"
+
+	defaultLegacyPort         = "55000"
+	defaultLegacyDialTimeout  = 1 * time.Second
+	defaultLegacyReadTimeout  = 30 * time.Second
+	defaultLegacyWriteTimeout = 30 * time.Second
+
+	legacyPacketHeaderSize = 3
+	legacyAuthWaitingType  = byte(0x0a)
+	legacyAuthTimeoutType  = byte(0x65)
+)
+
+var (
+	ErrLegacyNotSupported = errors.New("not supported by the legacy remote protocol")
+	ErrLegacyAccessDenied = errors.New("legacy remote access denied")
+	ErrLegacyAuthTimeout  = errors.New("legacy remote authorization timed out")
+
+	legacyAuthorized      = []byte{0x64, 0x00, 0x01, 0x00}
+	legacyDenied          = []byte{0x64, 0x00, 0x00, 0x00}
+	legacyKeyAcknowledged = []byte{0x00, 0x00, 0x00, 0x00}
+	legacyAuthPayloadType = []byte{0x64, 0x00}
+	legacyKeyPayloadType  = []byte{0x00, 0x00, 0x00}
+)
+
+type LegacyAPIOption func(*LegacyAPIClient)
+
+func WithLegacyPort(port string) LegacyAPIOption {
+	return func(client *LegacyAPIClient) {
+		client.port = port
+	}
+}
+
+func WithLegacyDialTimeout(timeout time.Duration) LegacyAPIOption {
+	return func(client *LegacyAPIClient) {
+		client.dialTimeout = timeout
+	}
+}
+
+func WithLegacyReadTimeout(timeout time.Duration) LegacyAPIOption {
+	return func(client *LegacyAPIClient) {
+		client.readTimeout = timeout
+	}
+}
+
+func WithLegacyWriteTimeout(timeout time.Duration) LegacyAPIOption {
+	return func(client *LegacyAPIClient) {
+		client.writeTimeout = timeout
+	}
+}
+
+// LegacyAPIClient speaks the binary remote protocol of pre-Tizen TVs (C/D/E/F
+// series) on port 55000. It only sends keys, other websocket API features
+// return ErrLegacyNotSupported.
+type LegacyAPIClient struct {
+	host         string
+	port         string
+	clientID     string
+	dialTimeout  time.Duration
+	readTimeout  time.Duration
+	writeTimeout time.Duration
+	mu           sync.Mutex
+	connection   net.Conn
+	// pending is the connection waiting for the user to allow the client. It
+	// is kept apart so that the prompt does not block the client meanwhile.
+	pending net.Conn
+}
+
+func NewLegacyAPIClient(host string, clientID string, options ...LegacyAPIOption) *LegacyAPIClient {
+	client := &LegacyAPIClient{
+		host:         host,
+		port:         defaultLegacyPort,
+		clientID:     clientID,
+		dialTimeout:  defaultLegacyDialTimeout,
+		readTimeout:  defaultLegacyReadTimeout,
+		writeTimeout: defaultLegacyWriteTimeout,
+	}
+
+	for _, option := range options {
+		option(client)
+	}
+
+	return client
+}
+
+func (c *LegacyAPIClient) Protocol() string {
+	return LegacyProtocol
+}
+
+func (c *LegacyAPIClient) Host() string {
+	return c.host
+}
+
+func (c *LegacyAPIClient) Port() string {
+	return c.port
+}
+
+func (c *LegacyAPIClient) IsSecure() bool {
+	return false
+}
+
+func (c *LegacyAPIClient) DialTimeout() time.Duration {
+	return c.dialTimeout
+}
+
+func (c *LegacyAPIClient) ReadTimeout() time.Duration {
+	return c.readTimeout
+}
+
+func (c *LegacyAPIClient) WriteTimeout() time.Duration {
+	return c.writeTimeout
+}
+
+func (c *LegacyAPIClient) ClientID() string {
+	return c.clientID
+}
+
+func (c *LegacyAPIClient) IsAvailable() bool {
+	return c.IsAvailableContext(context.Background())
+}
+
+func (c *LegacyAPIClient) IsAvailableContext(ctx context.Context) bool {
+	dialer := net.Dialer{Timeout: c.dialTimeout}
+
+	connection, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(c.host, c.port))
+	if err != nil {
+		return false
+	}
+
+	defer func() {
+		_ = connection.Close()
+	}()
+
+	return true
+}
+
+func (c *LegacyAPIClient) IsConnected() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.connection != nil
+}
+
+func (c *LegacyAPIClient) IsReconnecting() bool {
+	return false
+}
+
+// Connect opens the connection and authenticates the client. The TV asks the
+// user to allow the client on the first connection, so the context should
+// leave time for that. The protocol has no tokens, the one given is ignored.
+func (c *LegacyAPIClient) Connect(token string) (ConnectResponseMessage, error) {
+	return c.ConnectContext(context.Background(), token)
+}
+
+func (c *LegacyAPIClient) ConnectContext(ctx context.Context, token string) (ConnectResponseMessage, error) {
+	connection, err := c.dial(ctx)
+	if err != nil {
+		return ConnectResponseMessage{}, err
+	}
+
+	c.mu.Lock()
+	if c.connection != nil || c.pending != nil {
+		c.mu.Unlock()
+		_ = connection.Close()
+
+		return ConnectResponseMessage{}, errors.New("connection has been already opened")
+	}
+
+	c.pending = connection
+	c.mu.Unlock()
+
+	err = c.authorize(ctx, connection)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Close drops the pending connection while the user is asked.
+	if c.pending != connection {
+		return ConnectResponseMessage{}, errors.New("connection has been closed")
+	}
+
+	c.pending = nil
+
+	if err != nil {
+		_ = connection.Close()
+
+		return ConnectResponseMessage{}, err
+	}
+
+	c.connection = connection
+
+	response := ConnectResponseMessage{Event: string(WebsocketEventChannelConnect)}
+	response.Data.ID = c.clientID
+
+	return response, nil
+}
+
+func (c *LegacyAPIClient) GetApps() (GetAppsResponseMessage, error) {
+	return c.GetAppsContext(context.Background())
+}
+
+func (c *LegacyAPIClient) GetAppsContext(ctx context.Context) (GetAppsResponseMessage, error) {
+	return GetAppsResponseMessage{}, ErrLegacyNotSupported
+}
+
+func (c *LegacyAPIClient) OpenApp(id string, actionType WebsocketOpenAppActionType, metaTag string) error {
+	return c.OpenAppContext(context.Background(), id, actionType, metaTag)
+}
+
+func (c *LegacyAPIClient) OpenAppContext(
+	ctx context.Context,
+	id string,
+	actionType WebsocketOpenAppActionType,
+	metaTag string,
+) error {
+	return ErrLegacyNotSupported
+}
+
+// SendKey sends a key. The protocol only knows clicks, so a press sends the
+// key and a release does nothing, which keeps key sequences working.
+func (c *LegacyAPIClient) SendKey(key string, state WebsocketKeyState) error {
+	return c.SendKeyContext(context.Background(), key, state)
+}
+
+func (c *LegacyAPIClient) SendKeyContext(ctx context.Context, key string, state WebsocketKeyState) error {
+	if state == WebsocketKeyStateRelease {
+		return nil
+	}
+
+	payload := &bytes.Buffer{}
+	payload.Write(legacyKeyPayloadType)
+	writeLegacyString(payload, []byte(base64.StdEncoding.EncodeToString([]byte(key))))
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.connection == nil {
+		return ErrWebsocketNotConnected
+	}
+
+	err := c.exchange(ctx, c.connection, payload.Bytes())
+	if err == nil {
+		return nil
+	}
+
+	// Legacy TVs drop idle connections, a key is retried once on a new one.
+	// The client has been allowed already, so no prompt holds the lock here.
+	c.closeConnection()
+
+	connection, err := c.dial(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = c.authorize(ctx, connection)
+	if err != nil {
+		_ = connection.Close()
+
+		return err
+	}
+
+	c.connection = connection
+
+	err = c.exchange(ctx, c.connection, payload.Bytes())
+	if err != nil {
+		c.closeConnection()
+
+		return err
+	}
+
+	return nil
+}
+
+func (c *LegacyAPIClient) SendMouseMove(dx, dy int) error {
+	return c.SendMouseMoveContext(context.Background(), dx, dy)
+}
+
+func (c *LegacyAPIClient) SendMouseMoveContext(ctx context.Context, dx, dy int) error {
+	return ErrLegacyNotSupported
+}
+
+func (c *LegacyAPIClient) SendMouseClick(button WebsocketMouseButton) error {
+	return c.SendMouseClickContext(context.Background(), button)
+}
+
+func (c *LegacyAPIClient) SendMouseClickContext(ctx context.Context, button WebsocketMouseButton) error {
+	return ErrLegacyNotSupported
+}
+
+func (c *LegacyAPIClient) SendInputString(text string) error {
+	return c.SendInputStringContext(context.Background(), text)
+}
+
+func (c *LegacyAPIClient) SendInputStringContext(ctx context.Context, text string) error {
+	return ErrLegacyNotSupported
+}
+
+func (c *LegacyAPIClient) SendInputEnd() error {
+	return c.SendInputEndContext(context.Background())
+}
+
+func (c *LegacyAPIClient) SendInputEndContext(ctx context.Context) error {
+	return ErrLegacyNotSupported
+}
+
+// Subscribe never calls the handler, the protocol has no events.
+func (c *LegacyAPIClient) Subscribe(event WebsocketEvent, handler WebsocketEventHandler) func() {
+	return func() {}
+}
+
+// OnReconnect never calls the handler, keys reconnect on their own.
+func (c *LegacyAPIClient) OnReconnect(handler WebsocketReconnectHandler) {}
+
+func (c *LegacyAPIClient) Close() error {
+	return c.CloseContext(context.Background())
+}
+
+func (c *LegacyAPIClient) CloseContext(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.closeConnection()
+
+	if c.pending != nil {
+		_ = c.pending.Close()
+		c.pending = nil
+	}
+
+	return nil
+}
+
+func (c *LegacyAPIClient) dial(ctx context.Context) (net.Conn, error) {
+	dialer := net.Dialer{Timeout: c.dialTimeout}
+
+	return dialer.DialContext(ctx, "tcp", net.JoinHostPort(c.host, c.port))
+}
+
+// authorize introduces the client on the connection, the TV may ask the user
+// to allow it first.
+func (c *LegacyAPIClient) authorize(ctx context.Context, connection net.Conn) error {
+	localIP := ""
+	if address, ok := connection.LocalAddr().(*net.TCPAddr); ok {
+		localIP = address.IP.String()
+	}
+
+	payload := &bytes.Buffer{}
+	payload.Write(legacyAuthPayloadType)
+	writeLegacyString(payload, []byte(base64.StdEncoding.EncodeToString([]byte(localIP))))
+	writeLegacyString(payload, []byte(base64.StdEncoding.EncodeToString([]byte(c.clientID))))
+	writeLegacyString(payload, []byte(base64.StdEncoding.EncodeToString([]byte(c.clientID))))
+
+	return c.exchange(ctx, connection, payload.Bytes())
+}
+
+// exchange writes a packet and reads responses until the one answering it.
+func (c *LegacyAPIClient) exchange(ctx context.Context, connection net.Conn, payload []byte) error {
+	packet := &bytes.Buffer{}
+	packet.Write([]byte{0x00})
+	writeLegacyString(packet, nil)
+	writeLegacyString(packet, payload)
+
+	err := connection.SetWriteDeadline(c.deadline(ctx, c.writeTimeout))
+	if err != nil {
+		return err
+	}
+
+	_, err = connection.Write(packet.Bytes())
+	if err != nil {
+		return err
+	}
+
+	for {
+		err = connection.SetReadDeadline(c.deadline(ctx, c.readTimeout))
+		if err != nil {
+			return err
+		}
+
+		response, err := readLegacyPacket(connection)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			return err
+		}
+
+		switch {
+		case bytes.Equal(response, legacyAuthorized), bytes.Equal(response, legacyKeyAcknowledged):
+			return nil
+		case bytes.Equal(response, legacyDenied):
+			return ErrLegacyAccessDenied
+		case len(response) > 0 && response[0] == legacyAuthWaitingType:
+			// The TV shows the authorization prompt, the answer follows.
+			continue
+		case len(response) > 0 && response[0] == legacyAuthTimeoutType:
+			return ErrLegacyAuthTimeout
+		default:
+			return fmt.Errorf("unexpected legacy response: %x", response)
+		}
+	}
+}
+
+func (c *LegacyAPIClient) deadline(ctx context.Context, timeout time.Duration) time.Time {
+	deadline, ok := ctx.Deadline()
+	if ok {
+		return deadline
+	}
+
+	return time.Now().Add(timeout)
+}
+
+func (c *LegacyAPIClient) closeConnection() {
+	if c.connection == nil {
+		return
+	}
+
+	_ = c.connection.Close()
+	c.connection = nil
+}
+
+func writeLegacyString(buffer *bytes.Buffer, value []byte) {
+	length := make([]byte, 2)
+	binary.LittleEndian.PutUint16(length, uint16(len(value)))
+	buffer.Write(length)
+	buffer.Write(value)
+}
+
+func readLegacyPacket(reader io.Reader) ([]byte, error) {
+	header := make([]byte, legacyPacketHeaderSize)
+	_, err := io.ReadFull(reader, header)
+	if err != nil {
+		return nil, err
+	}
+
+	// The header carries the name of the TV app which sent the response.
+	appName := make([]byte, binary.LittleEndian.Uint16(header[1:]))
+	_, err = io.ReadFull(reader, appName)
+	if err != nil {
+		return nil, err
+	}
+
+	length := make([]byte, 2)
+	_, err = io.ReadFull(reader, length)
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]byte, binary.LittleEndian.Uint16(length))
+	_, err = io.ReadFull(reader, response)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
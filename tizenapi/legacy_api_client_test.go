@@ -0,0 +1,101 @@
+package tizenapi_test
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/kpeu3i/go-tizen-tv/tizenapi"
+)
+
+// writeLegacyResponse writes a response packet as the TV does.
+func writeLegacyResponse(t *testing.T, connection net.Conn, payload []byte) {
+	t.Helper()
+
+	appName := []byte("iapp.samsung")
+
+	packet := []byte{0x00, 0x00, 0x00}
+	binary.LittleEndian.PutUint16(packet[1:], uint16(len(appName)))
+	packet = append(packet, appName...)
+	packet = append(packet, byte(len(payload)), 0x00)
+	packet = append(packet, payload...)
+
+	_, err := connection.Write(packet)
+	if err != nil {
+		t.Errorf("write response: %v", err)
+	}
+}
+
+func TestLegacyAPIClientConnectDoesNotBlockWhileAuthorizing(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+
+	defer func() {
+		_ = listener.Close()
+	}()
+
+	// The TV shows the prompt and the user never answers.
+	go func() {
+		connection, err := listener.Accept()
+		if err != nil {
+			return
+		}
+
+		defer func() {
+			_ = connection.Close()
+		}()
+
+		writeLegacyResponse(t, connection, []byte{0x0a, 0x00, 0x02, 0x00, 0x00, 0x00})
+
+		_, _ = io.Copy(ioutil.Discard, connection)
+	}()
+
+	_, port, _ := net.SplitHostPort(listener.Addr().String())
+	client := tizenapi.NewLegacyAPIClient("127.0.0.1", "tizentest", tizenapi.WithLegacyPort(port))
+
+	errs := make(chan error, 1)
+	go func() {
+		_, err := client.ConnectContext(context.Background(), "")
+		errs <- err
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+
+	connected := make(chan bool, 1)
+	go func() {
+		connected <- client.IsConnected()
+	}()
+
+	select {
+	case ok := <-connected:
+		if ok {
+			t.Error("IsConnected() = true while the user is asked")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("IsConnected() blocked by the authorization prompt")
+	}
+
+	err = client.Close()
+	if err != nil {
+		t.Fatalf("Close() = %v", err)
+	}
+
+	select {
+	case err := <-errs:
+		if err == nil {
+			t.Error("Connect() succeeded after Close()")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Close() has not aborted the authorization")
+	}
+
+	if client.IsConnected() {
+		t.Error("IsConnected() = true after Close()")
+	}
+}
@@ -0,0 +1,106 @@
+package tizenapi
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/kpeu3i/go-tizen-tv/upnp"
+)
+
+const legacyDeviceType = "Samsung SmartTV (legacy)"
+
+// LegacyHTTPAPIClient stands in for the HTTP API on pre-Tizen TVs which do not
+// have it. The info is built from the UPnP device description at the location,
+// apps are not supported.
+type LegacyHTTPAPIClient struct {
+	*HTTPAPIClient
+	location string
+}
+
+func NewLegacyHTTPAPIClient(location string, options ...HTTPAPIOption) *LegacyHTTPAPIClient {
+	host, port := "", ""
+
+	u, err := url.Parse(location)
+	if err == nil {
+		host, port = u.Hostname(), u.Port()
+	}
+
+	if port == "" {
+		port = "80"
+	}
+
+	options = append([]HTTPAPIOption{WithHTTPPort(port)}, options...)
+
+	return &LegacyHTTPAPIClient{
+		HTTPAPIClient: NewHTTPAPIClient(host, options...),
+		location:      location,
+	}
+}
+
+func (c *LegacyHTTPAPIClient) Location() string {
+	return c.location
+}
+
+func (c *LegacyHTTPAPIClient) GetInfo() (GetInfoResponse, error) {
+	return c.GetInfoContext(context.Background())
+}
+
+func (c *LegacyHTTPAPIClient) GetInfoContext(ctx context.Context) (GetInfoResponse, error) {
+	description, err := upnp.FetchDeviceDescription(ctx, c.httpClient, c.location)
+	if err != nil {
+		return GetInfoResponse{}, err
+	}
+
+	device := description.Device
+	id := strings.TrimPrefix(device.UDN, "uuid:")
+
+	info := GetInfoResponse{
+		ID:        id,
+		Type:      legacyDeviceType,
+		Name:      device.FriendlyName,
+		URI:       c.location,
+		IsSupport: "{}",
+		Device: map[string]string{
+			"id":        id,
+			"ip":        c.host,
+			"name":      device.FriendlyName,
+			"modelName": device.ModelName,
+			"type":      legacyDeviceType,
+		},
+	}
+
+	return info, nil
+}
+
+func (c *LegacyHTTPAPIClient) GetApp(id string) (GetAppResponse, error) {
+	return c.GetAppContext(context.Background(), id)
+}
+
+func (c *LegacyHTTPAPIClient) GetAppContext(ctx context.Context, id string) (GetAppResponse, error) {
+	return GetAppResponse{}, ErrLegacyNotSupported
+}
+
+func (c *LegacyHTTPAPIClient) OpenApp(id string) error {
+	return c.OpenAppContext(context.Background(), id)
+}
+
+func (c *LegacyHTTPAPIClient) OpenAppContext(ctx context.Context, id string) error {
+	return ErrLegacyNotSupported
+}
+
+func (c *LegacyHTTPAPIClient) InstallApp(id string) error {
+	return c.InstallAppContext(context.Background(), id)
+}
+
+func (c *LegacyHTTPAPIClient) InstallAppContext(ctx context.Context, id string) error {
+	return ErrLegacyNotSupported
+}
+
+func (c *LegacyHTTPAPIClient) CloseApp(id string) error {
+	return c.CloseAppContext(context.Background(), id)
+}
+
+func (c *LegacyHTTPAPIClient) CloseAppContext(ctx context.Context, id string) error {
+	return ErrLegacyNotSupported
+}
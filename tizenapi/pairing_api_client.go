@@ -0,0 +1,315 @@
+package tizenapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	defaultPairingPort           = "8080"
+	defaultPairingDialTimeout    = 1 * time.Second
+	defaultPairingRequestTimeout = 10 * time.Second
+	defaultPairingAppID          = "12345"
+	defaultPairingDeviceID       = "7e509404-9d7c-46b4-8f6a-e2a9668ad184"
+	defaultPairingUserID         = "654321"
+	pairingPINPagePath           = "/ws/apps/CloudPINPage"
+	pairingPath                  = "/ws/pairing"
+	pairingAuthType              = "SPC"
+	pairingSecureMode            = "secure-mode"
+)
+
+var (
+	ErrPairingCryptoNotConfigured = errors.New("pairing crypto is not configured")
+	ErrPairingPINHandlerNotSet    = errors.New("pairing PIN handler is not set")
+
+	pairingStatePattern       = regexp.MustCompile(`<state>([^<>]*)</state>`)
+	pairingRequestIDPattern   = regexp.MustCompile(`request_id\W*?(\d+)`)
+	pairingClientHelloPattern = regexp.MustCompile(`GeneratorClientHello\W*?([0-9a-fA-F]+)`)
+	pairingClientAckPattern   = regexp.MustCompile(`ClientAckMsg\W*?([0-9a-fA-F]+)`)
+	pairingSessionIDPattern   = regexp.MustCompile(`session_id\W*?(\d+)`)
+)
+
+// PairingCrypto implements the proprietary handshake of the H/J series
+// pairing (known as SmartCrypto). The library does not ship one, it has to be
+// provided by the user.
+type PairingCrypto interface {
+	GenerateServerHello(userID string, pin string) (hello []byte, hash []byte, aesKey []byte, err error)
+	ParseClientHello(clientHello string, hash []byte, aesKey []byte, userID string) (skPrime []byte, sessionKey []byte, err error)
+	GenerateServerAcknowledge(skPrime []byte) (string, error)
+	ParseClientAcknowledge(clientAck string, skPrime []byte) error
+}
+
+// PairingSession holds the credentials of a paired client. The key encrypts
+// the commands sent to the TV.
+type PairingSession struct {
+	ID  string
+	Key []byte
+}
+
+func (s PairingSession) IsEmpty() bool {
+	return s.ID == "" || len(s.Key) == 0
+}
+
+// PINHandler returns the PIN shown on the screen of the TV.
+type PINHandler func(ctx context.Context) (string, error)
+
+type PairingAPIOption func(*PairingAPIClient)
+
+func WithPairingPort(port string) PairingAPIOption {
+	return func(client *PairingAPIClient) {
+		client.port = port
+	}
+}
+
+func WithPairingDialTimeout(timeout time.Duration) PairingAPIOption {
+	return func(client *PairingAPIClient) {
+		client.dialTimeout = timeout
+	}
+}
+
+func WithPairingRequestTimeout(timeout time.Duration) PairingAPIOption {
+	return func(client *PairingAPIClient) {
+		client.requestTimeout = timeout
+	}
+}
+
+func WithPairingCrypto(crypto PairingCrypto) PairingAPIOption {
+	return func(client *PairingAPIClient) {
+		client.crypto = crypto
+	}
+}
+
+// PairingAPIClient pairs with 2014-2015 (H/J series) TVs which require a PIN
+// and an encrypted session.
+type PairingAPIClient struct {
+	host           string
+	port           string
+	dialTimeout    time.Duration
+	requestTimeout time.Duration
+	crypto         PairingCrypto
+	httpClient     *http.Client
+}
+
+func NewPairingAPIClient(host string, options ...PairingAPIOption) *PairingAPIClient {
+	client := &PairingAPIClient{
+		host:           host,
+		port:           defaultPairingPort,
+		dialTimeout:    defaultPairingDialTimeout,
+		requestTimeout: defaultPairingRequestTimeout,
+	}
+
+	for _, option := range options {
+		option(client)
+	}
+
+	client.httpClient = &http.Client{
+		Timeout: client.requestTimeout,
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+				dialer := net.Dialer{Timeout: client.dialTimeout}
+
+				return dialer.DialContext(ctx, network, addr)
+			},
+		},
+	}
+
+	return client
+}
+
+// IsAvailableContext reports whether the TV serves the PIN page, which only
+// TVs requiring PIN pairing do.
+func (c *PairingAPIClient) IsAvailableContext(ctx context.Context) bool {
+	response, err := c.do(ctx, http.MethodGet, c.url(pairingPINPagePath), "")
+	if err != nil {
+		return false
+	}
+
+	return response.statusCode == http.StatusOK
+}
+
+// Pair shows the PIN on the TV, asks the handler for it and runs the
+// handshake. The PIN page is closed whatever the outcome.
+func (c *PairingAPIClient) Pair(ctx context.Context, pinHandler PINHandler) (PairingSession, error) {
+	if c.crypto == nil {
+		return PairingSession{}, ErrPairingCryptoNotConfigured
+	}
+
+	if pinHandler == nil {
+		return PairingSession{}, ErrPairingPINHandlerNotSet
+	}
+
+	err := c.showPIN(ctx)
+	if err != nil {
+		return PairingSession{}, err
+	}
+
+	defer func() {
+		_, _ = c.do(context.Background(), http.MethodDelete, c.url(pairingPINPagePath+"/run"), "")
+	}()
+
+	_, err = c.do(ctx, http.MethodGet, c.pairingURL(0)+"&type=1", "")
+	if err != nil {
+		return PairingSession{}, err
+	}
+
+	pin, err := pinHandler(ctx)
+	if err != nil {
+		return PairingSession{}, err
+	}
+
+	hello, hash, aesKey, err := c.crypto.GenerateServerHello(defaultPairingUserID, pin)
+	if err != nil {
+		return PairingSession{}, err
+	}
+
+	response, err := c.post(ctx, 1, fmt.Sprintf(
+		`{"auth_Data":{"auth_type":"%s","GeneratorServerHello":"%s"}}`,
+		pairingAuthType,
+		strings.ToUpper(hex.EncodeToString(hello)),
+	))
+	if err != nil {
+		return PairingSession{}, err
+	}
+
+	requestID, err := matchPairingResponse(pairingRequestIDPattern, response)
+	if err != nil {
+		return PairingSession{}, err
+	}
+
+	clientHello, err := matchPairingResponse(pairingClientHelloPattern, response)
+	if err != nil {
+		return PairingSession{}, err
+	}
+
+	skPrime, sessionKey, err := c.crypto.ParseClientHello(clientHello, hash, aesKey, defaultPairingUserID)
+	if err != nil {
+		return PairingSession{}, err
+	}
+
+	serverAck, err := c.crypto.GenerateServerAcknowledge(skPrime)
+	if err != nil {
+		return PairingSession{}, err
+	}
+
+	response, err = c.post(ctx, 2, fmt.Sprintf(
+		`{"auth_Data":{"auth_type":"%s","request_id":"%s","ServerAckMsg":"%s"}}`,
+		pairingAuthType,
+		requestID,
+		serverAck,
+	))
+	if err != nil {
+		return PairingSession{}, err
+	}
+
+	if strings.Contains(response, pairingSecureMode) {
+		return PairingSession{}, errors.New("pairing in secure mode is not supported")
+	}
+
+	clientAck, err := matchPairingResponse(pairingClientAckPattern, response)
+	if err != nil {
+		return PairingSession{}, err
+	}
+
+	err = c.crypto.ParseClientAcknowledge(clientAck, skPrime)
+	if err != nil {
+		return PairingSession{}, err
+	}
+
+	sessionID, err := matchPairingResponse(pairingSessionIDPattern, response)
+	if err != nil {
+		return PairingSession{}, err
+	}
+
+	return PairingSession{ID: sessionID, Key: sessionKey}, nil
+}
+
+func (c *PairingAPIClient) showPIN(ctx context.Context) error {
+	response, err := c.do(ctx, http.MethodGet, c.url(pairingPINPagePath), "")
+	if err != nil {
+		return err
+	}
+
+	state := pairingStatePattern.FindStringSubmatch(response.body)
+	if len(state) == 2 && state[1] != "stopped" {
+		return nil
+	}
+
+	_, err = c.do(ctx, http.MethodPost, c.url(pairingPINPagePath), "pin4")
+
+	return err
+}
+
+func (c *PairingAPIClient) post(ctx context.Context, step int, body string) (string, error) {
+	response, err := c.do(ctx, http.MethodPost, c.pairingURL(step), body)
+	if err != nil {
+		return "", err
+	}
+
+	if response.statusCode != http.StatusOK {
+		return "", fmt.Errorf("pairing step %d failed with status %d", step, response.statusCode)
+	}
+
+	return response.body, nil
+}
+
+type pairingResponse struct {
+	statusCode int
+	body       string
+}
+
+func (c *PairingAPIClient) do(ctx context.Context, method string, u string, body string) (pairingResponse, error) {
+	request, err := http.NewRequest(method, u, bytes.NewReader([]byte(body)))
+	if err != nil {
+		return pairingResponse{}, err
+	}
+
+	response, err := c.httpClient.Do(request.WithContext(ctx))
+	if err != nil {
+		return pairingResponse{}, err
+	}
+
+	defer func() {
+		_ = response.Body.Close()
+	}()
+
+	data, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return pairingResponse{}, err
+	}
+
+	return pairingResponse{statusCode: response.StatusCode, body: string(data)}, nil
+}
+
+func (c *PairingAPIClient) url(path string) string {
+	u := url.URL{Scheme: "http", Host: net.JoinHostPort(c.host, c.port), Path: path}
+
+	return u.String()
+}
+
+func (c *PairingAPIClient) pairingURL(step int) string {
+	query := url.Values{}
+	query.Set("step", fmt.Sprint(step))
+	query.Set("app_id", defaultPairingAppID)
+	query.Set("device_id", defaultPairingDeviceID)
+
+	return c.url(pairingPath) + "?" + query.Encode()
+}
+
+func matchPairingResponse(pattern *regexp.Regexp, response string) (string, error) {
+	matches := pattern.FindStringSubmatch(response)
+	if len(matches) != 2 {
+		return "", fmt.Errorf("invalid pairing response: %s", response)
+	}
+
+	return matches[1], nil
+}
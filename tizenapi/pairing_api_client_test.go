@@ -0,0 +1,102 @@
+package tizenapi_test
+
+import (
+	"context"
+	"encoding/hex"
+	"testing"
+
+	"github.com/kpeu3i/go-tizen-tv/tizenapi"
+	"github.com/kpeu3i/go-tizen-tv/tizentest"
+)
+
+func startPairingServer(t *testing.T) *tizentest.PairingServer {
+	t.Helper()
+
+	server := tizentest.NewPairingServer()
+
+	err := server.Start()
+	if err != nil {
+		t.Fatalf("Start() = %v", err)
+	}
+
+	return server
+}
+
+func TestPairingAPIClientPair(t *testing.T) {
+	tests := []struct {
+		name    string
+		pin     string
+		wantErr bool
+	}{
+		{name: "PIN shown on the TV", pin: "1234"},
+		{name: "wrong PIN", pin: "4321", wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := startPairingServer(t)
+			defer func() {
+				_ = server.Close()
+			}()
+
+			client := tizenapi.NewPairingAPIClient(
+				server.Host(),
+				tizenapi.WithPairingPort(server.Port()),
+				tizenapi.WithPairingCrypto(tizentest.PairingCrypto{}),
+			)
+
+			if !client.IsAvailableContext(context.Background()) {
+				t.Fatalf("IsAvailableContext() = false")
+			}
+
+			pinShown := false
+			session, err := client.Pair(context.Background(), func(ctx context.Context) (string, error) {
+				pinShown = server.PINShown()
+
+				return test.pin, nil
+			})
+
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("Pair() = %+v, want an error", session)
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("Pair() = %v", err)
+				}
+
+				if session.ID != server.SessionID || hex.EncodeToString(session.Key) == "" {
+					t.Errorf("Pair() = %+v, want session %s with a key", session, server.SessionID)
+				}
+			}
+
+			if !pinShown {
+				t.Errorf("PIN was not shown when asked for")
+			}
+
+			if server.PINShown() {
+				t.Errorf("PIN is still shown after pairing")
+			}
+		})
+	}
+}
+
+func TestPairingAPIClientPairWithoutCrypto(t *testing.T) {
+	server := startPairingServer(t)
+	defer func() {
+		_ = server.Close()
+	}()
+
+	client := tizenapi.NewPairingAPIClient(server.Host(), tizenapi.WithPairingPort(server.Port()))
+
+	_, err := client.Pair(context.Background(), func(ctx context.Context) (string, error) {
+		return server.PIN, nil
+	})
+	if err != tizenapi.ErrPairingCryptoNotConfigured {
+		t.Fatalf("Pair() = %v, want %v", err, tizenapi.ErrPairingCryptoNotConfigured)
+	}
+
+	if server.PINShown() {
+		t.Errorf("PIN was shown without a way to pair")
+	}
+}
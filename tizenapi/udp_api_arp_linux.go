@@ -0,0 +1,53 @@
+package tizenapi
+
+import (
+	"bufio"
+	"net"
+	"os"
+	"strings"
+)
+
+const arpTablePath = "/proc/net/arp"
+
+// LookupMAC returns the MAC address of the host from the ARP cache, or an
+// empty string when it is not there. The cache only has hosts on the local
+// network which have been talked to recently.
+func LookupMAC(host string) string {
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return ""
+	}
+
+	file, err := os.Open(arpTablePath)
+	if err != nil {
+		return ""
+	}
+
+	defer func() {
+		_ = file.Close()
+	}()
+
+	return parseARPTable(bufio.NewScanner(file), ip)
+}
+
+// parseARPTable looks for the IP in the table, the first line is a header:
+// IP address, HW type, Flags, HW address, Mask, Device.
+func parseARPTable(scanner *bufio.Scanner, ip net.IP) string {
+	scanner.Scan()
+
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 4 || !ip.Equal(net.ParseIP(fields[0])) {
+			continue
+		}
+
+		mac, err := net.ParseMAC(fields[3])
+		if err != nil || mac.String() == "00:00:00:00:00:00" {
+			return ""
+		}
+
+		return mac.String()
+	}
+
+	return ""
+}
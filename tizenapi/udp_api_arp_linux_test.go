@@ -0,0 +1,32 @@
+package tizenapi
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"testing"
+)
+
+func TestParseARPTable(t *testing.T) {
+	table := `IP address       HW type     Flags       HW address            Mask     Device
+192.168.1.1      0x1         0x2         a4:91:b1:00:00:01     *        eth0
+192.168.1.20     0x1         0x2         F4:7B:5E:12:34:56     *        eth0
+192.168.1.30     0x1         0x0         00:00:00:00:00:00     *        eth0
+`
+
+	tests := []struct {
+		ip   string
+		want string
+	}{
+		{ip: "192.168.1.20", want: "f4:7b:5e:12:34:56"},
+		{ip: "192.168.1.30", want: ""},
+		{ip: "192.168.1.40", want: ""},
+	}
+
+	for _, test := range tests {
+		mac := parseARPTable(bufio.NewScanner(strings.NewReader(table)), net.ParseIP(test.ip))
+		if mac != test.want {
+			t.Errorf("parseARPTable(%s) = %q, want %q", test.ip, mac, test.want)
+		}
+	}
+}
@@ -0,0 +1,9 @@
+//go:build !linux
+// +build !linux
+
+package tizenapi
+
+// LookupMAC returns an empty string, the ARP cache is only read on Linux.
+func LookupMAC(host string) string {
+	return ""
+}
@@ -1,20 +1,36 @@
 package tizenapi
 
 import (
+	"bytes"
+	"context"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"net"
+	"strings"
 )
 
 const (
 	defaultUDPSubnet = "255.255.255.255"
 	defaultUDPPort   = "9"
+	ipv6AllNodes     = "ff02::1"
 )
 
-type wolPacket [102]byte
-
 type UDPAPIOption func(*UDPAPIClient)
 
+// WOLRequest asks a relay to send magic packets on its network.
+type WOLRequest struct {
+	MACs     []string
+	SecureOn string
+	Port     string
+}
+
+// WOLRelay sends magic packets on behalf of the client, for TVs on another
+// network which broadcasts do not reach.
+type WOLRelay interface {
+	RelayContext(ctx context.Context, request WOLRequest) error
+}
+
 func WithUDPSubnet(subnet string) UDPAPIOption {
 	return func(client *UDPAPIClient) {
 		client.subnet = subnet
@@ -27,10 +43,57 @@ func WithUDPPort(port string) UDPAPIOption {
 	}
 }
 
+// WithUDPMACs adds MAC addresses to wake besides the main one, e.g. the wired
+// and the wireless interface of the same TV.
+func WithUDPMACs(macs ...string) UDPAPIOption {
+	return func(client *UDPAPIClient) {
+		for _, mac := range macs {
+			client.macs = appendMAC(client.macs, mac)
+		}
+	}
+}
+
+// WithUDPSecureOn sets the SecureOn password appended to magic packets. It is
+// either 4 bytes ("192.168.1.1") or 6 bytes ("01:02:03:04:05:06").
+func WithUDPSecureOn(password string) UDPAPIOption {
+	return func(client *UDPAPIClient) {
+		client.secureOn = password
+	}
+}
+
+// WithUDPTargetHost sets the host of the TV. Packets go to the directed
+// broadcast address of the interface routing to it first.
+func WithUDPTargetHost(host string) UDPAPIOption {
+	return func(client *UDPAPIClient) {
+		client.targetHost = host
+	}
+}
+
+// WithUDPIPv6 enables sending packets to the IPv6 all-nodes multicast group of
+// every interface.
+func WithUDPIPv6(enabled bool) UDPAPIOption {
+	return func(client *UDPAPIClient) {
+		client.ipv6 = enabled
+	}
+}
+
+// WithUDPRelay sends wake requests through the relay instead of broadcasting
+// packets directly.
+func WithUDPRelay(relay WOLRelay) UDPAPIOption {
+	return func(client *UDPAPIClient) {
+		client.relay = relay
+	}
+}
+
 type UDPAPIClient struct {
-	mac    string
-	subnet string
-	port   string
+	mac        string
+	macs       []string
+	subnet     string
+	port       string
+	secureOn   string
+	targetHost string
+	ipv6       bool
+	relay      WOLRelay
 }
 
 func NewUDPAPIClient(mac string, options ...UDPAPIOption) *UDPAPIClient {
@@ -51,6 +114,16 @@ func (s *UDPAPIClient) MAC() string {
 	return s.mac
 }
 
+// MACs returns all MAC addresses to wake, the main one first.
+func (s *UDPAPIClient) MACs() []string {
+	macs := appendMAC(nil, s.mac)
+	for _, mac := range s.macs {
+		macs = appendMAC(macs, mac)
+	}
+
+	return macs
+}
+
 func (s *UDPAPIClient) Subnet() string {
 	return s.subnet
 }
@@ -59,24 +132,225 @@ func (s *UDPAPIClient) Port() string {
 	return s.port
 }
 
+func (s *UDPAPIClient) SecureOn() string {
+	return s.secureOn
+}
+
+func (s *UDPAPIClient) IPv6() bool {
+	return s.ipv6
+}
+
+func (s *UDPAPIClient) Relay() WOLRelay {
+	return s.relay
+}
+
 func (s *UDPAPIClient) WakeUp() error {
-	mac, err := s.parseMAC()
+	return s.WakeUpContext(context.Background())
+}
+
+// WakeUpContext sends a magic packet for every MAC to every destination. It
+// fails only when no packet could be sent.
+func (s *UDPAPIClient) WakeUpContext(ctx context.Context) error {
+	packets, err := s.constructPackets()
 	if err != nil {
 		return err
 	}
 
-	packet := constructWOLPacket(mac)
+	if s.relay != nil {
+		return s.relay.RelayContext(ctx, WOLRequest{
+			MACs:     s.MACs(),
+			SecureOn: s.secureOn,
+			Port:     s.port,
+		})
+	}
 
-	err = s.broadcastWOLPacket(packet)
-	if err != nil {
-		return err
+	destinations := s.destinations()
+
+	sent := 0
+	var lastErr error
+	for _, destination := range destinations {
+		for _, packet := range packets {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			err = sendWOLPacket(ctx, destination, packet)
+			if err != nil {
+				lastErr = err
+
+				continue
+			}
+
+			sent++
+		}
+	}
+
+	if sent == 0 {
+		if lastErr == nil {
+			return errors.New("no Wake-on-LAN destination found")
+		}
+
+		return lastErr
 	}
 
 	return nil
 }
 
-func (s *UDPAPIClient) broadcastWOLPacket(packet wolPacket) error {
-	connection, err := net.Dial("udp", fmt.Sprintf("%s:%s", s.subnet, s.port))
+func (s *UDPAPIClient) constructPackets() ([][]byte, error) {
+	password, err := parseSecureOn(s.secureOn)
+	if err != nil {
+		return nil, err
+	}
+
+	var packets [][]byte
+	for _, value := range s.MACs() {
+		mac, err := parseMAC(value)
+		if err != nil {
+			return nil, err
+		}
+
+		packets = append(packets, constructWOLPacket(mac, password))
+	}
+
+	if len(packets) == 0 {
+		return nil, errors.New("parse MAC error: no MAC address")
+	}
+
+	return packets, nil
+}
+
+// destinations returns the addresses to send packets to: the broadcast address
+// of the interface routing to the TV, the directed broadcast addresses of all
+// interfaces, the configured subnet and the IPv6 all-nodes groups if enabled.
+func (s *UDPAPIClient) destinations() []string {
+	var destinations []string
+
+	add := func(host string) {
+		address := net.JoinHostPort(host, s.port)
+		for _, destination := range destinations {
+			if destination == address {
+				return
+			}
+		}
+
+		destinations = append(destinations, address)
+	}
+
+	networks := interfaceNetworks()
+
+	if s.targetHost != "" {
+		local := routeIP(s.targetHost)
+		for _, network := range networks {
+			if local != nil && local.To4() != nil && network.ipNet.IP.Equal(local) {
+				add(broadcastIP(network.ipNet).String())
+			}
+		}
+	}
+
+	for _, network := range networks {
+		if network.ipNet.IP.To4() != nil && network.broadcast {
+			add(broadcastIP(network.ipNet).String())
+		}
+	}
+
+	subnet := s.subnet
+	if subnet == "" {
+		subnet = defaultUDPSubnet
+	}
+
+	add(subnet)
+
+	if s.ipv6 {
+		for _, network := range networks {
+			if network.ipNet.IP.To4() == nil && network.multicast {
+				add(ipv6AllNodes + "%" + network.name)
+			}
+		}
+	}
+
+	return destinations
+}
+
+type interfaceNetwork struct {
+	name      string
+	ipNet     *net.IPNet
+	broadcast bool
+	multicast bool
+}
+
+func interfaceNetworks() []interfaceNetwork {
+	interfaces, err := net.Interfaces()
+	if err != nil {
+		return nil
+	}
+
+	var networks []interfaceNetwork
+	for _, iface := range interfaces {
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+
+		for _, addr := range addrs {
+			ipNet, ok := addr.(*net.IPNet)
+			if !ok {
+				continue
+			}
+
+			networks = append(networks, interfaceNetwork{
+				name:      iface.Name,
+				ipNet:     ipNet,
+				broadcast: iface.Flags&net.FlagBroadcast != 0,
+				multicast: iface.Flags&net.FlagMulticast != 0,
+			})
+		}
+	}
+
+	return networks
+}
+
+// routeIP returns the local IP used to reach the host. Dialing UDP sends no
+// packets.
+func routeIP(host string) net.IP {
+	connection, err := net.Dial("udp", net.JoinHostPort(host, defaultUDPPort))
+	if err != nil {
+		return nil
+	}
+
+	defer func() {
+		_ = connection.Close()
+	}()
+
+	addr, ok := connection.LocalAddr().(*net.UDPAddr)
+	if !ok {
+		return nil
+	}
+
+	return addr.IP
+}
+
+func broadcastIP(ipNet *net.IPNet) net.IP {
+	ip := ipNet.IP.To4()
+	if ip == nil || len(ipNet.Mask) != net.IPv4len {
+		return net.IPv4bcast
+	}
+
+	broadcast := make(net.IP, net.IPv4len)
+	for i := range ip {
+		broadcast[i] = ip[i] | ^ipNet.Mask[i]
+	}
+
+	return broadcast
+}
+
+func sendWOLPacket(ctx context.Context, address string, packet []byte) error {
+	dialer := net.Dialer{}
+
+	connection, err := dialer.DialContext(ctx, "udp", address)
 	if err != nil {
 		return err
 	}
@@ -85,7 +359,7 @@ func (s *UDPAPIClient) broadcastWOLPacket(packet wolPacket) error {
 		_ = connection.Close()
 	}()
 
-	_, err = connection.Write(packet[:])
+	_, err = connection.Write(packet)
 	if err != nil {
 		return err
 	}
@@ -93,8 +367,8 @@ func (s *UDPAPIClient) broadcastWOLPacket(packet wolPacket) error {
 	return nil
 }
 
-func (s *UDPAPIClient) parseMAC() (net.HardwareAddr, error) {
-	mac, err := net.ParseMAC(s.mac)
+func parseMAC(value string) (net.HardwareAddr, error) {
+	mac, err := net.ParseMAC(value)
 	if err != nil {
 		return nil, fmt.Errorf("parse MAC error: %s", err.Error())
 	}
@@ -106,16 +380,48 @@ func (s *UDPAPIClient) parseMAC() (net.HardwareAddr, error) {
 	return mac, nil
 }
 
-func constructWOLPacket(mac net.HardwareAddr) wolPacket {
-	var packet wolPacket
+func parseSecureOn(value string) ([]byte, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	if ip := net.ParseIP(value).To4(); ip != nil && strings.Count(value, ".") == 3 {
+		return ip, nil
+	}
+
+	password, err := net.ParseMAC(value)
+	if err == nil && len(password) == 6 {
+		return password, nil
+	}
+
+	password, err = hex.DecodeString(value)
+	if err == nil && (len(password) == 4 || len(password) == 6) {
+		return password, nil
+	}
+
+	return nil, errors.New("parse SecureOn password error: expected 4 or 6 bytes")
+}
+
+func appendMAC(macs []string, mac string) []string {
+	if mac == "" {
+		return macs
+	}
+
+	for _, m := range macs {
+		if strings.EqualFold(m, mac) {
+			return macs
+		}
+	}
+
+	return append(macs, mac)
+}
 
-	offset := 6
-	copy(packet[0:], []byte{255, 255, 255, 255, 255, 255})
+func constructWOLPacket(mac net.HardwareAddr, password []byte) []byte {
+	packet := bytes.Repeat([]byte{255}, 6)
 
 	for i := 0; i < 16; i++ {
-		copy(packet[offset:], mac)
-		offset += 6
+		packet = append(packet, mac...)
 	}
 
-	return packet
+	return append(packet, password...)
 }
@@ -1,19 +1,15 @@
 package tizenapi
 
+import (
+	"encoding/json"
+)
+
 type ConnectResponseMessage struct {
 	Event string `json:"event"`
 	Data  struct {
-		ID      string `json:"id"`
-		Token   string `json:"token"`
-		Clients []struct {
-			ID          string  `json:"id"`
-			ConnectTime float64 `json:"connectTime"`
-			DeviceName  string  `json:"deviceName"`
-			IsHost      bool    `json:"isHost"`
-			Attributes  struct {
-				Name string `json:"name"`
-			} `json:"attributes"`
-		} `json:"clients"`
+		ID      string          `json:"id"`
+		Token   string          `json:"token"`
+		Clients []ChannelClient `json:"clients"`
 	} `json:"data"`
 }
 
@@ -29,15 +25,17 @@ type GetAppsResponseMessage struct {
 	Event string `json:"event"`
 	From  string `json:"from"`
 	Data  struct {
-		Data []struct {
-			AppId   string `json:"appId"`
-			AppType int    `json:"app_type"`
-			Icon    string `json:"icon"`
-			Name    string `json:"name"`
-		} `json:"data"`
+		Data []InstalledApp `json:"data"`
 	} `json:"data"`
 }
 
+type InstalledApp struct {
+	AppId   string `json:"appId"`
+	AppType int    `json:"app_type"`
+	Icon    string `json:"icon"`
+	Name    string `json:"name"`
+}
+
 type OpenAppRequestMessage struct {
 	Method string `json:"method"`
 	Params struct {
@@ -60,3 +58,149 @@ type SendKeyRequestMessage struct {
 		TypeOfRemote string `json:"TypeOfRemote"`
 	} `json:"params"`
 }
+
+type EventMessage struct {
+	Event string          `json:"event"`
+	From  string          `json:"from"`
+	Data  json.RawMessage `json:"data"`
+}
+
+type ErrorEventMessage struct {
+	Event string `json:"event"`
+	Data  struct {
+		Message string `json:"message"`
+	} `json:"data"`
+}
+
+type ChannelClient struct {
+	ID          string  `json:"id"`
+	ConnectTime float64 `json:"connectTime"`
+	DeviceName  string  `json:"deviceName"`
+	IsHost      bool    `json:"isHost"`
+	Attributes  struct {
+		Name string `json:"name"`
+	} `json:"attributes"`
+}
+
+type SendInputStringRequestMessage struct {
+	Method string `json:"method"`
+	Params struct {
+		Cmd          string `json:"Cmd"`
+		DataOfCmd    string `json:"DataOfCmd"`
+		TypeOfRemote string `json:"TypeOfRemote"`
+	} `json:"params"`
+}
+
+type SendInputEndRequestMessage struct {
+	Method string `json:"method"`
+	Params struct {
+		TypeOfRemote string `json:"TypeOfRemote"`
+	} `json:"params"`
+}
+
+type SendMouseMoveRequestMessage struct {
+	Method string `json:"method"`
+	Params struct {
+		Cmd      string `json:"Cmd"`
+		Position struct {
+			X    int    `json:"x"`
+			Y    int    `json:"y"`
+			Time string `json:"Time"`
+		} `json:"Position"`
+		TypeOfRemote string `json:"TypeOfRemote"`
+	} `json:"params"`
+}
+
+type SendMouseClickRequestMessage struct {
+	Method string `json:"method"`
+	Params struct {
+		Cmd          string `json:"Cmd"`
+		TypeOfRemote string `json:"TypeOfRemote"`
+	} `json:"params"`
+}
+
+// UnmarshalJSON accepts a token and a client ID sent as numbers.
+func (m *ConnectResponseMessage) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Event jsonString `json:"event"`
+		Data  struct {
+			ID      jsonString      `json:"id"`
+			Token   jsonString      `json:"token"`
+			Clients []ChannelClient `json:"clients"`
+		} `json:"data"`
+	}
+
+	err := json.Unmarshal(data, &raw)
+	if err != nil {
+		return err
+	}
+
+	*m = ConnectResponseMessage{Event: string(raw.Event)}
+	m.Data.ID = string(raw.Data.ID)
+	m.Data.Token = string(raw.Data.Token)
+	m.Data.Clients = raw.Data.Clients
+
+	return nil
+}
+
+// UnmarshalJSON accepts the app type as a string and under "appType".
+func (a *InstalledApp) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		AppID      jsonString `json:"appId"`
+		AppType    jsonNumber `json:"app_type"`
+		AppTypeAlt jsonNumber `json:"appType"`
+		Icon       jsonString `json:"icon"`
+		Name       jsonString `json:"name"`
+	}
+
+	err := json.Unmarshal(data, &raw)
+	if err != nil {
+		return err
+	}
+
+	appType := raw.AppType
+	if appType == 0 {
+		appType = raw.AppTypeAlt
+	}
+
+	*a = InstalledApp{
+		AppId:   string(raw.AppID),
+		AppType: int(appType),
+		Icon:    string(raw.Icon),
+		Name:    string(raw.Name),
+	}
+
+	return nil
+}
+
+// UnmarshalJSON accepts the connect time as a string and missing or null
+// attributes.
+func (c *ChannelClient) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		ID          jsonString `json:"id"`
+		ConnectTime jsonNumber `json:"connectTime"`
+		DeviceName  jsonString `json:"deviceName"`
+		IsHost      jsonBool   `json:"isHost"`
+		Attributes  *struct {
+			Name jsonString `json:"name"`
+		} `json:"attributes"`
+	}
+
+	err := json.Unmarshal(data, &raw)
+	if err != nil {
+		return err
+	}
+
+	*c = ChannelClient{
+		ID:          string(raw.ID),
+		ConnectTime: float64(raw.ConnectTime),
+		DeviceName:  string(raw.DeviceName),
+		IsHost:      bool(raw.IsHost),
+	}
+
+	if raw.Attributes != nil {
+		c.Attributes.Name = string(raw.Attributes.Name)
+	}
+
+	return nil
+}
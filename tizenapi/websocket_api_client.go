@@ -1,7 +1,7 @@
 package tizenapi
 
 import (
-	"bytes"
+	"context"
 	"crypto/tls"
 	"encoding/base64"
 	"encoding/json"
@@ -9,18 +9,24 @@ import (
 	"fmt"
 	"net"
 	"sort"
+	"strconv"
+	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/websocket"
+
+	"github.com/kpeu3i/go-tizen-tv/clock"
 )
 
 const (
 	defaultWebsocketPort         = "8002"
 	defaultWebsocketSecurePort   = "8002"
+	defaultWebsocketCloseTimeout = 10 * time.Second
 	defaultWebsocketDialTimeout  = 1 * time.Second
 	defaultWebsocketReadTimeout  = 30 * time.Second
 	defaultWebsocketWriteTimeout = 30 * time.Second
+	defaultWebsocketChannel      = "samsung.remote.control"
 )
 
 type WebsocketKeyState string
@@ -31,6 +37,13 @@ const (
 	WebsocketKeyStateRelease WebsocketKeyState = "Release"
 )
 
+type WebsocketMouseButton string
+
+const (
+	WebsocketMouseButtonLeft  WebsocketMouseButton = "LeftClick"
+	WebsocketMouseButtonRight WebsocketMouseButton = "RightClick"
+)
+
 type WebsocketOpenAppActionType string
 
 const (
@@ -84,20 +97,105 @@ func WithWebsocketWriteTimeout(timeout time.Duration) WebsocketAPIOption {
 	}
 }
 
+func WithWebsocketChannel(channel string) WebsocketAPIOption {
+	return WebsocketAPIOption{
+		setter: func(client *WebsocketAPIClient) {
+			client.channel = channel
+		},
+		priority: 2,
+	}
+}
+
+func WithWebsocketReconnect(reconnect bool) WebsocketAPIOption {
+	return WebsocketAPIOption{
+		setter: func(client *WebsocketAPIClient) {
+			client.reconnect = reconnect
+		},
+		priority: 2,
+	}
+}
+
+func WithWebsocketReconnectBackoff(backoff WebsocketBackoff) WebsocketAPIOption {
+	return WebsocketAPIOption{
+		setter: func(client *WebsocketAPIClient) {
+			client.backoff = backoff
+		},
+		priority: 2,
+	}
+}
+
+// WithWebsocketClock sets the clock used for timeouts, pings and reconnection
+// delays. Network deadlines always use the system clock.
+func WithWebsocketClock(c clock.Clock) WebsocketAPIOption {
+	return WebsocketAPIOption{
+		setter: func(client *WebsocketAPIClient) {
+			client.clock = c
+		},
+		priority: 2,
+	}
+}
+
+func WithWebsocketSendPolicy(policy WebsocketSendPolicy) WebsocketAPIOption {
+	return WebsocketAPIOption{
+		setter: func(client *WebsocketAPIClient) {
+			client.sendPolicy = policy
+		},
+		priority: 2,
+	}
+}
+
+type websocketSession struct {
+	connection  *websocket.Conn
+	readerState int32
+	writerState int32
+	quit        chan struct{}
+	done        chan struct{}
+	closeOnce   sync.Once
+}
+
+// isAlive reports whether the session runs and has not been closed. The
+// goroutines of a closed session may still be winding down.
+func (s *websocketSession) isAlive() bool {
+	select {
+	case <-s.quit:
+		return false
+	default:
+	}
+
+	return atomic.LoadInt32(&s.readerState) == 1 || atomic.LoadInt32(&s.writerState) == 1
+}
+
+func (s *websocketSession) close() {
+	s.closeOnce.Do(func() {
+		close(s.quit)
+		_ = s.connection.Close()
+	})
+}
+
 type WebsocketAPIClient struct {
 	host             string
 	port             string
 	isSecure         bool
 	dialTimeout      time.Duration
 	readTimeout      time.Duration
+	clock            clock.Clock
 	writeTimeout     time.Duration
 	clientID         string
-	connection       *websocket.Conn
-	readerState      int32
-	writerState      int32
+	channel          string
+	reconnect        bool
+	backoff          WebsocketBackoff
+	sendPolicy       WebsocketSendPolicy
+	reconnectHandler WebsocketReconnectHandler
 	requestMessages  chan []byte
-	responseMessages chan []byte
-	quit             chan struct{}
+	connectionMu     sync.Mutex
+	mu               sync.Mutex
+	session          *websocketSession
+	token            string
+	supervisorStop   chan struct{}
+	reconnecting     chan struct{}
+	waiters          []*websocketWaiter
+	subscriptions    []websocketSubscription
+	subscriptionID   int
 }
 
 func NewWebsocketAPIClient(
@@ -106,14 +204,18 @@ func NewWebsocketAPIClient(
 	options ...WebsocketAPIOption,
 ) *WebsocketAPIClient {
 	client := &WebsocketAPIClient{
-		host:             host,
-		port:             defaultWebsocketPort,
-		dialTimeout:      defaultWebsocketDialTimeout,
-		readTimeout:      defaultWebsocketReadTimeout,
-		writeTimeout:     defaultWebsocketWriteTimeout,
-		clientID:         clientID,
-		requestMessages:  make(chan []byte),
-		responseMessages: make(chan []byte),
+		host:            host,
+		port:            defaultWebsocketPort,
+		dialTimeout:     defaultWebsocketDialTimeout,
+		readTimeout:     defaultWebsocketReadTimeout,
+		clock:           clock.New(),
+		writeTimeout:    defaultWebsocketWriteTimeout,
+		clientID:        clientID,
+		channel:         defaultWebsocketChannel,
+		reconnect:       true,
+		backoff:         DefaultWebsocketBackoff,
+		sendPolicy:      WebsocketSendPolicyFail,
+		requestMessages: make(chan []byte),
 	}
 
 	sort.Slice(options, func(i, j int) bool {
@@ -155,8 +257,18 @@ func (c *WebsocketAPIClient) ClientID() string {
 	return c.clientID
 }
 
+func (c *WebsocketAPIClient) Channel() string {
+	return c.channel
+}
+
 func (c *WebsocketAPIClient) IsAvailable() bool {
-	connection, err := net.DialTimeout("tcp", fmt.Sprintf("%s:%s", c.host, c.port), c.dialTimeout)
+	return c.IsAvailableContext(context.Background())
+}
+
+func (c *WebsocketAPIClient) IsAvailableContext(ctx context.Context) bool {
+	dialer := net.Dialer{Timeout: c.dialTimeout}
+
+	connection, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(c.host, c.port))
 	if err != nil {
 		return false
 	}
@@ -169,68 +281,60 @@ func (c *WebsocketAPIClient) IsAvailable() bool {
 }
 
 func (c *WebsocketAPIClient) IsConnected() bool {
-	return atomic.LoadInt32(&c.readerState) == 1 || atomic.LoadInt32(&c.writerState) == 1
+	session := c.currentSession()
+
+	return session != nil && session.isAlive()
 }
 
 func (c *WebsocketAPIClient) Connect(token string) (ConnectResponseMessage, error) {
-	if c.IsConnected() {
-		return ConnectResponseMessage{}, errors.New("connection has been already opened")
-	}
-
-	dialer := *websocket.DefaultDialer
-	if c.isSecure {
-		dialer.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
-	}
-
-	dialer.NetDial = func(network, addr string) (net.Conn, error) {
-		return net.DialTimeout(network, addr, c.dialTimeout)
-	}
+	return c.ConnectContext(context.Background(), token)
+}
 
-	connection, _, err := dialer.Dial(c.generateURL(token), nil)
-	if err != nil {
-		return ConnectResponseMessage{}, err
+func (c *WebsocketAPIClient) ConnectContext(ctx context.Context, token string) (ConnectResponseMessage, error) {
+	if c.IsConnected() {
+		return ConnectResponseMessage{}, errWebsocketAlreadyOpened
 	}
 
-	c.connection = connection
-	c.quit = make(chan struct{})
+	// An explicit connection takes over from a reconnection in progress.
+	wasSupervised := c.stopSupervisor()
 
-	go func() {
-		defer func() {
-			_ = c.connection.Close()
-		}()
+	c.connectionMu.Lock()
+	defer c.connectionMu.Unlock()
 
-		_ = c.runReader()
-	}()
-
-	go func() {
-		defer func() {
-			_ = c.connection.Close()
-		}()
-
-		_ = c.runWriter()
-	}()
-
-	var message []byte
-	for {
-		message = <-c.responseMessages
-		if bytes.Contains(message, []byte("ms.channel.connect")) {
-			break
+	if c.IsConnected() {
+		// The reconnection has just succeeded, keep supervising it.
+		if wasSupervised {
+			c.startSupervisor()
 		}
+
+		return ConnectResponseMessage{}, errWebsocketAlreadyOpened
 	}
 
-	response := ConnectResponseMessage{}
-	err = json.Unmarshal(message, &response)
+	response, err := c.connect(ctx, token)
 	if err != nil {
+		// Resume the reconnection which has been taken over.
+		if wasSupervised {
+			c.startSupervisor()
+		}
+
 		return ConnectResponseMessage{}, err
 	}
 
+	if c.reconnect {
+		c.startSupervisor()
+	}
+
 	return response, nil
 }
 
 func (c *WebsocketAPIClient) GetApps() (GetAppsResponseMessage, error) {
+	return c.GetAppsContext(context.Background())
+}
+
+func (c *WebsocketAPIClient) GetAppsContext(ctx context.Context) (GetAppsResponseMessage, error) {
 	request := &GetAppsRequestMessage{}
 	request.Method = "ms.channel.emit"
-	request.Params.Event = "ed.installedApp.get"
+	request.Params.Event = string(WebsocketEventInstalledAppGet)
 	request.Params.To = "host"
 
 	requestMessage, err := json.Marshal(request)
@@ -238,7 +342,7 @@ func (c *WebsocketAPIClient) GetApps() (GetAppsResponseMessage, error) {
 		return GetAppsResponseMessage{}, err
 	}
 
-	responseMessage, err := c.sendAndWait(requestMessage)
+	responseMessage, err := c.sendAndWait(ctx, requestMessage, matchHostReply, WebsocketEventInstalledAppGet)
 	if err != nil {
 		return GetAppsResponseMessage{}, err
 	}
@@ -253,9 +357,18 @@ func (c *WebsocketAPIClient) GetApps() (GetAppsResponseMessage, error) {
 }
 
 func (c *WebsocketAPIClient) OpenApp(id string, actionType WebsocketOpenAppActionType, metaTag string) error {
+	return c.OpenAppContext(context.Background(), id, actionType, metaTag)
+}
+
+func (c *WebsocketAPIClient) OpenAppContext(
+	ctx context.Context,
+	id string,
+	actionType WebsocketOpenAppActionType,
+	metaTag string,
+) error {
 	request := &OpenAppRequestMessage{}
 	request.Method = "ms.channel.emit"
-	request.Params.Event = "ed.apps.launch"
+	request.Params.Event = string(WebsocketEventAppsLaunch)
 	request.Params.To = "host"
 	request.Params.Data.ActionType = string(actionType)
 	request.Params.Data.AppID = id
@@ -266,7 +379,7 @@ func (c *WebsocketAPIClient) OpenApp(id string, actionType WebsocketOpenAppActio
 		return err
 	}
 
-	responseMessage, err := c.sendAndWait(requestMessage)
+	responseMessage, err := c.sendAndWait(ctx, requestMessage, matchAppLaunch(id), WebsocketEventAppsLaunch)
 	if err != nil {
 		return err
 	}
@@ -294,6 +407,10 @@ func (c *WebsocketAPIClient) OpenApp(id string, actionType WebsocketOpenAppActio
 }
 
 func (c *WebsocketAPIClient) SendKey(key string, state WebsocketKeyState) error {
+	return c.SendKeyContext(context.Background(), key, state)
+}
+
+func (c *WebsocketAPIClient) SendKeyContext(ctx context.Context, key string, state WebsocketKeyState) error {
 	request := &SendKeyRequestMessage{}
 	request.Method = "ms.remote.control"
 	request.Params.Cmd = string(state)
@@ -306,7 +423,7 @@ func (c *WebsocketAPIClient) SendKey(key string, state WebsocketKeyState) error
 		return err
 	}
 
-	err = c.send(requestData)
+	err = c.send(ctx, requestData)
 	if err != nil {
 		return err
 	}
@@ -314,50 +431,215 @@ func (c *WebsocketAPIClient) SendKey(key string, state WebsocketKeyState) error
 	return nil
 }
 
+func (c *WebsocketAPIClient) SendMouseMove(dx, dy int) error {
+	return c.SendMouseMoveContext(context.Background(), dx, dy)
+}
+
+func (c *WebsocketAPIClient) SendMouseMoveContext(ctx context.Context, dx, dy int) error {
+	request := &SendMouseMoveRequestMessage{}
+	request.Method = "ms.remote.control"
+	request.Params.Cmd = "Move"
+	request.Params.Position.X = dx
+	request.Params.Position.Y = dy
+	request.Params.Position.Time = strconv.FormatInt(c.clock.Now().UnixNano()/int64(time.Millisecond), 10)
+	request.Params.TypeOfRemote = "ProcessMouseDevice"
+
+	requestData, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+
+	return c.send(ctx, requestData)
+}
+
+func (c *WebsocketAPIClient) SendMouseClick(button WebsocketMouseButton) error {
+	return c.SendMouseClickContext(context.Background(), button)
+}
+
+func (c *WebsocketAPIClient) SendMouseClickContext(ctx context.Context, button WebsocketMouseButton) error {
+	request := &SendMouseClickRequestMessage{}
+	request.Method = "ms.remote.control"
+	request.Params.Cmd = string(button)
+	request.Params.TypeOfRemote = "ProcessMouseDevice"
+
+	requestData, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+
+	return c.send(ctx, requestData)
+}
+
+func (c *WebsocketAPIClient) SendInputString(text string) error {
+	return c.SendInputStringContext(context.Background(), text)
+}
+
+func (c *WebsocketAPIClient) SendInputStringContext(ctx context.Context, text string) error {
+	request := &SendInputStringRequestMessage{}
+	request.Method = "ms.remote.control"
+	request.Params.Cmd = base64.StdEncoding.EncodeToString([]byte(text))
+	request.Params.DataOfCmd = "base64"
+	request.Params.TypeOfRemote = "SendInputString"
+
+	requestData, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+
+	return c.send(ctx, requestData)
+}
+
+func (c *WebsocketAPIClient) SendInputEnd() error {
+	return c.SendInputEndContext(context.Background())
+}
+
+func (c *WebsocketAPIClient) SendInputEndContext(ctx context.Context) error {
+	request := &SendInputEndRequestMessage{}
+	request.Method = "ms.remote.control"
+	request.Params.TypeOfRemote = "SendInputEnd"
+
+	requestData, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+
+	return c.send(ctx, requestData)
+}
+
 func (c *WebsocketAPIClient) Close() error {
-	if c.connection == nil {
+	return c.CloseContext(context.Background())
+}
+
+func (c *WebsocketAPIClient) CloseContext(ctx context.Context) error {
+	c.stopSupervisor()
+
+	c.connectionMu.Lock()
+	defer c.connectionMu.Unlock()
+
+	session := c.currentSession()
+	if session == nil {
 		return nil
 	}
 
-	close(c.quit)
-	_ = c.connection.Close()
+	session.close()
 
-	maxAttempts := 10
-	delay := 1 * time.Second
+	// The reader closes done once the connection is gone.
+	timeout := c.clock.NewTimer(defaultWebsocketCloseTimeout)
+	defer timeout.Stop()
 
-	attempts := 0
-	for {
-		attempts++
+	select {
+	case <-session.done:
+		return nil
+	case <-timeout.C():
+		return errors.New("unable to close connection")
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
 
-		if !c.IsConnected() {
-			return nil
-		}
+func (c *WebsocketAPIClient) connect(ctx context.Context, token string) (ConnectResponseMessage, error) {
+	dialer := *websocket.DefaultDialer
+	if c.isSecure {
+		dialer.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+
+	dialer.NetDial = func(network, addr string) (net.Conn, error) {
+		netDialer := net.Dialer{Timeout: c.dialTimeout}
 
-		time.Sleep(delay)
+		return netDialer.DialContext(ctx, network, addr)
+	}
 
-		if attempts == maxAttempts {
-			return errors.New("unable to close connection")
-		}
+	connection, _, err := dialer.DialContext(ctx, c.generateURL(token), nil)
+	if err != nil {
+		return ConnectResponseMessage{}, err
+	}
+
+	session := &websocketSession{
+		connection: connection,
+		quit:       make(chan struct{}),
+		done:       make(chan struct{}),
 	}
+
+	waiter := c.expect(
+		session,
+		nil,
+		WebsocketEventChannelConnect,
+		WebsocketEventChannelUnauthorized,
+		WebsocketEventChannelTimeout,
+	)
+	defer c.forget(waiter)
+
+	events := make(chan EventMessage, defaultWebsocketEventsBufferSize)
+
+	go c.runDispatcher(events, session.quit)
+
+	go func() {
+		defer func() {
+			session.close()
+			close(session.done)
+		}()
+
+		_ = c.runReader(session, events)
+	}()
+
+	go func() {
+		defer session.close()
+
+		_ = c.runWriter(session)
+	}()
+
+	message, err := c.wait(ctx, waiter)
+	if err != nil {
+		session.close()
+
+		return ConnectResponseMessage{}, err
+	}
+
+	response := ConnectResponseMessage{}
+	err = json.Unmarshal(message, &response)
+	if err != nil {
+		session.close()
+
+		return ConnectResponseMessage{}, err
+	}
+
+	if response.Data.Token != "" {
+		token = response.Data.Token
+	}
+
+	c.mu.Lock()
+	c.session = session
+	c.token = token
+	c.mu.Unlock()
+
+	return response, nil
+}
+
+func (c *WebsocketAPIClient) currentSession() *websocketSession {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.session
 }
 
-func (c *WebsocketAPIClient) runReader() error {
-	atomic.StoreInt32(&c.readerState, 1)
+func (c *WebsocketAPIClient) runReader(session *websocketSession, events chan<- EventMessage) error {
+	atomic.StoreInt32(&session.readerState, 1)
 	defer func() {
-		atomic.StoreInt32(&c.readerState, 0)
+		c.failWaiters(session, errWebsocketConnectionClosed)
+		atomic.StoreInt32(&session.readerState, 0)
 	}()
 
-	err := c.connection.SetReadDeadline(time.Now().Add(c.readTimeout))
+	err := session.connection.SetReadDeadline(time.Now().Add(c.readTimeout))
 	if err != nil {
 		return err
 	}
 
-	c.connection.SetPongHandler(func(string) error {
-		return c.connection.SetReadDeadline(time.Now().Add(c.readTimeout))
+	session.connection.SetPongHandler(func(string) error {
+		return session.connection.SetReadDeadline(time.Now().Add(c.readTimeout))
 	})
 
 	for {
-		_, message, err := c.connection.ReadMessage()
+		_, message, err := session.connection.ReadMessage()
 		if err != nil {
 			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
 				return err
@@ -366,16 +648,16 @@ func (c *WebsocketAPIClient) runReader() error {
 			return nil
 		}
 
-		c.responseMessages <- message
+		c.route(session, message, events)
 	}
 }
 
-func (c *WebsocketAPIClient) runWriter() error {
-	atomic.StoreInt32(&c.writerState, 1)
-	pingTicker := time.NewTicker((c.readTimeout * 9) / 10)
+func (c *WebsocketAPIClient) runWriter(session *websocketSession) error {
+	atomic.StoreInt32(&session.writerState, 1)
+	pingTicker := c.clock.NewTicker((c.readTimeout * 9) / 10)
 	defer func() {
 		pingTicker.Stop()
-		atomic.StoreInt32(&c.writerState, 0)
+		atomic.StoreInt32(&session.writerState, 0)
 	}()
 
 	for {
@@ -385,57 +667,91 @@ func (c *WebsocketAPIClient) runWriter() error {
 				return nil
 			}
 
-			err := c.connection.SetWriteDeadline(time.Now().Add(c.writeTimeout))
+			err := session.connection.SetWriteDeadline(time.Now().Add(c.writeTimeout))
 			if err != nil {
 				return err
 			}
 
-			err = c.connection.WriteMessage(websocket.TextMessage, message)
+			err = session.connection.WriteMessage(websocket.TextMessage, message)
 			if err != nil {
 				return err
 			}
-		case <-pingTicker.C:
-			err := c.connection.SetWriteDeadline(time.Now().Add(c.writeTimeout))
+		case <-pingTicker.C():
+			err := session.connection.SetWriteDeadline(time.Now().Add(c.writeTimeout))
 			if err != nil {
 				return err
 			}
 
-			err = c.connection.WriteMessage(websocket.PingMessage, nil)
+			err = session.connection.WriteMessage(websocket.PingMessage, nil)
 			if err != nil {
 				return err
 			}
-		case <-c.quit:
+		case <-session.quit:
 			return nil
 		}
 	}
 }
 
-func (c *WebsocketAPIClient) sendAndWait(requestMessage []byte) ([]byte, error) {
-	err := c.send(requestMessage)
+func (c *WebsocketAPIClient) sendAndWait(
+	ctx context.Context,
+	requestMessage []byte,
+	match websocketMatcher,
+	events ...WebsocketEvent,
+) ([]byte, error) {
+	err := c.awaitConnection(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// The reply is expected on the session the request is sent on. Should it
+	// close meanwhile, the request fails instead of waiting for a reply of the
+	// next session.
+	waiter := c.expect(c.currentSession(), match, events...)
+	defer c.forget(waiter)
+
+	err = c.send(ctx, requestMessage)
 	if err != nil {
 		return nil, err
 	}
 
-	return c.wait()
+	return c.wait(ctx, waiter)
 }
 
-func (c *WebsocketAPIClient) send(requestMessage []byte) error {
+func (c *WebsocketAPIClient) send(ctx context.Context, requestMessage []byte) error {
+	err := c.awaitConnection(ctx)
+	if err != nil {
+		return err
+	}
+
 	select {
 	case c.requestMessages <- requestMessage:
 		return nil
-	case <-time.After(c.writeTimeout):
+	case <-c.clock.After(c.writeTimeout):
 		return fmt.Errorf("request sending timeout: %s", c.writeTimeout)
+	case <-ctx.Done():
+		return ctx.Err()
 	}
 }
 
-func (c *WebsocketAPIClient) wait() ([]byte, error) {
-	for {
-		select {
-		case message := <-c.responseMessages:
-			return message, nil
-		case <-time.After(c.readTimeout):
-			return nil, fmt.Errorf("response waiting timeout: %s", c.readTimeout)
+func (c *WebsocketAPIClient) wait(ctx context.Context, waiter *websocketWaiter) ([]byte, error) {
+	select {
+	case response := <-waiter.responses:
+		if response.err != nil {
+			return nil, response.err
 		}
+
+		switch response.event {
+		case WebsocketEventChannelUnauthorized:
+			return nil, errors.New("connection is unauthorized")
+		case WebsocketEventChannelTimeout:
+			return nil, errors.New("connection authorization timeout")
+		}
+
+		return response.message, nil
+	case <-c.clock.After(c.readTimeout):
+		return nil, fmt.Errorf("response waiting timeout: %s", c.readTimeout)
+	case <-ctx.Done():
+		return nil, ctx.Err()
 	}
 }
 
@@ -446,10 +762,11 @@ func (c *WebsocketAPIClient) generateURL(token string) string {
 	}
 
 	return fmt.Sprintf(
-		"%s://%s:%s/api/v2/channels/samsung.remote.control?name=%s&token=%s",
+		"%s://%s:%s/api/v2/channels/%s?name=%s&token=%s",
 		schema,
 		c.host,
 		c.port,
+		c.channel,
 		base64.URLEncoding.EncodeToString([]byte(c.clientID)),
 		token,
 	)
@@ -0,0 +1,174 @@
+package tizenapi_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/kpeu3i/go-tizen-tv/tizenapi"
+	"github.com/kpeu3i/go-tizen-tv/tizentest"
+)
+
+func TestWebsocketAPIClientCloseDisconnects(t *testing.T) {
+	emulator := tizentest.NewEmulator()
+
+	err := emulator.Start()
+	if err != nil {
+		t.Fatalf("start emulator: %v", err)
+	}
+
+	defer func() {
+		_ = emulator.Close()
+	}()
+
+	client := tizenapi.NewWebsocketAPIClient(
+		emulator.Host(),
+		"tizentest",
+		tizenapi.WithWebsocketPort(emulator.Port()),
+	)
+
+	_, err = client.Connect("")
+	if err != nil {
+		t.Fatalf("Connect() = %v", err)
+	}
+
+	if !client.IsConnected() {
+		t.Fatal("IsConnected() = false after Connect()")
+	}
+
+	err = client.Close()
+	if err != nil {
+		t.Fatalf("Close() = %v", err)
+	}
+
+	if client.IsConnected() {
+		t.Error("IsConnected() = true after Close()")
+	}
+}
+
+func TestWebsocketAPIClientRoutesEventsDuringRequests(t *testing.T) {
+	const appID = "111299001912"
+	const otherAppID = "3201907018807"
+
+	tests := []struct {
+		name    string
+		match   string
+		request func(client *tizenapi.WebsocketAPIClient) error
+	}{
+		{
+			name:  "GetApps",
+			match: string(tizenapi.WebsocketEventInstalledAppGet),
+			request: func(client *tizenapi.WebsocketAPIClient) error {
+				response, err := client.GetApps()
+				if err != nil {
+					return err
+				}
+
+				if len(response.Data.Data) != 1 || response.Data.Data[0].AppId != appID {
+					t.Errorf("GetApps() = %+v, want app %s", response.Data.Data, appID)
+				}
+
+				return nil
+			},
+		},
+		{
+			name:  "OpenApp",
+			match: string(tizenapi.WebsocketEventAppsLaunch),
+			request: func(client *tizenapi.WebsocketAPIClient) error {
+				// The launch of the other app must not be taken for the reply.
+				return client.OpenApp(appID, tizenapi.WebsocketOpenAppActionTypeNativeLaunch, "")
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			emulator := tizentest.NewEmulator(tizentest.WithEmulatorApps(tizentest.EmulatorApp{
+				ID:   appID,
+				Name: "YouTube",
+			}))
+
+			err := emulator.Start()
+			if err != nil {
+				t.Fatalf("start emulator: %v", err)
+			}
+
+			defer func() {
+				_ = emulator.Close()
+			}()
+
+			client := tizenapi.NewWebsocketAPIClient(
+				emulator.Host(),
+				"tizentest",
+				tizenapi.WithWebsocketPort(emulator.Port()),
+			)
+
+			defer func() {
+				_ = client.Close()
+			}()
+
+			events := make(chan tizenapi.EventMessage, 8)
+			for _, event := range []tizenapi.WebsocketEvent{
+				tizenapi.WebsocketEventChannelClientConnect,
+				tizenapi.WebsocketEventAppsLaunch,
+			} {
+				client.Subscribe(event, func(message tizenapi.EventMessage) {
+					events <- message
+				})
+			}
+
+			_, err = client.Connect(emulator.Token())
+			if err != nil {
+				t.Fatalf("Connect() = %v", err)
+			}
+
+			// A phone connects and another app starts while the request waits.
+			emulator.InjectFault(tizentest.Fault{
+				Target: tizentest.FaultTargetMessage,
+				Match:  test.match,
+				Times:  1,
+				Events: []tizentest.EmulatorEvent{
+					{
+						Event: string(tizenapi.WebsocketEventChannelClientConnect),
+						Data:  map[string]interface{}{"id": "phone", "attributes": map[string]string{"name": "Phone"}},
+					},
+					{
+						Event: string(tizenapi.WebsocketEventAppsLaunch),
+						Data:  map[string]string{"appId": otherAppID},
+					},
+				},
+			})
+
+			err = test.request(client)
+			if err != nil {
+				t.Fatalf("%s() = %v", test.name, err)
+			}
+
+			received := map[string]tizenapi.EventMessage{}
+			for len(received) < 2 {
+				select {
+				case message := <-events:
+					received[message.Event] = message
+				case <-time.After(2 * time.Second):
+					t.Fatalf("events = %+v, want both events delivered to subscribers", received)
+				}
+			}
+
+			var launch struct {
+				AppID string `json:"appId"`
+			}
+
+			err = json.Unmarshal(received[string(tizenapi.WebsocketEventAppsLaunch)].Data, &launch)
+			if err != nil || launch.AppID != otherAppID {
+				t.Errorf("launch event = %s, want app %s", received[string(tizenapi.WebsocketEventAppsLaunch)].Data, otherAppID)
+			}
+
+			// The reply itself is not an event.
+			select {
+			case message := <-events:
+				t.Errorf("unexpected event %s %s", message.Event, message.Data)
+			case <-time.After(50 * time.Millisecond):
+			}
+		})
+	}
+}
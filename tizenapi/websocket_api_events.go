@@ -0,0 +1,253 @@
+package tizenapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+const defaultWebsocketEventsBufferSize = 64
+
+type WebsocketEvent string
+
+const (
+	WebsocketEventChannelConnect          WebsocketEvent = "ms.channel.connect"
+	WebsocketEventChannelClientConnect    WebsocketEvent = "ms.channel.clientConnect"
+	WebsocketEventChannelClientDisconnect WebsocketEvent = "ms.channel.clientDisconnect"
+	WebsocketEventChannelUnauthorized     WebsocketEvent = "ms.channel.unauthorized"
+	WebsocketEventChannelTimeout          WebsocketEvent = "ms.channel.timeOut"
+	WebsocketEventError                   WebsocketEvent = "ms.error"
+	WebsocketEventInstalledAppGet         WebsocketEvent = "ed.installedApp.get"
+	WebsocketEventAppsLaunch              WebsocketEvent = "ed.apps.launch"
+	WebsocketEventEdenTVUpdate            WebsocketEvent = "ed.edenTV.update"
+)
+
+var errWebsocketConnectionClosed = errors.New("connection has been closed")
+
+type WebsocketEventHandler func(message EventMessage)
+
+type websocketSubscription struct {
+	id      int
+	event   WebsocketEvent
+	handler WebsocketEventHandler
+}
+
+type websocketResponse struct {
+	event   WebsocketEvent
+	message []byte
+	err     error
+}
+
+// websocketMatcher tells whether an event is the reply a waiter expects.
+type websocketMatcher func(message EventMessage) bool
+
+type websocketWaiter struct {
+	session   *websocketSession
+	events    []WebsocketEvent
+	match     websocketMatcher
+	responses chan websocketResponse
+}
+
+// expects tells whether the event received on the session is the reply. Events
+// of other sessions never are, as the request was sent on this one.
+func (w *websocketWaiter) expects(session *websocketSession, message EventMessage) bool {
+	if w.session != session {
+		return false
+	}
+
+	for _, e := range w.events {
+		if e == WebsocketEvent(message.Event) {
+			return w.match == nil || w.match(message)
+		}
+	}
+
+	return false
+}
+
+func (w *websocketWaiter) resolve(response websocketResponse) {
+	select {
+	case w.responses <- response:
+	default:
+	}
+}
+
+// Subscribe registers a handler for events which are not a reply to a pending
+// request. Handlers are called sequentially from a single goroutine, so a slow
+// handler delays the following events. Up to 64 events wait for the handlers,
+// newer ones are dropped rather than holding up the replies to requests. The
+// returned function removes the handler.
+func (c *WebsocketAPIClient) Subscribe(event WebsocketEvent, handler WebsocketEventHandler) func() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.subscriptionID++
+	id := c.subscriptionID
+
+	c.subscriptions = append(c.subscriptions, websocketSubscription{id: id, event: event, handler: handler})
+
+	return func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		for i, subscription := range c.subscriptions {
+			if subscription.id == id {
+				c.subscriptions = append(c.subscriptions[:i], c.subscriptions[i+1:]...)
+
+				return
+			}
+		}
+	}
+}
+
+func (c *WebsocketAPIClient) expect(
+	session *websocketSession,
+	match websocketMatcher,
+	events ...WebsocketEvent,
+) *websocketWaiter {
+	waiter := &websocketWaiter{
+		session:   session,
+		events:    events,
+		match:     match,
+		responses: make(chan websocketResponse, 1),
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.waiters = append(c.waiters, waiter)
+
+	return waiter
+}
+
+func (c *WebsocketAPIClient) forget(waiter *websocketWaiter) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i, w := range c.waiters {
+		if w == waiter {
+			c.waiters = append(c.waiters[:i], c.waiters[i+1:]...)
+
+			return
+		}
+	}
+}
+
+func (c *WebsocketAPIClient) route(session *websocketSession, message []byte, events chan<- EventMessage) {
+	eventMessage := EventMessage{}
+	err := json.Unmarshal(message, &eventMessage)
+	if err != nil || eventMessage.Event == "" {
+		return
+	}
+
+	event := WebsocketEvent(eventMessage.Event)
+
+	if event == WebsocketEventError {
+		errorMessage := ErrorEventMessage{}
+		_ = json.Unmarshal(message, &errorMessage)
+
+		c.failWaiters(session, fmt.Errorf("websocket error: %s", errorMessage.Data.Message))
+	} else if c.resolveWaiter(session, eventMessage, message) {
+		return
+	}
+
+	// The reader must not wait for the handlers, it reads the replies too.
+	select {
+	case events <- eventMessage:
+	default:
+	}
+}
+
+func (c *WebsocketAPIClient) resolveWaiter(
+	session *websocketSession,
+	eventMessage EventMessage,
+	message []byte,
+) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i, waiter := range c.waiters {
+		if waiter.expects(session, eventMessage) {
+			c.waiters = append(c.waiters[:i], c.waiters[i+1:]...)
+			waiter.resolve(websocketResponse{event: WebsocketEvent(eventMessage.Event), message: message})
+
+			return true
+		}
+	}
+
+	return false
+}
+
+// failWaiters fails the requests sent on the session. Requests of a newer
+// session are left waiting for their own replies.
+func (c *WebsocketAPIClient) failWaiters(session *websocketSession, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	waiters := c.waiters[:0]
+	for _, waiter := range c.waiters {
+		if waiter.session == session {
+			waiter.resolve(websocketResponse{err: err})
+		} else {
+			waiters = append(waiters, waiter)
+		}
+	}
+
+	c.waiters = waiters
+}
+
+// matchHostReply accepts events sent by the TV itself, which is what replies to
+// ms.channel.emit are. The same events broadcast for other clients carry the
+// ID of the sender.
+func matchHostReply(message EventMessage) bool {
+	return message.From == "" || message.From == "host"
+}
+
+// matchAppLaunch accepts the reply to launching the app. The TV answers with a
+// status, while launch notifications carry the ID of the launched app, so a
+// notification about another app is not taken for the reply.
+func matchAppLaunch(id string) websocketMatcher {
+	return func(message EventMessage) bool {
+		if !matchHostReply(message) {
+			return false
+		}
+
+		var data struct {
+			AppID jsonString `json:"appId"`
+		}
+
+		err := json.Unmarshal(message.Data, &data)
+		if err != nil {
+			// Status replies are not objects.
+			return true
+		}
+
+		return data.AppID == "" || string(data.AppID) == id
+	}
+}
+
+func (c *WebsocketAPIClient) runDispatcher(events <-chan EventMessage, quit <-chan struct{}) {
+	for {
+		select {
+		case message := <-events:
+			for _, handler := range c.handlers(WebsocketEvent(message.Event)) {
+				handler(message)
+			}
+		case <-quit:
+			return
+		}
+	}
+}
+
+func (c *WebsocketAPIClient) handlers(event WebsocketEvent) []WebsocketEventHandler {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var handlers []WebsocketEventHandler
+	for _, subscription := range c.subscriptions {
+		if subscription.event == event {
+			handlers = append(handlers, subscription.handler)
+		}
+	}
+
+	return handlers
+}
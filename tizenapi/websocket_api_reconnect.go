@@ -0,0 +1,235 @@
+package tizenapi
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+type WebsocketSendPolicy int
+
+const (
+	// WebsocketSendPolicyFail rejects messages immediately while the connection is down.
+	WebsocketSendPolicyFail WebsocketSendPolicy = iota
+	// WebsocketSendPolicyHold keeps messages until the connection is re-established
+	// or the reconnection gives up.
+	WebsocketSendPolicyHold
+)
+
+var ErrWebsocketNotConnected = errors.New("connection is not established")
+
+var errWebsocketAlreadyOpened = errors.New("connection has been already opened")
+
+type WebsocketBackoff struct {
+	InitialDelay time.Duration
+	MaxDelay     time.Duration
+	Multiplier   float64
+	MaxAttempts  int // Zero means no limit
+}
+
+var DefaultWebsocketBackoff = WebsocketBackoff{
+	InitialDelay: 1 * time.Second,
+	MaxDelay:     30 * time.Second,
+	Multiplier:   2,
+}
+
+func (b WebsocketBackoff) next(delay time.Duration) time.Duration {
+	if b.Multiplier > 1 {
+		delay = time.Duration(float64(delay) * b.Multiplier)
+	}
+
+	if b.MaxDelay > 0 && delay > b.MaxDelay {
+		delay = b.MaxDelay
+	}
+
+	return delay
+}
+
+type WebsocketReconnectEvent struct {
+	Attempt  int
+	Response ConnectResponseMessage
+	Err      error
+	GaveUp   bool
+}
+
+type WebsocketReconnectHandler func(event WebsocketReconnectEvent)
+
+func (c *WebsocketAPIClient) OnReconnect(handler WebsocketReconnectHandler) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.reconnectHandler = handler
+}
+
+func (c *WebsocketAPIClient) IsReconnecting() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.reconnecting != nil
+}
+
+func (c *WebsocketAPIClient) startSupervisor() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stop := make(chan struct{})
+	c.supervisorStop = stop
+
+	go c.supervise(c.session, stop)
+}
+
+// stopSupervisor stops the supervisor and tells whether it was running.
+func (c *WebsocketAPIClient) stopSupervisor() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.supervisorStop == nil {
+		return false
+	}
+
+	close(c.supervisorStop)
+	c.supervisorStop = nil
+
+	return true
+}
+
+func (c *WebsocketAPIClient) supervise(session *websocketSession, stop <-chan struct{}) {
+	for {
+		select {
+		case <-session.done:
+		case <-stop:
+			return
+		}
+
+		session = c.reestablish(stop)
+		if session == nil {
+			return
+		}
+	}
+}
+
+func (c *WebsocketAPIClient) reestablish(stop <-chan struct{}) *websocketSession {
+	reconnecting := make(chan struct{})
+
+	c.mu.Lock()
+	c.reconnecting = reconnecting
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		c.reconnecting = nil
+		c.mu.Unlock()
+
+		close(reconnecting)
+	}()
+
+	delay := c.backoff.InitialDelay
+	for attempt := 1; ; attempt++ {
+		select {
+		case <-c.clock.After(delay):
+		case <-stop:
+			return nil
+		}
+
+		response, err := c.reconnectAttempt(stop)
+		if err != nil && isStopped(stop) {
+			return nil
+		}
+
+		gaveUp := err != nil && c.backoff.MaxAttempts > 0 && attempt >= c.backoff.MaxAttempts
+
+		c.notifyReconnect(WebsocketReconnectEvent{
+			Attempt:  attempt,
+			Response: response,
+			Err:      err,
+			GaveUp:   gaveUp,
+		})
+
+		if err == nil {
+			return c.currentSession()
+		}
+
+		if gaveUp {
+			return nil
+		}
+
+		delay = c.backoff.next(delay)
+	}
+}
+
+func (c *WebsocketAPIClient) reconnectAttempt(stop <-chan struct{}) (ConnectResponseMessage, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() {
+		select {
+		case <-stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	c.connectionMu.Lock()
+	defer c.connectionMu.Unlock()
+
+	if ctx.Err() != nil {
+		return ConnectResponseMessage{}, ctx.Err()
+	}
+
+	c.mu.Lock()
+	token := c.token
+	c.mu.Unlock()
+
+	return c.connect(ctx, token)
+}
+
+func (c *WebsocketAPIClient) notifyReconnect(event WebsocketReconnectEvent) {
+	c.mu.Lock()
+	handler := c.reconnectHandler
+	c.mu.Unlock()
+
+	if handler != nil {
+		handler(event)
+	}
+}
+
+// awaitConnection returns when the client is connected. While it reconnects,
+// the send policy tells whether to wait for it.
+func (c *WebsocketAPIClient) awaitConnection(ctx context.Context) error {
+	if c.IsConnected() {
+		return nil
+	}
+
+	return c.awaitReconnection(ctx)
+}
+
+func (c *WebsocketAPIClient) awaitReconnection(ctx context.Context) error {
+	c.mu.Lock()
+	reconnecting := c.reconnecting
+	c.mu.Unlock()
+
+	if c.sendPolicy != WebsocketSendPolicyHold || reconnecting == nil {
+		return ErrWebsocketNotConnected
+	}
+
+	select {
+	case <-reconnecting:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	if !c.IsConnected() {
+		return ErrWebsocketNotConnected
+	}
+
+	return nil
+}
+
+func isStopped(stop <-chan struct{}) bool {
+	select {
+	case <-stop:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,611 @@
+package tizentest
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/kpeu3i/go-tizen-tv/tizenapi"
+)
+
+const (
+	artChannelPath       = "/api/v2/channels/com.samsung.art-app"
+	artResponseEvent     = "d2d_service_message"
+	defaultArtServerHost = "127.0.0.1"
+	defaultArtUploadKey  = "tizentest"
+	artMaxBrightness     = 10
+)
+
+// ArtServer is a fake of the Frame TV art app channel. It keeps the art state
+// in memory and accepts uploads over a secondary socket like a real TV.
+type ArtServer struct {
+	Token string
+
+	mu                sync.Mutex
+	listener          net.Listener
+	uploadListener    net.Listener
+	server            *http.Server
+	upgrader          websocket.Upgrader
+	connections       map[*websocket.Conn]*sync.Mutex
+	uploads           map[string]artUpload
+	artMode           bool
+	brightness        int
+	colorTemperature  int
+	motionTimer       string
+	motionSensitivity int
+	contents          []tizenapi.ArtContent
+	images            map[string][]byte
+	favorites         map[string]bool
+	current           string
+	slideshow         tizenapi.ArtSlideshowStatus
+	nextContentID     int
+	requests          []string
+	dropped           map[string]bool
+}
+
+type artUpload struct {
+	requestID  string
+	connection *websocket.Conn
+	fileType   string
+	matteID    string
+}
+
+func NewArtServer() *ArtServer {
+	return &ArtServer{
+		Token:       "tizentest-token",
+		connections: map[*websocket.Conn]*sync.Mutex{},
+		uploads:     map[string]artUpload{},
+		brightness:  5,
+		motionTimer: "off",
+		images:      map[string][]byte{},
+		favorites:   map[string]bool{},
+		dropped:     map[string]bool{},
+		slideshow: tizenapi.ArtSlideshowStatus{
+			Value:      "off",
+			CategoryID: tizenapi.ArtCategoryMyPhotos,
+			Type:       "slideshow",
+		},
+		motionSensitivity: 2,
+	}
+}
+
+func (s *ArtServer) Start() error {
+	listener, err := net.Listen("tcp", net.JoinHostPort(defaultArtServerHost, "0"))
+	if err != nil {
+		return err
+	}
+
+	uploadListener, err := net.Listen("tcp", net.JoinHostPort(defaultArtServerHost, "0"))
+	if err != nil {
+		_ = listener.Close()
+
+		return err
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(artChannelPath, s.handleChannel)
+
+	s.listener = listener
+	s.uploadListener = uploadListener
+	s.server = &http.Server{Handler: mux}
+
+	go func() {
+		_ = s.server.Serve(listener)
+	}()
+
+	go s.acceptUploads()
+
+	return nil
+}
+
+func (s *ArtServer) Host() string {
+	return defaultArtServerHost
+}
+
+func (s *ArtServer) Port() string {
+	_, port, _ := net.SplitHostPort(s.listener.Addr().String())
+
+	return port
+}
+
+func (s *ArtServer) Close() error {
+	_ = s.uploadListener.Close()
+
+	return s.server.Close()
+}
+
+func (s *ArtServer) ArtMode() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.artMode
+}
+
+func (s *ArtServer) SetArtMode(on bool) {
+	s.mu.Lock()
+	s.artMode = on
+	s.mu.Unlock()
+
+	s.broadcast(map[string]interface{}{"event": "art_mode_changed", "status": onOff(on)})
+}
+
+func (s *ArtServer) Brightness() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.brightness
+}
+
+func (s *ArtServer) ColorTemperature() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.colorTemperature
+}
+
+func (s *ArtServer) MotionTimer() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.motionTimer
+}
+
+func (s *ArtServer) MotionSensitivity() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.motionSensitivity
+}
+
+func (s *ArtServer) Contents() []tizenapi.ArtContent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]tizenapi.ArtContent(nil), s.contents...)
+}
+
+func (s *ArtServer) Image(contentID string) []byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.images[contentID]
+}
+
+func (s *ArtServer) IsFavorite(contentID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.favorites[contentID]
+}
+
+func (s *ArtServer) Current() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.current
+}
+
+func (s *ArtServer) Slideshow() tizenapi.ArtSlideshowStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.slideshow
+}
+
+// AddContent stores a piece as if it had been uploaded earlier.
+func (s *ArtServer) AddContent(content tizenapi.ArtContent, image []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.contents = append(s.contents, content)
+	s.images[content.ContentID] = image
+}
+
+// DropRequests makes the server leave requests of the names unanswered, as
+// the TV does when it ignores a request.
+func (s *ArtServer) DropRequests(names ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, name := range names {
+		s.dropped[name] = true
+	}
+}
+
+// Requests returns the names of all art requests received so far.
+func (s *ArtServer) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]string(nil), s.requests...)
+}
+
+func (s *ArtServer) handleChannel(w http.ResponseWriter, r *http.Request) {
+	connection, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+
+	s.mu.Lock()
+	s.connections[connection] = &sync.Mutex{}
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.connections, connection)
+		s.mu.Unlock()
+
+		_ = connection.Close()
+	}()
+
+	connect := map[string]interface{}{
+		"event": "ms.channel.connect",
+		"data":  map[string]interface{}{"id": "tizentest", "token": s.Token},
+	}
+
+	err = s.write(connection, connect)
+	if err != nil {
+		return
+	}
+
+	for {
+		_, message, err := connection.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		var request tizenapi.ArtRequestMessage
+		err = json.Unmarshal(message, &request)
+		if err != nil || request.Params.Event != "art_app_request" {
+			continue
+		}
+
+		data := map[string]interface{}{}
+		err = json.Unmarshal([]byte(request.Params.Data), &data)
+		if err != nil {
+			continue
+		}
+
+		s.handleRequest(connection, data)
+	}
+}
+
+func (s *ArtServer) handleRequest(connection *websocket.Conn, data map[string]interface{}) {
+	name, _ := data["request"].(string)
+	id, _ := data["request_id"].(string)
+	if id == "" {
+		id, _ = data["id"].(string)
+	}
+
+	var replies []map[string]interface{}
+	reply := func(event string, fields map[string]interface{}) {
+		fields["event"] = event
+		fields["id"] = id
+		fields["request_id"] = id
+
+		replies = append(replies, fields)
+	}
+
+	defer func() {
+		for _, fields := range replies {
+			_ = s.reply(connection, fields)
+		}
+	}()
+
+	value := fmt.Sprint(data["value"])
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests = append(s.requests, name)
+
+	if s.dropped[name] {
+		return
+	}
+
+	switch name {
+	case "get_artmode_status":
+		reply("artmode_status", map[string]interface{}{"value": onOff(s.artMode)})
+	case "set_artmode_status":
+		s.artMode = value == "on"
+		reply("art_mode_changed", map[string]interface{}{"status": value})
+	case "get_brightness":
+		reply("brightness", map[string]interface{}{"value": strconv.Itoa(s.brightness)})
+	case "set_brightness":
+		brightness, err := strconv.Atoi(value)
+		if err != nil || brightness < 0 || brightness > artMaxBrightness {
+			reply("error", map[string]interface{}{"error_code": "-7"})
+
+			return
+		}
+
+		s.brightness = brightness
+		reply("brightness_changed", map[string]interface{}{"value": value})
+	case "get_color_temperature":
+		reply("color_temperature", map[string]interface{}{"value": strconv.Itoa(s.colorTemperature)})
+	case "set_color_temperature":
+		s.colorTemperature, _ = strconv.Atoi(value)
+		reply("color_temperature_changed", map[string]interface{}{"value": value})
+	case "get_motion_timer":
+		reply("motion_timer", map[string]interface{}{"value": s.motionTimer})
+	case "set_motion_timer":
+		s.motionTimer = value
+		reply("motion_timer_changed", map[string]interface{}{"value": value})
+	case "get_motion_sensitivity":
+		reply("motion_sensitivity", map[string]interface{}{"value": strconv.Itoa(s.motionSensitivity)})
+	case "set_motion_sensitivity":
+		s.motionSensitivity, _ = strconv.Atoi(value)
+		reply("motion_sensitivity_changed", map[string]interface{}{"value": value})
+	case "get_content_list":
+		category, _ := data["category"].(string)
+
+		var contents []tizenapi.ArtContent
+		for _, content := range s.contents {
+			if category == "" || content.CategoryID == category ||
+				(category == tizenapi.ArtCategoryFavorites && s.favorites[content.ContentID]) {
+				contents = append(contents, content)
+			}
+		}
+
+		contentList, _ := json.Marshal(contents)
+		reply("content_list", map[string]interface{}{"content_list": string(contentList)})
+	case "get_current_artwork":
+		for _, content := range s.contents {
+			if content.ContentID == s.current {
+				reply("current_artwork", map[string]interface{}{
+					"content_id":  content.ContentID,
+					"category_id": content.CategoryID,
+					"matte_id":    content.MatteID,
+				})
+
+				return
+			}
+		}
+
+		reply("error", map[string]interface{}{"error_code": "-1"})
+	case "select_image":
+		contentID, _ := data["content_id"].(string)
+		if !s.hasContent(contentID) {
+			reply("error", map[string]interface{}{"error_code": "-11"})
+
+			return
+		}
+
+		s.current = contentID
+		reply("image_selected", map[string]interface{}{"content_id": contentID})
+	case "change_matte":
+		contentID, _ := data["content_id"].(string)
+		if !s.hasContent(contentID) {
+			reply("error", map[string]interface{}{"error_code": "-11"})
+
+			return
+		}
+
+		matteID, _ := data["matte_id"].(string)
+		for i := range s.contents {
+			if s.contents[i].ContentID == contentID {
+				s.contents[i].MatteID = matteID
+			}
+		}
+
+		reply("matte_changed", map[string]interface{}{"content_id": contentID, "matte_id": matteID})
+	case "get_matte_list":
+		types, _ := json.Marshal([]map[string]string{{"matte_type": "none"}, {"matte_type": "shadowbox"}})
+		colors, _ := json.Marshal([]map[string]string{{"color": "polar"}, {"color": "black"}})
+		reply("matte_list", map[string]interface{}{
+			"matte_type_list":  string(types),
+			"matte_color_list": string(colors),
+		})
+	case "change_favorite":
+		contentID, _ := data["content_id"].(string)
+		if !s.hasContent(contentID) {
+			reply("error", map[string]interface{}{"error_code": "-11"})
+
+			return
+		}
+
+		status, _ := data["status"].(string)
+		s.favorites[contentID] = status == "on"
+		reply("favorite_changed", map[string]interface{}{"content_id": contentID, "status": status})
+	case "get_slideshow_status":
+		reply("slideshow_status", map[string]interface{}{
+			"value":       s.slideshow.Value,
+			"category_id": s.slideshow.CategoryID,
+			"type":        s.slideshow.Type,
+		})
+	case "set_slideshow_status":
+		if value != "off" {
+			minutes, err := strconv.Atoi(value)
+			if err != nil || minutes < 1 {
+				reply("error", map[string]interface{}{"error_code": "-7"})
+
+				return
+			}
+		}
+
+		s.slideshow.Value = value
+		s.slideshow.CategoryID, _ = data["category_id"].(string)
+		s.slideshow.Type, _ = data["type"].(string)
+		reply("slideshow_changed", map[string]interface{}{
+			"value":       s.slideshow.Value,
+			"category_id": s.slideshow.CategoryID,
+			"type":        s.slideshow.Type,
+		})
+	case "delete_image_list":
+		list, _ := data["content_id_list"].([]interface{})
+		for _, item := range list {
+			entry, _ := item.(map[string]interface{})
+			contentID, _ := entry["content_id"].(string)
+			s.deleteContent(contentID)
+		}
+
+		reply("image_deleted", map[string]interface{}{})
+	case "send_image":
+		fileType, _ := data["file_type"].(string)
+		matteID, _ := data["matte_id"].(string)
+		s.uploads[defaultArtUploadKey+id] = artUpload{
+			requestID:  id,
+			connection: connection,
+			fileType:   fileType,
+			matteID:    matteID,
+		}
+
+		_, port, _ := net.SplitHostPort(s.uploadListener.Addr().String())
+		connInfo, _ := json.Marshal(map[string]interface{}{
+			"ip":      defaultArtServerHost,
+			"port":    port,
+			"key":     defaultArtUploadKey + id,
+			"secured": false,
+		})
+
+		reply("ready_to_use", map[string]interface{}{"conn_info": string(connInfo)})
+	default:
+		reply("error", map[string]interface{}{"error_code": "-1"})
+	}
+}
+
+func (s *ArtServer) hasContent(contentID string) bool {
+	for _, content := range s.contents {
+		if content.ContentID == contentID {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *ArtServer) deleteContent(contentID string) {
+	for i, content := range s.contents {
+		if content.ContentID == contentID {
+			s.contents = append(s.contents[:i], s.contents[i+1:]...)
+			delete(s.images, contentID)
+			delete(s.favorites, contentID)
+
+			return
+		}
+	}
+}
+
+func (s *ArtServer) acceptUploads() {
+	for {
+		connection, err := s.uploadListener.Accept()
+		if err != nil {
+			return
+		}
+
+		go s.receiveUpload(connection)
+	}
+}
+
+func (s *ArtServer) receiveUpload(connection net.Conn) {
+	defer func() {
+		_ = connection.Close()
+	}()
+
+	length := make([]byte, 4)
+	_, err := io.ReadFull(connection, length)
+	if err != nil {
+		return
+	}
+
+	headerData := make([]byte, binary.BigEndian.Uint32(length))
+	_, err = io.ReadFull(connection, headerData)
+	if err != nil {
+		return
+	}
+
+	header := tizenapi.ArtUploadHeader{}
+	err = json.Unmarshal(headerData, &header)
+	if err != nil {
+		return
+	}
+
+	image := make([]byte, header.FileLength)
+	_, err = io.ReadFull(connection, image)
+	if err != nil {
+		return
+	}
+
+	s.mu.Lock()
+	upload, ok := s.uploads[header.SecKey]
+	if !ok {
+		s.mu.Unlock()
+
+		return
+	}
+
+	delete(s.uploads, header.SecKey)
+
+	s.nextContentID++
+	contentID := fmt.Sprintf("MY_F%04d", s.nextContentID)
+	s.contents = append(s.contents, tizenapi.ArtContent{
+		ContentID:   contentID,
+		CategoryID:  tizenapi.ArtCategoryMyPhotos,
+		MatteID:     upload.matteID,
+		ContentType: "mobile",
+	})
+	s.images[contentID] = image
+	s.mu.Unlock()
+
+	_ = s.reply(upload.connection, map[string]interface{}{
+		"event":      "image_added",
+		"id":         upload.requestID,
+		"request_id": upload.requestID,
+		"content_id": contentID,
+	})
+}
+
+func (s *ArtServer) broadcast(fields map[string]interface{}) {
+	s.mu.Lock()
+	connections := make([]*websocket.Conn, 0, len(s.connections))
+	for connection := range s.connections {
+		connections = append(connections, connection)
+	}
+	s.mu.Unlock()
+
+	for _, connection := range connections {
+		_ = s.reply(connection, fields)
+	}
+}
+
+func (s *ArtServer) reply(connection *websocket.Conn, fields map[string]interface{}) error {
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+
+	return s.write(connection, map[string]interface{}{
+		"event": artResponseEvent,
+		"data":  string(data),
+	})
+}
+
+func (s *ArtServer) write(connection *websocket.Conn, message interface{}) error {
+	s.mu.Lock()
+	writeMu, ok := s.connections[connection]
+	s.mu.Unlock()
+
+	if !ok {
+		return fmt.Errorf("connection is closed")
+	}
+
+	writeMu.Lock()
+	defer writeMu.Unlock()
+
+	return connection.WriteJSON(message)
+}
+
+func onOff(on bool) string {
+	if on {
+		return "on"
+	}
+
+	return "off"
+}
@@ -0,0 +1,515 @@
+package tizentest
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	samsung "github.com/kpeu3i/go-tizen-tv"
+)
+
+const (
+	defaultEmulatorHost  = "127.0.0.1"
+	defaultEmulatorMAC   = "02:00:00:00:00:01"
+	defaultEmulatorID    = "uuid:0e5f6c4a-0000-4000-8000-74657374746f"
+	defaultEmulatorName  = "[TV] tizentest"
+	defaultEmulatorModel = "QE55Q80TAUXXU"
+)
+
+// PairingMode tells how the emulator answers a websocket connection without a
+// valid token.
+type PairingMode string
+
+const (
+	PairingAccept  PairingMode = "accept"  // The user allows the connection
+	PairingDeny    PairingMode = "deny"    // The user denies the connection
+	PairingTimeout PairingMode = "timeout" // The user does not answer
+)
+
+type EmulatorApp struct {
+	ID      string
+	Name    string
+	Version string
+	AppType int
+	Running bool
+	Visible bool
+}
+
+// ReceivedKey is a key sent to the emulator over the remote control channel.
+type ReceivedKey struct {
+	Key string
+	Cmd string
+}
+
+type EmulatorOption func(emulator *Emulator)
+
+// WithEmulatorListenAddress sets the address of the HTTP and websocket API. By
+// default it is a random port on the loopback interface.
+func WithEmulatorListenAddress(address string) EmulatorOption {
+	return func(emulator *Emulator) {
+		emulator.listenAddress = address
+	}
+}
+
+// WithEmulatorSSDPAddress sets the address of the SSDP responder. A multicast
+// address, e.g. "239.255.255.250:1900", makes the emulator discoverable by
+// the real SSDP search. An empty address disables it.
+func WithEmulatorSSDPAddress(address string) EmulatorOption {
+	return func(emulator *Emulator) {
+		emulator.ssdpAddress = address
+	}
+}
+
+// WithEmulatorWoLAddress sets the address of the Wake-on-LAN listener. An empty
+// address disables it.
+func WithEmulatorWoLAddress(address string) EmulatorOption {
+	return func(emulator *Emulator) {
+		emulator.wolAddress = address
+	}
+}
+
+// WithEmulatorDevice overrides fields of the device reported by /api/v2/.
+func WithEmulatorDevice(device map[string]string) EmulatorOption {
+	return func(emulator *Emulator) {
+		for key, value := range device {
+			emulator.device[key] = value
+		}
+	}
+}
+
+func WithEmulatorPowerState(state samsung.PowerState) EmulatorOption {
+	return func(emulator *Emulator) {
+		emulator.powerState = state
+	}
+}
+
+// WithEmulatorNetworkStandby tells whether the power key puts the TV into
+// network standby, which keeps its API up, instead of turning it off.
+func WithEmulatorNetworkStandby(enabled bool) EmulatorOption {
+	return func(emulator *Emulator) {
+		emulator.networkStandby = enabled
+	}
+}
+
+// WithEmulatorBootDelay sets how long the TV takes to boot after a
+// Wake-on-LAN packet.
+func WithEmulatorBootDelay(delay time.Duration) EmulatorOption {
+	return func(emulator *Emulator) {
+		emulator.bootDelay = delay
+	}
+}
+
+func WithEmulatorToken(token string) EmulatorOption {
+	return func(emulator *Emulator) {
+		emulator.token = token
+	}
+}
+
+func WithEmulatorPairingMode(mode PairingMode) EmulatorOption {
+	return func(emulator *Emulator) {
+		emulator.pairingMode = mode
+	}
+}
+
+func WithEmulatorApps(apps ...EmulatorApp) EmulatorOption {
+	return func(emulator *Emulator) {
+		emulator.apps = append(emulator.apps, apps...)
+	}
+}
+
+// Emulator is an in-process Tizen TV. It serves the HTTP API, the remote
+// control websocket channel, SSDP and Wake-on-LAN on local sockets, and keeps
+// its state in memory so tests can inspect and change it.
+type Emulator struct {
+	listenAddress  string
+	ssdpAddress    string
+	wolAddress     string
+	networkStandby bool
+	bootDelay      time.Duration
+	pairingMode    PairingMode
+	upgrader       websocket.Upgrader
+
+	mu             sync.Mutex
+	address        string
+	listener       net.Listener
+	server         *http.Server
+	ssdpConnection net.PacketConn
+	wolConnection  net.PacketConn
+	connections    map[*websocket.Conn]*sync.Mutex
+	device         map[string]string
+	powerState     samsung.PowerState
+	token          string
+	apps           []EmulatorApp
+	keys           []ReceivedKey
+	texts          []string
+	wolPackets     int
+	searches       int
+	faults         []*fault
+	closed         bool
+}
+
+func NewEmulator(options ...EmulatorOption) *Emulator {
+	emulator := &Emulator{
+		listenAddress:  net.JoinHostPort(defaultEmulatorHost, "0"),
+		ssdpAddress:    net.JoinHostPort(defaultEmulatorHost, "0"),
+		wolAddress:     net.JoinHostPort(defaultEmulatorHost, "0"),
+		networkStandby: true,
+		pairingMode:    PairingAccept,
+		connections:    map[*websocket.Conn]*sync.Mutex{},
+		powerState:     samsung.PowerStateOn,
+		device: map[string]string{
+			"id":               defaultEmulatorID,
+			"name":             defaultEmulatorName,
+			"modelName":        defaultEmulatorModel,
+			"type":             "Samsung SmartTV",
+			"OS":               "Tizen",
+			"firmwareVersion":  "Unknown",
+			"networkType":      "wireless",
+			"wifiMac":          defaultEmulatorMAC,
+			"TokenAuthSupport": "true",
+			"FrameTVSupport":   "false",
+			"VoiceSupport":     "true",
+			"developerMode":    "0",
+		},
+	}
+
+	for _, option := range options {
+		option(emulator)
+	}
+
+	return emulator
+}
+
+// Start starts listening. The API is only reachable while the TV is not off.
+func (e *Emulator) Start() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	listener, err := net.Listen("tcp", e.listenAddress)
+	if err != nil {
+		return err
+	}
+
+	e.address = listener.Addr().String()
+
+	if e.ssdpAddress != "" {
+		e.ssdpConnection, err = listenPacket(e.ssdpAddress)
+		if err != nil {
+			_ = listener.Close()
+
+			return err
+		}
+
+		go e.serveSSDP(e.ssdpConnection)
+	}
+
+	if e.wolAddress != "" {
+		e.wolConnection, err = listenPacket(e.wolAddress)
+		if err != nil {
+			_ = listener.Close()
+			e.closePacketConnections()
+
+			return err
+		}
+
+		go e.serveWoL(e.wolConnection)
+	}
+
+	if e.powerState == samsung.PowerStateOff {
+		return listener.Close()
+	}
+
+	e.serve(listener)
+
+	return nil
+}
+
+func (e *Emulator) Close() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.closed = true
+	e.closePacketConnections()
+
+	return e.stopServing()
+}
+
+func (e *Emulator) Host() string {
+	host, _, _ := net.SplitHostPort(e.address)
+
+	return host
+}
+
+// Port returns the port of the HTTP and websocket API.
+func (e *Emulator) Port() string {
+	_, port, _ := net.SplitHostPort(e.address)
+
+	return port
+}
+
+// SSDPAddr returns the address of the SSDP responder or nil.
+func (e *Emulator) SSDPAddr() net.Addr {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.ssdpConnection == nil {
+		return nil
+	}
+
+	return e.ssdpConnection.LocalAddr()
+}
+
+// WoLAddr returns the address of the Wake-on-LAN listener or nil.
+func (e *Emulator) WoLAddr() net.Addr {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.wolConnection == nil {
+		return nil
+	}
+
+	return e.wolConnection.LocalAddr()
+}
+
+func (e *Emulator) MAC() string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.device["wifiMac"]
+}
+
+func (e *Emulator) Device() map[string]string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.deviceInfo()
+}
+
+func (e *Emulator) PowerState() samsung.PowerState {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.powerState
+}
+
+// SetPowerState changes the power state as if the user used the remote. The
+// API stops answering when the TV is off.
+func (e *Emulator) SetPowerState(state samsung.PowerState) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.setPowerState(state)
+}
+
+// Token returns the token issued to paired clients.
+func (e *Emulator) Token() string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.token
+}
+
+func (e *Emulator) SetPairingMode(mode PairingMode) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.pairingMode = mode
+}
+
+func (e *Emulator) Apps() []EmulatorApp {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return append([]EmulatorApp(nil), e.apps...)
+}
+
+func (e *Emulator) RunningApps() []EmulatorApp {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var apps []EmulatorApp
+	for _, app := range e.apps {
+		if app.Running {
+			apps = append(apps, app)
+		}
+	}
+
+	return apps
+}
+
+func (e *Emulator) InstallApp(app EmulatorApp) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for i := range e.apps {
+		if e.apps[i].ID == app.ID {
+			e.apps[i] = app
+
+			return
+		}
+	}
+
+	e.apps = append(e.apps, app)
+}
+
+// Keys returns all keys received so far.
+func (e *Emulator) Keys() []ReceivedKey {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return append([]ReceivedKey(nil), e.keys...)
+}
+
+// Texts returns all strings typed into input fields so far.
+func (e *Emulator) Texts() []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return append([]string(nil), e.texts...)
+}
+
+// WoLPackets returns the number of magic packets for the TV received so far.
+func (e *Emulator) WoLPackets() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.wolPackets
+}
+
+// Searches returns the number of SSDP searches answered so far.
+func (e *Emulator) Searches() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.searches
+}
+
+// Connections returns the number of open websocket connections.
+func (e *Emulator) Connections() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return len(e.connections)
+}
+
+func (e *Emulator) setPowerState(state samsung.PowerState) error {
+	if e.closed || state == e.powerState {
+		e.powerState = state
+
+		return nil
+	}
+
+	previous := e.powerState
+	e.powerState = state
+
+	if state == samsung.PowerStateOff {
+		return e.stopServing()
+	}
+
+	if previous == samsung.PowerStateOff {
+		listener, err := net.Listen("tcp", e.address)
+		if err != nil {
+			return err
+		}
+
+		e.serve(listener)
+	}
+
+	return nil
+}
+
+// wake turns the TV on after the boot delay.
+func (e *Emulator) wake() {
+	if e.powerState == samsung.PowerStateOn {
+		return
+	}
+
+	if e.bootDelay <= 0 {
+		_ = e.setPowerState(samsung.PowerStateOn)
+
+		return
+	}
+
+	time.AfterFunc(e.bootDelay, func() {
+		e.mu.Lock()
+		defer e.mu.Unlock()
+
+		_ = e.setPowerState(samsung.PowerStateOn)
+	})
+}
+
+// powerOff handles the power off key.
+func (e *Emulator) powerOff() {
+	if e.networkStandby {
+		_ = e.setPowerState(samsung.PowerStateStandby)
+
+		return
+	}
+
+	_ = e.setPowerState(samsung.PowerStateOff)
+}
+
+func (e *Emulator) serve(listener net.Listener) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v2/", e.handleAPI)
+	mux.HandleFunc(emulatorDescriptionPath, e.handleDescription)
+
+	e.listener = listener
+	e.server = &http.Server{Handler: mux}
+
+	go func(server *http.Server) {
+		_ = server.Serve(listener)
+	}(e.server)
+}
+
+func (e *Emulator) stopServing() error {
+	for connection := range e.connections {
+		_ = connection.Close()
+		delete(e.connections, connection)
+	}
+
+	if e.server == nil {
+		return nil
+	}
+
+	server := e.server
+	e.server = nil
+	e.listener = nil
+
+	return server.Close()
+}
+
+func (e *Emulator) closePacketConnections() {
+	if e.ssdpConnection != nil {
+		_ = e.ssdpConnection.Close()
+	}
+
+	if e.wolConnection != nil {
+		_ = e.wolConnection.Close()
+	}
+}
+
+func (e *Emulator) deviceInfo() map[string]string {
+	device := make(map[string]string, len(e.device)+2)
+	for key, value := range e.device {
+		device[key] = value
+	}
+
+	device["ip"] = e.Host()
+	device["PowerState"] = string(e.powerState)
+
+	return device
+}
+
+func (e *Emulator) write(connection *websocket.Conn, message interface{}) error {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	return e.writeRaw(connection, data)
+}
@@ -0,0 +1,356 @@
+package tizentest
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	samsung "github.com/kpeu3i/go-tizen-tv"
+	"github.com/kpeu3i/go-tizen-tv/tizenapi"
+)
+
+const (
+	emulatorAPIPath          = "/api/v2/"
+	emulatorAppsPath         = "/api/v2/applications/"
+	emulatorChannelsPath     = "/api/v2/channels/"
+	emulatorRemoteChannel    = "samsung.remote.control"
+	emulatorDescriptionPath  = "/dmr"
+	emulatorConnectionClient = "tizentest"
+)
+
+var errConnectionClosed = errors.New("connection is closed")
+
+func (e *Emulator) handleAPI(w http.ResponseWriter, r *http.Request) {
+	target := FaultTarget("")
+	switch {
+	case r.URL.Path == emulatorAPIPath:
+		target = FaultTargetInfo
+	case strings.HasPrefix(r.URL.Path, emulatorAppsPath):
+		target = FaultTargetApp
+	}
+
+	if f, ok := e.takeFault(target, r.Method); ok && applyHTTPFault(w, f) {
+		return
+	}
+
+	switch {
+	case r.URL.Path == emulatorAPIPath:
+		e.handleInfo(w, r)
+	case strings.HasPrefix(r.URL.Path, emulatorAppsPath):
+		e.handleApp(w, r, strings.TrimPrefix(r.URL.Path, emulatorAppsPath))
+	case strings.HasPrefix(r.URL.Path, emulatorChannelsPath):
+		e.handleChannel(w, r, strings.TrimPrefix(r.URL.Path, emulatorChannelsPath))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (e *Emulator) handleInfo(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+
+		return
+	}
+
+	e.mu.Lock()
+	device := e.deviceInfo()
+	e.mu.Unlock()
+
+	isSupport, _ := json.Marshal(map[string]string{
+		"remote":                "true",
+		"remote_available":      "true",
+		"remote_fourDirections": "true",
+		"remote_touchPad":       "true",
+		"remote_voiceControl":   "true",
+		"DMP_available":         "true",
+		"DMP_DRM_PLAYREADY":     "false",
+		"DMP_DRM_WIDEVINE":      "false",
+		"EDEN_available":        "true",
+		"FrameTVSupport":        device["FrameTVSupport"],
+	})
+
+	writeJSON(w, tizenapi.GetInfoResponse{
+		ID:        device["id"],
+		Type:      "Samsung SmartTV",
+		Name:      device["name"],
+		Version:   "2.0.25",
+		URI:       fmt.Sprintf("http://%s%s", r.Host, emulatorAPIPath),
+		Remote:    "1.0",
+		Device:    device,
+		IsSupport: string(isSupport),
+	})
+}
+
+func (e *Emulator) handleApp(w http.ResponseWriter, r *http.Request, id string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	index := -1
+	for i, app := range e.apps {
+		if app.ID == id {
+			index = i
+		}
+	}
+
+	if index < 0 {
+		http.NotFound(w, r)
+
+		return
+	}
+
+	app := &e.apps[index]
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, tizenapi.GetAppResponse{
+			ID:      app.ID,
+			Name:    app.Name,
+			Running: app.Running,
+			Visible: app.Visible,
+			Version: app.Version,
+		})
+	case http.MethodPost:
+		e.launchApp(index)
+		writeJSON(w, true)
+	case http.MethodPut:
+		writeJSON(w, true)
+	case http.MethodDelete:
+		app.Running = false
+		app.Visible = false
+		writeJSON(w, true)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// launchApp runs the app in the foreground and moves the others to the
+// background.
+func (e *Emulator) launchApp(index int) {
+	for i := range e.apps {
+		e.apps[i].Visible = false
+	}
+
+	e.apps[index].Running = true
+	e.apps[index].Visible = true
+}
+
+func (e *Emulator) handleChannel(w http.ResponseWriter, r *http.Request, channel string) {
+	if channel != emulatorRemoteChannel {
+		http.NotFound(w, r)
+
+		return
+	}
+
+	f, hasFault := e.takeFault(FaultTargetConnect, "")
+	if hasFault && f.Status != 0 {
+		time.Sleep(f.Latency)
+		http.Error(w, http.StatusText(f.Status), f.Status)
+
+		return
+	}
+
+	connection, err := e.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+
+	e.mu.Lock()
+	e.connections[connection] = &sync.Mutex{}
+	token, mode := e.token, e.pairingMode
+	e.mu.Unlock()
+
+	defer func() {
+		e.mu.Lock()
+		delete(e.connections, connection)
+		e.mu.Unlock()
+
+		_ = connection.Close()
+	}()
+
+	if hasFault && e.applyWebsocketFault(connection, f) {
+		return
+	}
+
+	requestToken := r.URL.Query().Get("token")
+	if token == "" || requestToken != token {
+		switch mode {
+		case PairingDeny:
+			_ = e.write(connection, map[string]interface{}{"event": tizenapi.WebsocketEventChannelUnauthorized})
+
+			return
+		case PairingTimeout:
+			_ = e.write(connection, map[string]interface{}{"event": tizenapi.WebsocketEventChannelTimeout})
+
+			return
+		}
+
+		e.mu.Lock()
+		if e.token == "" {
+			e.token = generateToken()
+		}
+		token = e.token
+		e.mu.Unlock()
+	}
+
+	connect := tizenapi.ConnectResponseMessage{Event: string(tizenapi.WebsocketEventChannelConnect)}
+	connect.Data.ID = emulatorConnectionClient
+	connect.Data.Token = token
+
+	err = e.write(connection, connect)
+	if err != nil {
+		return
+	}
+
+	for {
+		_, message, err := connection.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		e.handleMessage(connection, message)
+	}
+}
+
+func (e *Emulator) handleMessage(connection *websocket.Conn, message []byte) {
+	var request struct {
+		Method string `json:"method"`
+		Params struct {
+			Cmd          string `json:"Cmd"`
+			DataOfCmd    string `json:"DataOfCmd"`
+			TypeOfRemote string `json:"TypeOfRemote"`
+			Event        string `json:"event"`
+			Data         struct {
+				AppID string `json:"appId"`
+			} `json:"data"`
+		} `json:"params"`
+	}
+
+	err := json.Unmarshal(message, &request)
+	if err != nil {
+		return
+	}
+
+	match := request.Params.Event
+	if match == "" {
+		match = request.Method
+	}
+
+	if f, ok := e.takeFault(FaultTargetMessage, match); ok && e.applyWebsocketFault(connection, f) {
+		return
+	}
+
+	switch request.Method {
+	case "ms.remote.control":
+		e.handleRemoteControl(request.Params.TypeOfRemote, request.Params.Cmd, request.Params.DataOfCmd)
+	case "ms.channel.emit":
+		switch tizenapi.WebsocketEvent(request.Params.Event) {
+		case tizenapi.WebsocketEventInstalledAppGet:
+			_ = e.write(connection, e.installedApps())
+		case tizenapi.WebsocketEventAppsLaunch:
+			e.mu.Lock()
+			for i, app := range e.apps {
+				if app.ID == request.Params.Data.AppID {
+					e.launchApp(i)
+				}
+			}
+			e.mu.Unlock()
+
+			_ = e.write(connection, map[string]interface{}{
+				"event": tizenapi.WebsocketEventAppsLaunch,
+				"from":  "host",
+				"data":  200,
+			})
+		}
+	}
+}
+
+func (e *Emulator) handleRemoteControl(typeOfRemote, cmd, data string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	switch typeOfRemote {
+	case "SendRemoteKey":
+		e.keys = append(e.keys, ReceivedKey{Key: data, Cmd: cmd})
+
+		if cmd == string(tizenapi.WebsocketKeyStateRelease) {
+			return
+		}
+
+		switch data {
+		case "KEY_POWER":
+			if e.powerState == samsung.PowerStateOn {
+				e.powerOff()
+			} else {
+				e.wake()
+			}
+		case "KEY_POWEROFF":
+			if e.powerState == samsung.PowerStateOn {
+				e.powerOff()
+			}
+		case "KEY_POWERON":
+			e.wake()
+		}
+	case "SendInputString":
+		e.texts = append(e.texts, data)
+	}
+}
+
+func (e *Emulator) installedApps() tizenapi.GetAppsResponseMessage {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	response := tizenapi.GetAppsResponseMessage{
+		Event: string(tizenapi.WebsocketEventInstalledAppGet),
+		From:  "host",
+	}
+
+	for _, app := range e.apps {
+		response.Data.Data = append(response.Data.Data, tizenapi.InstalledApp{
+			AppId:   app.ID,
+			AppType: app.AppType,
+			Name:    app.Name,
+		})
+	}
+
+	return response
+}
+
+func (e *Emulator) handleDescription(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	device := e.deviceInfo()
+	e.mu.Unlock()
+
+	w.Header().Set("Content-Type", `text/xml; charset="utf-8"`)
+	_, _ = fmt.Fprintf(w, `<?xml version="1.0"?>
+<root xmlns="urn:schemas-upnp-org:device-1-0">
+  <specVersion><major>1</major><minor>0</minor></specVersion>
+  <device>
+    <deviceType>urn:schemas-upnp-org:device:MediaRenderer:1</deviceType>
+    <friendlyName>%s</friendlyName>
+    <manufacturer>Samsung Electronics</manufacturer>
+    <modelName>%s</modelName>
+    <UDN>%s</UDN>
+  </device>
+</root>
+`, device["name"], device["modelName"], device["id"])
+}
+
+func writeJSON(w http.ResponseWriter, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+
+	_ = json.NewEncoder(w).Encode(value)
+}
+
+func generateToken() string {
+	b := make([]byte, 4)
+	_, _ = rand.Read(b)
+
+	return fmt.Sprintf("%d", uint32(b[0])<<24|uint32(b[1])<<16|uint32(b[2])<<8|uint32(b[3]))
+}
@@ -0,0 +1,302 @@
+package tizentest
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	samsung "github.com/kpeu3i/go-tizen-tv"
+)
+
+// FaultTarget selects the requests a fault applies to.
+type FaultTarget string
+
+const (
+	FaultTargetInfo    FaultTarget = "info"    // GET /api/v2/
+	FaultTargetApp     FaultTarget = "app"     // /api/v2/applications/{id}
+	FaultTargetConnect FaultTarget = "connect" // Websocket channel connection
+	FaultTargetMessage FaultTarget = "message" // Websocket messages from the client
+)
+
+// Fault describes how the emulator misbehaves. The effects are applied in the
+// order of the fields: the latency first, then the events, then the first of
+// closing the connection, dropping the reply, answering with the status or
+// answering with the body.
+type Fault struct {
+	Target FaultTarget
+	// Match restricts a message fault to a method or an event, e.g.
+	// "ms.remote.control" or "ed.installedApp.get", and an HTTP fault to a
+	// request method. Empty matches all.
+	Match string
+	// Times is how many requests the fault applies to. Zero means all.
+	Times   int
+	Latency time.Duration
+	// Events are sent to the client before the reply.
+	Events          []EmulatorEvent
+	CloseConnection bool
+	Drop            bool
+	Status          int
+	// Body replaces the reply, e.g. with malformed JSON.
+	Body string
+}
+
+// EmulatorEvent is an unsolicited websocket event.
+type EmulatorEvent struct {
+	Event string
+	Data  interface{}
+}
+
+// ScenarioStep runs an action after a delay from the previous step.
+type ScenarioStep struct {
+	After time.Duration
+	Do    func(e *Emulator)
+}
+
+type fault struct {
+	Fault
+	remaining int
+}
+
+// InjectFault adds a fault. The returned function removes it.
+func (e *Emulator) InjectFault(f Fault) func() {
+	injected := &fault{Fault: f, remaining: f.Times}
+
+	e.mu.Lock()
+	e.faults = append(e.faults, injected)
+	e.mu.Unlock()
+
+	return func() {
+		e.mu.Lock()
+		defer e.mu.Unlock()
+
+		e.removeFault(injected)
+	}
+}
+
+func (e *Emulator) ClearFaults() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.faults = nil
+}
+
+// Emit sends an unsolicited event to all connected clients.
+func (e *Emulator) Emit(event EmulatorEvent) {
+	e.mu.Lock()
+	connections := make([]*websocket.Conn, 0, len(e.connections))
+	for connection := range e.connections {
+		connections = append(connections, connection)
+	}
+	e.mu.Unlock()
+
+	for _, connection := range connections {
+		_ = e.write(connection, event.message())
+	}
+}
+
+// DropConnections closes all websocket connections as if the network failed.
+func (e *Emulator) DropConnections() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for connection := range e.connections {
+		_ = connection.Close()
+		delete(e.connections, connection)
+	}
+}
+
+// RotateToken issues a new token. Clients with the old token have to pair
+// again.
+func (e *Emulator) RotateToken() string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.token = generateToken()
+
+	return e.token
+}
+
+// Restart turns the TV off and back on after the downtime, as if it rebooted
+// in the middle of a session. It returns immediately.
+func (e *Emulator) Restart(downtime time.Duration) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	state := e.powerState
+	if state == samsung.PowerStateOff {
+		return nil
+	}
+
+	err := e.setPowerState(samsung.PowerStateOff)
+	if err != nil {
+		return err
+	}
+
+	time.AfterFunc(downtime, func() {
+		e.mu.Lock()
+		defer e.mu.Unlock()
+
+		_ = e.setPowerState(state)
+	})
+
+	return nil
+}
+
+// Play runs the steps in the background. The returned function stops the
+// remaining steps and waits for the running one.
+func (e *Emulator) Play(steps ...ScenarioStep) func() {
+	quit := make(chan struct{})
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		for _, step := range steps {
+			timer := time.NewTimer(step.After)
+
+			select {
+			case <-timer.C:
+			case <-quit:
+				timer.Stop()
+
+				return
+			}
+
+			step.Do(e)
+		}
+	}()
+
+	var once sync.Once
+
+	return func() {
+		once.Do(func() {
+			close(quit)
+		})
+
+		<-done
+	}
+}
+
+// takeFault returns the first fault for the request and counts it.
+func (e *Emulator) takeFault(target FaultTarget, match string) (Fault, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for _, f := range e.faults {
+		if f.Target != target || (f.Match != "" && f.Match != match) {
+			continue
+		}
+
+		if f.Times > 0 {
+			f.remaining--
+			if f.remaining <= 0 {
+				e.removeFault(f)
+			}
+		}
+
+		return f.Fault, true
+	}
+
+	return Fault{}, false
+}
+
+func (e *Emulator) removeFault(f *fault) {
+	for i, injected := range e.faults {
+		if injected == f {
+			e.faults = append(e.faults[:i], e.faults[i+1:]...)
+
+			return
+		}
+	}
+}
+
+// applyHTTPFault applies the fault to an HTTP request. It returns true when the
+// request has been answered.
+func applyHTTPFault(w http.ResponseWriter, f Fault) bool {
+	time.Sleep(f.Latency)
+
+	switch {
+	case f.CloseConnection || f.Drop:
+		hijacker, ok := w.(http.Hijacker)
+		if ok {
+			connection, _, err := hijacker.Hijack()
+			if err == nil {
+				_ = connection.Close()
+
+				return true
+			}
+		}
+
+		http.Error(w, "connection closed", http.StatusServiceUnavailable)
+
+		return true
+	case f.Status != 0:
+		body := f.Body
+		if body == "" {
+			body = http.StatusText(f.Status)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(f.Status)
+		_, _ = w.Write([]byte(body))
+
+		return true
+	case f.Body != "":
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(f.Body))
+
+		return true
+	}
+
+	return false
+}
+
+// applyWebsocketFault applies the fault to a websocket exchange. It returns
+// true when the normal reply must not be sent.
+func (e *Emulator) applyWebsocketFault(connection *websocket.Conn, f Fault) bool {
+	time.Sleep(f.Latency)
+
+	for _, event := range f.Events {
+		_ = e.write(connection, event.message())
+	}
+
+	switch {
+	case f.CloseConnection:
+		_ = connection.Close()
+
+		return true
+	case f.Drop:
+		return true
+	case f.Body != "":
+		_ = e.writeRaw(connection, []byte(f.Body))
+
+		return true
+	}
+
+	return false
+}
+
+func (event EmulatorEvent) message() map[string]interface{} {
+	return map[string]interface{}{
+		"event": event.Event,
+		"from":  "host",
+		"data":  event.Data,
+	}
+}
+
+func (e *Emulator) writeRaw(connection *websocket.Conn, data []byte) error {
+	e.mu.Lock()
+	writeMu, ok := e.connections[connection]
+	e.mu.Unlock()
+
+	if !ok {
+		return errConnectionClosed
+	}
+
+	writeMu.Lock()
+	defer writeMu.Unlock()
+
+	return connection.WriteMessage(websocket.TextMessage, data)
+}
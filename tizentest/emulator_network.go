@@ -0,0 +1,147 @@
+package tizentest
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	samsung "github.com/kpeu3i/go-tizen-tv"
+)
+
+const (
+	ssdpMaxMessageSize = 2048
+	wolMaxPacketSize   = 256
+)
+
+// emulatorSearchTargets are the devices a Tizen TV announces over SSDP.
+var emulatorSearchTargets = []string{
+	"upnp:rootdevice",
+	"urn:schemas-upnp-org:device:MediaRenderer:1",
+	"urn:samsung.com:device:RemoteControlReceiver:1",
+	"urn:dial-multiscreen-org:service:dial:1",
+}
+
+// listenPacket listens on a UDP address. Multicast addresses join the group on
+// all interfaces.
+func listenPacket(address string) (net.PacketConn, error) {
+	udpAddress, err := net.ResolveUDPAddr("udp", address)
+	if err != nil {
+		return nil, err
+	}
+
+	if udpAddress.IP.IsMulticast() {
+		return net.ListenMulticastUDP("udp", nil, udpAddress)
+	}
+
+	return net.ListenUDP("udp", udpAddress)
+}
+
+func (e *Emulator) serveSSDP(connection net.PacketConn) {
+	buffer := make([]byte, ssdpMaxMessageSize)
+
+	for {
+		n, remote, err := connection.ReadFrom(buffer)
+		if err != nil {
+			return
+		}
+
+		request, err := http.ReadRequest(bufio.NewReader(bytes.NewReader(buffer[:n])))
+		if err != nil || request.Method != "M-SEARCH" {
+			continue
+		}
+
+		e.mu.Lock()
+		isOff := e.powerState == samsung.PowerStateOff
+		if !isOff {
+			e.searches++
+		}
+		e.mu.Unlock()
+
+		if isOff {
+			continue
+		}
+
+		for _, target := range matchSearchTargets(request.Header.Get("ST")) {
+			_, _ = connection.WriteTo([]byte(e.searchResponse(target)), remote)
+		}
+	}
+}
+
+func (e *Emulator) searchResponse(target string) string {
+	e.mu.Lock()
+	id := e.device["id"]
+	e.mu.Unlock()
+
+	usn := id
+	if target != id {
+		usn = id + "::" + target
+	}
+
+	return strings.Join([]string{
+		"HTTP/1.1 200 OK",
+		"CACHE-CONTROL: max-age=1800",
+		"EXT:",
+		fmt.Sprintf("LOCATION: http://%s%s", e.address, emulatorDescriptionPath),
+		"SERVER: SHP, UPnP/1.0, Samsung UPnP SDK/1.0",
+		"ST: " + target,
+		"USN: " + usn,
+		"", "",
+	}, "\r\n")
+}
+
+func matchSearchTargets(target string) []string {
+	if target == "ssdp:all" {
+		return emulatorSearchTargets
+	}
+
+	for _, t := range emulatorSearchTargets {
+		if t == target {
+			return []string{t}
+		}
+	}
+
+	return nil
+}
+
+func (e *Emulator) serveWoL(connection net.PacketConn) {
+	buffer := make([]byte, wolMaxPacketSize)
+
+	for {
+		n, _, err := connection.ReadFrom(buffer)
+		if err != nil {
+			return
+		}
+
+		mac, ok := parseMagicPacket(buffer[:n])
+		if !ok {
+			continue
+		}
+
+		e.mu.Lock()
+		if strings.EqualFold(mac.String(), e.device["wifiMac"]) {
+			e.wolPackets++
+			e.wake()
+		}
+		e.mu.Unlock()
+	}
+}
+
+// parseMagicPacket returns the MAC of a magic packet. A SecureOn password
+// after the MACs is ignored.
+func parseMagicPacket(packet []byte) (net.HardwareAddr, bool) {
+	if len(packet) < 102 || !bytes.Equal(packet[:6], bytes.Repeat([]byte{0xff}, 6)) {
+		return nil, false
+	}
+
+	mac := packet[6:12]
+	for i := 1; i < 16; i++ {
+		if !bytes.Equal(packet[6+i*6:12+i*6], mac) {
+			return nil, false
+		}
+	}
+
+	return net.HardwareAddr(append([]byte(nil), mac...)), true
+}
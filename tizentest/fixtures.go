@@ -0,0 +1,367 @@
+package tizentest
+
+// FixtureKind tells which API a fixture is a payload of.
+type FixtureKind string
+
+const (
+	FixtureInfo          FixtureKind = "info"           // GET /api/v2/
+	FixtureApp           FixtureKind = "app"            // GET /api/v2/applications/{id}
+	FixtureOpenApp       FixtureKind = "open_app"       // POST /api/v2/applications/{id}
+	FixtureConnect       FixtureKind = "connect"        // ms.channel.connect event
+	FixtureInstalledApps FixtureKind = "installed_apps" // ed.installedApp.get event
+)
+
+// FixtureVersion is incremented whenever fixtures are added or changed, so
+// tests depending on the corpus can tell which one they run against.
+const FixtureVersion = 1
+
+// Fixture is an anonymised API payload modelled on TVs of a model year. Names,
+// IDs, MACs, addresses and tokens are made up; field names, value types and
+// quirks such as isSupport being a string are what the parsers must handle.
+type Fixture struct {
+	Name      string
+	ModelYear int
+	Series    string
+	Firmware  string
+	Kind      FixtureKind
+	Body      string
+}
+
+// Fixtures returns the fixtures of the kind, or all of them for an empty kind,
+// ordered by model year.
+func Fixtures(kind FixtureKind) []Fixture {
+	var result []Fixture
+	for _, fixture := range fixtures {
+		if kind == "" || fixture.Kind == kind {
+			result = append(result, fixture)
+		}
+	}
+
+	return result
+}
+
+// FixturesOfYear returns the fixtures of a model year.
+func FixturesOfYear(year int) []Fixture {
+	var result []Fixture
+	for _, fixture := range fixtures {
+		if fixture.ModelYear == year {
+			result = append(result, fixture)
+		}
+	}
+
+	return result
+}
+
+var fixtures = []Fixture{
+	// 2016, K series, Tizen 2.4: no token authentication and no power state.
+	{
+		Name:      "2016-k-info",
+		ModelYear: 2016,
+		Series:    "K",
+		Firmware:  "T-HKMDEUC-1250.3",
+		Kind:      FixtureInfo,
+		Body: `{"device":{"FrameTVSupport":"false","GamePadSupport":"false","ImeSyncedSupport":"false",` +
+			`"OS":"Tizen","VoiceSupport":"false","countryCode":"GB","description":"Samsung DTV RCR",` +
+			`"developerIP":"0.0.0.0","developerMode":"0","duid":"uuid:00000000-0000-4000-8000-000000002016",` +
+			`"firmwareVersion":"Unknown","id":"uuid:00000000-0000-4000-8000-000000002016","ip":"192.0.2.16",` +
+			`"model":"16_JAZZM_UHD","modelName":"UE49KU6400","name":"[TV] Living Room","networkType":"wireless",` +
+			`"resolution":"3840x2160","smartHubAgreement":"true","ssid":"00:00:5e:00:53:16","type":"Samsung SmartTV",` +
+			`"udn":"uuid:00000000-0000-4000-8000-000000002016","wifiMac":"02:00:5e:00:20:16"},` +
+			`"id":"uuid:00000000-0000-4000-8000-000000002016","isSupport":"{\"DMP_DRM_PLAYREADY\":\"false\",` +
+			`\"DMP_DRM_WIDEVINE\":\"false\",\"DMP_available\":\"true\",\"EDEN_available\":\"true\",` +
+			`\"FrameTVSupport\":\"false\",\"ImeSyncedSupport\":\"false\",\"TokenAuthSupport\":\"false\",` +
+			`\"remote\":\"true\",\"remote_available\":\"true\",\"remote_fourDirections\":\"true\",` +
+			`\"remote_touchPad\":\"true\",\"remote_voiceControl\":\"false\"}\n","name":"[TV] Living Room",` +
+			`"remote":"1.0","type":"Samsung SmartTV","uri":"http://192.0.2.16:8001/api/v2/","version":"2.0.25"}`,
+	},
+	{
+		Name:      "2016-k-app",
+		ModelYear: 2016,
+		Series:    "K",
+		Kind:      FixtureApp,
+		Body:      `{"id":"111299001912","name":"YouTube","running":false,"version":"2.1.0","visible":false}`,
+	},
+	{
+		Name:      "2016-k-open-app",
+		ModelYear: 2016,
+		Series:    "K",
+		Kind:      FixtureOpenApp,
+		Body:      `true`,
+	},
+	{
+		Name:      "2016-k-connect",
+		ModelYear: 2016,
+		Series:    "K",
+		Kind:      FixtureConnect,
+		Body: `{"data":{"clients":[{"attributes":{"name":"R29UaXplblRW"},"connectTime":1462000000000,` +
+			`"deviceName":"R29UaXplblRW","id":"0000a16f-0000-4000-8000-000000002016","isHost":false}],` +
+			`"id":"0000a16f-0000-4000-8000-000000002016"},"event":"ms.channel.connect"}`,
+	},
+	{
+		Name:      "2016-k-installed-apps",
+		ModelYear: 2016,
+		Series:    "K",
+		Kind:      FixtureInstalledApps,
+		Body: `{"data":{"data":[{"appId":"111299001912","app_type":2,` +
+			`"icon":"/opt/share/webappservice/apps_icon/FirstScreen/111299001912/250x250.png","name":"YouTube"},` +
+			`{"appId":"org.tizen.browser","app_type":4,"icon":"","name":"Internet"}]},` +
+			`"event":"ed.installedApp.get","from":"host"}`,
+	},
+	// 2017, M series: same shape as 2016 with voice support.
+	{
+		Name:      "2017-m-info",
+		ModelYear: 2017,
+		Series:    "M",
+		Firmware:  "T-KTMDEUC-1280.5",
+		Kind:      FixtureInfo,
+		Body: `{"device":{"FrameTVSupport":"false","GamePadSupport":"true","ImeSyncedSupport":"true",` +
+			`"OS":"Tizen","TokenAuthSupport":"false","VoiceSupport":"true","countryCode":"DE",` +
+			`"description":"Samsung DTV RCR","developerIP":"0.0.0.0","developerMode":"0",` +
+			`"duid":"uuid:00000000-0000-4000-8000-000000002017","firmwareVersion":"Unknown",` +
+			`"id":"uuid:00000000-0000-4000-8000-000000002017","ip":"192.0.2.17","model":"17_KANTM_UHD",` +
+			`"modelName":"UE55MU6100","name":"[TV] Bedroom","networkType":"wired","resolution":"3840x2160",` +
+			`"smartHubAgreement":"true","type":"Samsung SmartTV","udn":"uuid:00000000-0000-4000-8000-000000002017",` +
+			`"wifiMac":"02:00:5e:00:20:17"},"id":"uuid:00000000-0000-4000-8000-000000002017",` +
+			`"isSupport":"{\"DMP_DRM_PLAYREADY\":\"false\",\"DMP_DRM_WIDEVINE\":\"false\",\"DMP_available\":\"true\",` +
+			`\"EDEN_available\":\"true\",\"FrameTVSupport\":\"false\",\"ImeSyncedSupport\":\"true\",` +
+			`\"TokenAuthSupport\":\"false\",\"remote\":\"true\",\"remote_available\":\"true\",` +
+			`\"remote_fourDirections\":\"true\",\"remote_touchPad\":\"true\",\"remote_voiceControl\":\"true\"}\n",` +
+			`"name":"[TV] Bedroom","remote":"1.0","type":"Samsung SmartTV",` +
+			`"uri":"http://192.0.2.17:8001/api/v2/","version":"2.0.25"}`,
+	},
+	// 2018, N series: token authentication over wss on port 8002.
+	{
+		Name:      "2018-n-info",
+		ModelYear: 2018,
+		Series:    "N",
+		Firmware:  "T-KTSUDEUC-1310.4",
+		Kind:      FixtureInfo,
+		Body: `{"device":{"FrameTVSupport":"false","GamePadSupport":"true","ImeSyncedSupport":"true",` +
+			`"OS":"Tizen","TokenAuthSupport":"true","VoiceSupport":"true","countryCode":"NL",` +
+			`"description":"Samsung DTV RCR","developerIP":"0.0.0.0","developerMode":"0",` +
+			`"duid":"uuid:00000000-0000-4000-8000-000000002018","firmwareVersion":"Unknown",` +
+			`"id":"uuid:00000000-0000-4000-8000-000000002018","ip":"192.0.2.18","model":"18_KANTSU2E",` +
+			`"modelName":"UE43NU7100","name":"[TV] Kitchen","networkType":"wireless","resolution":"3840x2160",` +
+			`"smartHubAgreement":"true","ssid":"00:00:5e:00:53:18","type":"Samsung SmartTV",` +
+			`"udn":"uuid:00000000-0000-4000-8000-000000002018","wifiMac":"02:00:5e:00:20:18"},` +
+			`"id":"uuid:00000000-0000-4000-8000-000000002018","isSupport":"{\"DMP_DRM_PLAYREADY\":\"false\",` +
+			`\"DMP_DRM_WIDEVINE\":\"false\",\"DMP_available\":\"true\",\"EDEN_available\":\"true\",` +
+			`\"FrameTVSupport\":\"false\",\"ImeSyncedSupport\":\"true\",\"TokenAuthSupport\":\"true\",` +
+			`\"remote\":\"true\",\"remote_available\":\"true\",\"remote_fourDirections\":\"true\",` +
+			`\"remote_touchPad\":\"true\",\"remote_voiceControl\":\"true\"}\n","name":"[TV] Kitchen",` +
+			`"remote":"1.0","type":"Samsung SmartTV","uri":"http://192.0.2.18:8001/api/v2/","version":"2.0.25"}`,
+	},
+	{
+		Name:      "2018-n-connect",
+		ModelYear: 2018,
+		Series:    "N",
+		Kind:      FixtureConnect,
+		Body: `{"data":{"clients":[{"attributes":{"name":"R29UaXplblRW","token":null},"connectTime":1530000000000,` +
+			`"deviceName":"R29UaXplblRW","id":"0000b18f-0000-4000-8000-000000002018","isHost":false}],` +
+			`"id":"0000b18f-0000-4000-8000-000000002018","token":"12345678"},"event":"ms.channel.connect"}`,
+	},
+	{
+		Name:      "2018-n-open-app",
+		ModelYear: 2018,
+		Series:    "N",
+		Kind:      FixtureOpenApp,
+		Body:      `true`,
+	},
+	// 2019, R series: reports the power state.
+	{
+		Name:      "2019-r-info",
+		ModelYear: 2019,
+		Series:    "R",
+		Firmware:  "T-MSLDEUC-1304.1",
+		Kind:      FixtureInfo,
+		Body: `{"device":{"FrameTVSupport":"false","GamePadSupport":"true","ImeSyncedSupport":"true",` +
+			`"Language":"en_GB","OS":"Tizen","PowerState":"on","TokenAuthSupport":"true","VoiceSupport":"true",` +
+			`"WallScreenRatio":"0","WallService":"false","countryCode":"GB","description":"Samsung DTV RCR",` +
+			`"developerIP":"0.0.0.0","developerMode":"0","duid":"uuid:00000000-0000-4000-8000-000000002019",` +
+			`"firmwareVersion":"Unknown","id":"uuid:00000000-0000-4000-8000-000000002019","ip":"192.0.2.19",` +
+			`"model":"19_MUSEL_UHD","modelName":"QE55Q60RATXXU","name":"[TV] Living Room","networkType":"wireless",` +
+			`"resolution":"3840x2160","smartHubAgreement":"true","ssid":"00:00:5e:00:53:19","type":"Samsung SmartTV",` +
+			`"udn":"uuid:00000000-0000-4000-8000-000000002019","wifiMac":"02:00:5e:00:20:19"},` +
+			`"id":"uuid:00000000-0000-4000-8000-000000002019","isSupport":"{\"DMP_DRM_PLAYREADY\":\"false\",` +
+			`\"DMP_DRM_WIDEVINE\":\"false\",\"DMP_available\":\"true\",\"EDEN_available\":\"true\",` +
+			`\"FrameTVSupport\":\"false\",\"ImeSyncedSupport\":\"true\",\"TokenAuthSupport\":\"true\",` +
+			`\"remote\":\"true\",\"remote_available\":\"true\",\"remote_fourDirections\":\"true\",` +
+			`\"remote_touchPad\":\"true\",\"remote_voiceControl\":\"true\"}\n","name":"[TV] Living Room",` +
+			`"remote":"1.0","type":"Samsung SmartTV","uri":"http://192.0.2.19:8001/api/v2/","version":"2.0.25"}`,
+	},
+	{
+		Name:      "2019-r-open-app",
+		ModelYear: 2019,
+		Series:    "R",
+		Kind:      FixtureOpenApp,
+		Body:      `{"ok":true}`,
+	},
+	// 2020, T series: clients without attributes, standby power state.
+	{
+		Name:      "2020-t-info",
+		ModelYear: 2020,
+		Series:    "T",
+		Firmware:  "T-MSMDEUC-2010.3",
+		Kind:      FixtureInfo,
+		Body: `{"device":{"FrameTVSupport":"false","GamePadSupport":"true","ImeSyncedSupport":"true",` +
+			`"Language":"en_US","OS":"Tizen","PowerState":"standby","TokenAuthSupport":"true","VoiceSupport":"true",` +
+			`"WallScreenRatio":"-1","WallService":"false","countryCode":"US","description":"Samsung DTV RCR",` +
+			`"developerIP":"0.0.0.0","developerMode":"0","duid":"uuid:00000000-0000-4000-8000-000000002020",` +
+			`"firmwareVersion":"Unknown","id":"uuid:00000000-0000-4000-8000-000000002020","ip":"192.0.2.20",` +
+			`"model":"20_MUSEM_UHD","modelName":"QN55Q80TAFXZA","name":"[TV] Den","networkType":"wired",` +
+			`"resolution":"3840x2160","smartHubAgreement":"true","type":"Samsung SmartTV",` +
+			`"udn":"uuid:00000000-0000-4000-8000-000000002020","wifiMac":"02:00:5e:00:20:20"},` +
+			`"id":"uuid:00000000-0000-4000-8000-000000002020","isSupport":"{\"DMP_DRM_PLAYREADY\":\"false\",` +
+			`\"DMP_DRM_WIDEVINE\":\"false\",\"DMP_available\":\"true\",\"EDEN_available\":\"true\",` +
+			`\"FrameTVSupport\":\"false\",\"ImeSyncedSupport\":\"true\",\"TokenAuthSupport\":\"true\",` +
+			`\"remote\":\"true\",\"remote_available\":\"true\",\"remote_fourDirections\":\"true\",` +
+			`\"remote_touchPad\":\"true\",\"remote_voiceControl\":\"true\"}\n","name":"[TV] Den",` +
+			`"remote":"1.0","type":"Samsung SmartTV","uri":"http://192.0.2.20:8001/api/v2/","version":"2.0.25"}`,
+	},
+	{
+		Name:      "2020-t-connect",
+		ModelYear: 2020,
+		Series:    "T",
+		Kind:      FixtureConnect,
+		Body: `{"data":{"clients":[{"attributes":null,"connectTime":1600000000000,"deviceName":"Smart Device",` +
+			`"id":"0000c20f-0000-4000-8000-000000002020","isHost":false}],` +
+			`"id":"0000c20f-0000-4000-8000-000000002020","token":"87654321"},"event":"ms.channel.connect"}`,
+	},
+	{
+		Name:      "2020-t-installed-apps",
+		ModelYear: 2020,
+		Series:    "T",
+		Kind:      FixtureInstalledApps,
+		Body: `{"data":{"data":[{"appId":"3201907018807","app_type":2,` +
+			`"icon":"/opt/share/webappservice/apps_icon/FirstScreen/3201907018807/250x250.png","name":"Netflix"},` +
+			`{"appId":"111299001912","app_type":2,"icon":"","name":"YouTube"}]},` +
+			`"event":"ed.installedApp.get","from":"host"}`,
+	},
+	// 2021, A series: Frame TVs announce art mode support.
+	{
+		Name:      "2021-a-frame-info",
+		ModelYear: 2021,
+		Series:    "LS03A",
+		Firmware:  "T-NKMDEUC-1520.1",
+		Kind:      FixtureInfo,
+		Body: `{"device":{"FrameTVSupport":"true","GamePadSupport":"true","ImeSyncedSupport":"true",` +
+			`"Language":"en_GB","OS":"Tizen","PowerState":"on","TokenAuthSupport":"true","VoiceSupport":"true",` +
+			`"WallScreenRatio":"0","WallService":"false","countryCode":"GB","description":"Samsung DTV RCR",` +
+			`"developerIP":"0.0.0.0","developerMode":"0","duid":"uuid:00000000-0000-4000-8000-000000002021",` +
+			`"firmwareVersion":"Unknown","id":"uuid:00000000-0000-4000-8000-000000002021","ip":"192.0.2.21",` +
+			`"model":"21_NIKEM_UHD","modelName":"QE50LS03AAUXXU","name":"[TV] Frame","networkType":"wireless",` +
+			`"resolution":"3840x2160","smartHubAgreement":"true","ssid":"00:00:5e:00:53:21","type":"Samsung SmartTV",` +
+			`"udn":"uuid:00000000-0000-4000-8000-000000002021","wifiMac":"02:00:5e:00:20:21"},` +
+			`"id":"uuid:00000000-0000-4000-8000-000000002021","isSupport":"{\"DMP_DRM_PLAYREADY\":\"false\",` +
+			`\"DMP_DRM_WIDEVINE\":\"false\",\"DMP_available\":\"true\",\"EDEN_available\":\"true\",` +
+			`\"FrameTVSupport\":\"true\",\"ImeSyncedSupport\":\"true\",\"TokenAuthSupport\":\"true\",` +
+			`\"remote\":\"true\",\"remote_available\":\"true\",\"remote_fourDirections\":\"true\",` +
+			`\"remote_touchPad\":\"true\",\"remote_voiceControl\":\"true\"}\n","name":"[TV] Frame",` +
+			`"remote":"1.0","type":"Samsung SmartTV","uri":"http://192.0.2.21:8001/api/v2/","version":"2.0.25"}`,
+	},
+	// 2022, B series: isSupport sent as an object.
+	{
+		Name:      "2022-b-info",
+		ModelYear: 2022,
+		Series:    "B",
+		Firmware:  "T-PTMDEUC-1401.2",
+		Kind:      FixtureInfo,
+		Body: `{"device":{"FrameTVSupport":"false","GamePadSupport":"true","ImeSyncedSupport":"true",` +
+			`"Language":"fr_FR","OS":"Tizen","PowerState":"on","TokenAuthSupport":"true","VoiceSupport":"true",` +
+			`"WallScreenRatio":"0","WallService":"false","countryCode":"FR","description":"Samsung DTV RCR",` +
+			`"developerIP":"0.0.0.0","developerMode":"0","duid":"uuid:00000000-0000-4000-8000-000000002022",` +
+			`"firmwareVersion":"Unknown","id":"uuid:00000000-0000-4000-8000-000000002022","ip":"192.0.2.22",` +
+			`"model":"22_PONTUSM_UHD","modelName":"QE55QN85BATXXC","name":"[TV] Salon","networkType":"wireless",` +
+			`"resolution":"3840x2160","smartHubAgreement":"true","ssid":"00:00:5e:00:53:22","type":"Samsung SmartTV",` +
+			`"udn":"uuid:00000000-0000-4000-8000-000000002022","wifiMac":"02:00:5e:00:20:22"},` +
+			`"id":"uuid:00000000-0000-4000-8000-000000002022","isSupport":{"DMP_DRM_PLAYREADY":"false",` +
+			`"DMP_DRM_WIDEVINE":"false","DMP_available":"true","EDEN_available":"true","FrameTVSupport":"false",` +
+			`"ImeSyncedSupport":"true","TokenAuthSupport":"true","remote":"true","remote_available":"true",` +
+			`"remote_fourDirections":"true","remote_touchPad":"true","remote_voiceControl":"true"},` +
+			`"name":"[TV] Salon","remote":"1.0","type":"Samsung SmartTV",` +
+			`"uri":"http://192.0.2.22:8001/api/v2/","version":"2.0.25"}`,
+	},
+	{
+		Name:      "2022-b-app",
+		ModelYear: 2022,
+		Series:    "B",
+		Kind:      FixtureApp,
+		Body:      `{"id":"3201907018807","name":"Netflix","running":true,"version":"5.3.10","visible":true}`,
+	},
+	// 2023, C series: booleans and numbers as strings.
+	{
+		Name:      "2023-c-info",
+		ModelYear: 2023,
+		Series:    "C",
+		Firmware:  "T-PTM2DEUC-1103.6",
+		Kind:      FixtureInfo,
+		Body: `{"device":{"FrameTVSupport":false,"GamePadSupport":true,"ImeSyncedSupport":true,` +
+			`"Language":"de_DE","OS":"Tizen","PowerState":"on","TokenAuthSupport":true,"VoiceSupport":true,` +
+			`"WallScreenRatio":0,"WallService":false,"countryCode":"DE","description":"Samsung DTV RCR",` +
+			`"developerIP":"0.0.0.0","developerMode":0,"duid":"uuid:00000000-0000-4000-8000-000000002023",` +
+			`"firmwareVersion":"Unknown","id":"uuid:00000000-0000-4000-8000-000000002023","ip":"192.0.2.23",` +
+			`"model":"23_PONTUSM2_UHD","modelName":"GQ65QN90CATXZG","name":"[TV] Wohnzimmer",` +
+			`"networkType":"wireless","resolution":"3840x2160","smartHubAgreement":true,` +
+			`"ssid":"00:00:5e:00:53:23","type":"Samsung SmartTV","udn":"uuid:00000000-0000-4000-8000-000000002023",` +
+			`"wifiMac":"02:00:5e:00:20:23"},"id":"uuid:00000000-0000-4000-8000-000000002023",` +
+			`"isSupport":"{\"DMP_DRM_PLAYREADY\":false,\"DMP_DRM_WIDEVINE\":false,\"DMP_available\":true,` +
+			`\"EDEN_available\":true,\"FrameTVSupport\":false,\"ImeSyncedSupport\":true,\"TokenAuthSupport\":true,` +
+			`\"remote\":true,\"remote_available\":true,\"remote_fourDirections\":true,\"remote_touchPad\":true,` +
+			`\"remote_voiceControl\":true}","name":"[TV] Wohnzimmer","remote":"1.0","type":"Samsung SmartTV",` +
+			`"uri":"http://192.0.2.23:8001/api/v2/","version":"2.0.25"}`,
+	},
+	{
+		Name:      "2023-c-app",
+		ModelYear: 2023,
+		Series:    "C",
+		Kind:      FixtureApp,
+		Body:      `{"id":"111299001912","name":"YouTube","running":"true","version":"3.1.2","visible":"false"}`,
+	},
+	{
+		Name:      "2023-c-connect",
+		ModelYear: 2023,
+		Series:    "C",
+		Kind:      FixtureConnect,
+		Body: `{"data":{"clients":[{"attributes":{"name":"R29UaXplblRW"},"connectTime":"1690000000000",` +
+			`"deviceName":"R29UaXplblRW","id":"0000d23f-0000-4000-8000-000000002023","isHost":"false"}],` +
+			`"id":"0000d23f-0000-4000-8000-000000002023","token":11223344},"event":"ms.channel.connect"}`,
+	},
+	{
+		Name:      "2023-c-installed-apps",
+		ModelYear: 2023,
+		Series:    "C",
+		Kind:      FixtureInstalledApps,
+		Body: `{"data":{"data":[{"appId":"3201907018807","appType":"2","icon":null,"name":"Netflix"},` +
+			`{"appId":"3201512006785","appType":"2","icon":null,"name":"Prime Video"}]},` +
+			`"event":"ed.installedApp.get","from":"host"}`,
+	},
+	// 2024, D series: empty open app replies.
+	{
+		Name:      "2024-d-info",
+		ModelYear: 2024,
+		Series:    "D",
+		Firmware:  "T-PTM3DEUC-1050.0",
+		Kind:      FixtureInfo,
+		Body: `{"device":{"FrameTVSupport":"false","GamePadSupport":"true","ImeSyncedSupport":"true",` +
+			`"Language":"en_GB","OS":"Tizen","PowerState":"on","TokenAuthSupport":"true","VoiceSupport":"true",` +
+			`"WallScreenRatio":"0","WallService":"false","countryCode":"GB","description":"Samsung DTV RCR",` +
+			`"developerIP":"0.0.0.0","developerMode":"0","duid":"uuid:00000000-0000-4000-8000-000000002024",` +
+			`"firmwareVersion":"Unknown","id":"uuid:00000000-0000-4000-8000-000000002024","ip":"192.0.2.24",` +
+			`"model":"24_PONTUSM3_UHD","modelName":"QE65QN95DATXXU","name":"[TV] Lounge","networkType":"wired",` +
+			`"resolution":"3840x2160","smartHubAgreement":"true","type":"Samsung SmartTV",` +
+			`"udn":"uuid:00000000-0000-4000-8000-000000002024","wifiMac":"02:00:5e:00:20:24"},` +
+			`"id":"uuid:00000000-0000-4000-8000-000000002024","isSupport":"{\"DMP_DRM_PLAYREADY\":\"false\",` +
+			`\"DMP_DRM_WIDEVINE\":\"false\",\"DMP_available\":\"true\",\"EDEN_available\":\"true\",` +
+			`\"FrameTVSupport\":\"false\",\"ImeSyncedSupport\":\"true\",\"TokenAuthSupport\":\"true\",` +
+			`\"remote\":\"true\",\"remote_available\":\"true\",\"remote_fourDirections\":\"true\",` +
+			`\"remote_touchPad\":\"true\",\"remote_voiceControl\":\"true\"}","name":"[TV] Lounge",` +
+			`"remote":"1.0","type":"Samsung SmartTV","uri":"http://192.0.2.24:8001/api/v2/","version":"2.0.25"}`,
+	},
+	{
+		Name:      "2024-d-open-app",
+		ModelYear: 2024,
+		Series:    "D",
+		Kind:      FixtureOpenApp,
+		Body:      ``,
+	},
+}
@@ -0,0 +1,357 @@
+package tizentest
+
+import (
+	"crypto/aes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	defaultPairingServerHost = "127.0.0.1"
+	defaultPairingPIN        = "1234"
+	defaultPairingSessionID  = "1"
+	pairingNamespace         = "/com.samsung.companion"
+	socketIOSessionToken     = "tizentest"
+)
+
+// PairingCrypto is a stand-in for the SmartCrypto handshake. It is not secure,
+// it only lets the client and PairingServer agree on a session key derived
+// from the PIN, and fail when the PINs differ.
+type PairingCrypto struct{}
+
+func (PairingCrypto) GenerateServerHello(userID string, pin string) ([]byte, []byte, []byte, error) {
+	hash := pairingHash(pin)
+
+	return []byte(userID + ":" + pin), hash, hash[:16], nil
+}
+
+func (PairingCrypto) ParseClientHello(clientHello string, hash []byte, aesKey []byte, userID string) ([]byte, []byte, error) {
+	if !strings.EqualFold(clientHello, hex.EncodeToString(hash)) {
+		return nil, nil, errors.New("client hello does not match the PIN")
+	}
+
+	return hash, aesKey, nil
+}
+
+func (PairingCrypto) GenerateServerAcknowledge(skPrime []byte) (string, error) {
+	return pairingAcknowledge("0103", skPrime), nil
+}
+
+func (PairingCrypto) ParseClientAcknowledge(clientAck string, skPrime []byte) error {
+	if clientAck != pairingAcknowledge("0104", skPrime) {
+		return errors.New("client acknowledge does not match")
+	}
+
+	return nil
+}
+
+func pairingHash(pin string) []byte {
+	hash := sha256.Sum256([]byte(pin))
+
+	return hash[:]
+}
+
+func pairingAcknowledge(prefix string, skPrime []byte) string {
+	return prefix + strings.ToUpper(hex.EncodeToString(skPrime[:8]))
+}
+
+// PairingServer is a fake of a 2014-2015 (H/J series) TV. It serves the PIN
+// pairing on its port, expecting the handshake of PairingCrypto, and receives
+// encrypted keys over socket.io on the same port.
+type PairingServer struct {
+	PIN       string
+	SessionID string
+
+	mu          sync.Mutex
+	listener    net.Listener
+	server      *http.Server
+	upgrader    websocket.Upgrader
+	connections map[*websocket.Conn]bool
+	pinShown    bool
+	keys        []ReceivedKey
+}
+
+func NewPairingServer() *PairingServer {
+	return &PairingServer{
+		PIN:         defaultPairingPIN,
+		SessionID:   defaultPairingSessionID,
+		connections: map[*websocket.Conn]bool{},
+	}
+}
+
+func (s *PairingServer) Start() error {
+	listener, err := net.Listen("tcp", net.JoinHostPort(defaultPairingServerHost, "0"))
+	if err != nil {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ws/apps/CloudPINPage", s.handlePINPage)
+	mux.HandleFunc("/ws/apps/CloudPINPage/run", s.handlePINPage)
+	mux.HandleFunc("/ws/pairing", s.handlePairing)
+	mux.HandleFunc("/socket.io/1/", s.handleSocketIO)
+
+	server := &http.Server{Handler: mux}
+	s.listener = listener
+	s.server = server
+
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	return nil
+}
+
+func (s *PairingServer) Host() string {
+	return defaultPairingServerHost
+}
+
+func (s *PairingServer) Port() string {
+	_, port, _ := net.SplitHostPort(s.listener.Addr().String())
+
+	return port
+}
+
+func (s *PairingServer) Close() error {
+	s.DropConnections()
+
+	return s.server.Close()
+}
+
+// PINShown reports whether the PIN is on the screen.
+func (s *PairingServer) PINShown() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.pinShown
+}
+
+// Keys returns the keys received, decrypted, in order.
+func (s *PairingServer) Keys() []ReceivedKey {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]ReceivedKey(nil), s.keys...)
+}
+
+func (s *PairingServer) Connections() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.connections)
+}
+
+// DropConnections closes the socket.io connections like a TV dropping idle
+// clients.
+func (s *PairingServer) DropConnections() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for connection := range s.connections {
+		_ = connection.Close()
+		delete(s.connections, connection)
+	}
+}
+
+func (s *PairingServer) handlePINPage(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch r.Method {
+	case http.MethodGet:
+		state := "stopped"
+		if s.pinShown {
+			state = "running"
+		}
+
+		_, _ = fmt.Fprintf(w, "<service><name>CloudPINPage</name><state>%s</state></service>", state)
+	case http.MethodPost:
+		s.pinShown = true
+
+		w.WriteHeader(http.StatusCreated)
+	case http.MethodDelete:
+		s.pinShown = false
+	default:
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *PairingServer) handlePairing(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		AuthData struct {
+			ServerHello string `json:"GeneratorServerHello"`
+			ServerAck   string `json:"ServerAckMsg"`
+		} `json:"auth_Data"`
+	}
+
+	if r.Method == http.MethodPost {
+		err := json.NewDecoder(r.Body).Decode(&request)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+
+			return
+		}
+	}
+
+	// The TV derives its side of the handshake from the PIN it shows.
+	hash := pairingHash(s.PIN)
+
+	switch r.URL.Query().Get("step") {
+	case "0":
+		_, _ = fmt.Fprint(w, `{"auth_data":"","request_id":"0"}`)
+	case "1":
+		_, err := hex.DecodeString(request.AuthData.ServerHello)
+		if err != nil || !s.PINShown() {
+			http.Error(w, "invalid server hello", http.StatusBadRequest)
+
+			return
+		}
+
+		writePairingAuthData(w, map[string]string{
+			"auth_type":            "SPC",
+			"request_id":           "0",
+			"GeneratorClientHello": strings.ToUpper(hex.EncodeToString(hash)),
+		})
+	case "2":
+		if request.AuthData.ServerAck != pairingAcknowledge("0103", hash) {
+			http.Error(w, "invalid server acknowledge", http.StatusBadRequest)
+
+			return
+		}
+
+		writePairingAuthData(w, map[string]string{
+			"auth_type":    "SPC",
+			"request_id":   "0",
+			"ClientAckMsg": pairingAcknowledge("0104", hash),
+			"session_id":   s.SessionID,
+		})
+	default:
+		http.Error(w, "invalid step", http.StatusBadRequest)
+	}
+}
+
+// writePairingAuthData answers like a TV, with the data encoded as a string.
+func writePairingAuthData(w http.ResponseWriter, data map[string]string) {
+	authData, _ := json.Marshal(data)
+	body, _ := json.Marshal(map[string]string{"auth_data": string(authData)})
+
+	_, _ = w.Write(body)
+}
+
+func (s *PairingServer) handleSocketIO(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.URL.Path, "/socket.io/1/websocket/") {
+		_, _ = fmt.Fprintf(w, "%s:60:60:websocket,xhr-polling", socketIOSessionToken)
+
+		return
+	}
+
+	connection, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+
+	s.mu.Lock()
+	s.connections[connection] = true
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.connections, connection)
+		s.mu.Unlock()
+
+		_ = connection.Close()
+	}()
+
+	for {
+		_, message, err := connection.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		prefix := "5::" + pairingNamespace + ":"
+		if strings.HasPrefix(string(message), prefix) {
+			s.handleCommand(strings.TrimPrefix(string(message), prefix))
+		}
+	}
+}
+
+func (s *PairingServer) handleCommand(message string) {
+	var event struct {
+		Args []struct {
+			SessionID json.Number `json:"Session_Id"`
+			Body      string      `json:"body"`
+		} `json:"args"`
+	}
+
+	err := json.Unmarshal([]byte(message), &event)
+	if err != nil || len(event.Args) != 1 || event.Args[0].SessionID.String() != s.SessionID {
+		return
+	}
+
+	// The body is the encrypted command as a list of byte values.
+	var values []int
+
+	err = json.Unmarshal([]byte(event.Args[0].Body), &values)
+	if err != nil {
+		return
+	}
+
+	encrypted := make([]byte, 0, len(values))
+	for _, value := range values {
+		encrypted = append(encrypted, byte(value))
+	}
+
+	data, err := decryptAESECB(pairingHash(s.PIN)[:16], encrypted)
+	if err != nil {
+		return
+	}
+
+	var command struct {
+		Body struct {
+			Param2 string `json:"param2"`
+			Param3 string `json:"param3"`
+		} `json:"body"`
+	}
+
+	err = json.Unmarshal(data, &command)
+	if err != nil {
+		return
+	}
+
+	s.mu.Lock()
+	s.keys = append(s.keys, ReceivedKey{Key: command.Body.Param3, Cmd: command.Body.Param2})
+	s.mu.Unlock()
+}
+
+func decryptAESECB(key []byte, data []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) == 0 || len(data)%block.BlockSize() != 0 {
+		return nil, errors.New("invalid encrypted data length: " + strconv.Itoa(len(data)))
+	}
+
+	decrypted := make([]byte, len(data))
+	for i := 0; i < len(data); i += block.BlockSize() {
+		block.Decrypt(decrypted[i:i+block.BlockSize()], data[i:i+block.BlockSize()])
+	}
+
+	padding := int(decrypted[len(decrypted)-1])
+	if padding == 0 || padding > block.BlockSize() {
+		return nil, errors.New("invalid padding")
+	}
+
+	return decrypted[:len(decrypted)-padding], nil
+}
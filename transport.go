@@ -1,9 +1,14 @@
 package samsung
 
 import (
+	"context"
+	"io"
 	"time"
 
+	"github.com/kpeu3i/go-tizen-tv/dial"
+	"github.com/kpeu3i/go-tizen-tv/mediaserver"
 	"github.com/kpeu3i/go-tizen-tv/tizenapi"
+	"github.com/kpeu3i/go-tizen-tv/upnp"
 )
 
 type UDPAPIClient interface {
@@ -11,6 +16,7 @@ type UDPAPIClient interface {
 	Subnet() string
 	Port() string
 	WakeUp() error
+	WakeUpContext(ctx context.Context) error
 }
 
 type HTTPAPIClient interface {
@@ -20,11 +26,22 @@ type HTTPAPIClient interface {
 	RequestTimeout() time.Duration
 	ResponseTimeout() time.Duration
 	IsAvailable() bool
+	IsAvailableContext(ctx context.Context) bool
 	GetInfo() (tizenapi.GetInfoResponse, error)
+	GetInfoContext(ctx context.Context) (tizenapi.GetInfoResponse, error)
 	GetApp(id string) (tizenapi.GetAppResponse, error)
+	GetAppContext(ctx context.Context, id string) (tizenapi.GetAppResponse, error)
 	OpenApp(id string) error
+	OpenAppContext(ctx context.Context, id string) error
 	InstallApp(id string) error
+	InstallAppContext(ctx context.Context, id string) error
 	CloseApp(id string) error
+	CloseAppContext(ctx context.Context, id string) error
+}
+
+type PairingAPIClient interface {
+	IsAvailableContext(ctx context.Context) bool
+	Pair(ctx context.Context, pinHandler tizenapi.PINHandler) (tizenapi.PairingSession, error)
 }
 
 type WebsocketAPIClient interface {
@@ -36,10 +53,142 @@ type WebsocketAPIClient interface {
 	WriteTimeout() time.Duration
 	ClientID() string
 	IsAvailable() bool
+	IsAvailableContext(ctx context.Context) bool
 	IsConnected() bool
+	IsReconnecting() bool
 	Connect(token string) (tizenapi.ConnectResponseMessage, error)
+	ConnectContext(ctx context.Context, token string) (tizenapi.ConnectResponseMessage, error)
 	GetApps() (tizenapi.GetAppsResponseMessage, error)
+	GetAppsContext(ctx context.Context) (tizenapi.GetAppsResponseMessage, error)
 	OpenApp(id string, actionType tizenapi.WebsocketOpenAppActionType, metaTag string) error
+	OpenAppContext(
+		ctx context.Context,
+		id string,
+		actionType tizenapi.WebsocketOpenAppActionType,
+		metaTag string,
+	) error
 	SendKey(key string, state tizenapi.WebsocketKeyState) error
+	SendKeyContext(ctx context.Context, key string, state tizenapi.WebsocketKeyState) error
+	SendMouseMove(dx, dy int) error
+	SendMouseMoveContext(ctx context.Context, dx, dy int) error
+	SendMouseClick(button tizenapi.WebsocketMouseButton) error
+	SendMouseClickContext(ctx context.Context, button tizenapi.WebsocketMouseButton) error
+	SendInputString(text string) error
+	SendInputStringContext(ctx context.Context, text string) error
+	SendInputEnd() error
+	SendInputEndContext(ctx context.Context) error
+	Subscribe(event tizenapi.WebsocketEvent, handler tizenapi.WebsocketEventHandler) func()
+	OnReconnect(handler tizenapi.WebsocketReconnectHandler)
+	Close() error
+	CloseContext(ctx context.Context) error
+}
+
+type ArtAPIClient interface {
+	IsConnected() bool
+	Connect(token string) (tizenapi.ConnectResponseMessage, error)
+	ConnectContext(ctx context.Context, token string) (tizenapi.ConnectResponseMessage, error)
+	Subscribe(handler tizenapi.ArtEventHandler) func()
+	GetArtModeStatus() (bool, error)
+	GetArtModeStatusContext(ctx context.Context) (bool, error)
+	SetArtModeStatus(on bool) error
+	SetArtModeStatusContext(ctx context.Context, on bool) error
+	GetBrightness() (int, error)
+	GetBrightnessContext(ctx context.Context) (int, error)
+	SetBrightness(value int) error
+	SetBrightnessContext(ctx context.Context, value int) error
+	GetColorTemperature() (int, error)
+	GetColorTemperatureContext(ctx context.Context) (int, error)
+	SetColorTemperature(value int) error
+	SetColorTemperatureContext(ctx context.Context, value int) error
+	GetMotionTimer() (string, error)
+	GetMotionTimerContext(ctx context.Context) (string, error)
+	SetMotionTimer(value string) error
+	SetMotionTimerContext(ctx context.Context, value string) error
+	GetMotionSensitivity() (int, error)
+	GetMotionSensitivityContext(ctx context.Context) (int, error)
+	SetMotionSensitivity(value int) error
+	SetMotionSensitivityContext(ctx context.Context, value int) error
+	GetContentList(category string) ([]tizenapi.ArtContent, error)
+	GetContentListContext(ctx context.Context, category string) ([]tizenapi.ArtContent, error)
+	GetCurrentArtwork() (tizenapi.ArtContent, error)
+	GetCurrentArtworkContext(ctx context.Context) (tizenapi.ArtContent, error)
+	UploadImage(image []byte, fileType string, matteID string) (string, error)
+	UploadImageContext(ctx context.Context, image []byte, fileType string, matteID string) (string, error)
+	DeleteImages(contentIDs ...string) error
+	DeleteImagesContext(ctx context.Context, contentIDs ...string) error
+	SelectImage(contentID string, show bool) error
+	SelectImageContext(ctx context.Context, contentID string, show bool) error
+	GetMatteList() (tizenapi.ArtMatteList, error)
+	GetMatteListContext(ctx context.Context) (tizenapi.ArtMatteList, error)
+	ChangeMatte(contentID string, matteID string) error
+	ChangeMatteContext(ctx context.Context, contentID string, matteID string) error
+	ChangeFavorite(contentID string, favorite bool) error
+	ChangeFavoriteContext(ctx context.Context, contentID string, favorite bool) error
+	GetSlideshowStatus() (tizenapi.ArtSlideshowStatus, error)
+	GetSlideshowStatusContext(ctx context.Context) (tizenapi.ArtSlideshowStatus, error)
+	SetSlideshowStatus(value string, categoryID string, shuffle bool) error
+	SetSlideshowStatusContext(ctx context.Context, value string, categoryID string, shuffle bool) error
 	Close() error
+	CloseContext(ctx context.Context) error
+}
+
+type RenderingControlClient interface {
+	Location() string
+	GetVolume() (int, error)
+	GetVolumeContext(ctx context.Context) (int, error)
+	SetVolume(volume int) error
+	SetVolumeContext(ctx context.Context, volume int) error
+	GetMute() (bool, error)
+	GetMuteContext(ctx context.Context) (bool, error)
+	SetMute(mute bool) error
+	SetMuteContext(ctx context.Context, mute bool) error
+}
+
+type AVTransportClient interface {
+	Location() string
+	SetAVTransportURI(uri string, metadata upnp.Metadata) error
+	SetAVTransportURIContext(ctx context.Context, uri string, metadata upnp.Metadata) error
+	Play() error
+	PlayContext(ctx context.Context) error
+	Pause() error
+	PauseContext(ctx context.Context) error
+	Stop() error
+	StopContext(ctx context.Context) error
+	Seek(position time.Duration) error
+	SeekContext(ctx context.Context, position time.Duration) error
+	GetTransportInfo() (upnp.TransportInfo, error)
+	GetTransportInfoContext(ctx context.Context) (upnp.TransportInfo, error)
+	GetPositionInfo() (upnp.PositionInfo, error)
+	GetPositionInfoContext(ctx context.Context) (upnp.PositionInfo, error)
+}
+
+type MediaServer interface {
+	Start() error
+	PublishFile(path string) (mediaserver.Media, error)
+	PublishReader(name string, mimeType string, reader io.ReadSeeker) (mediaserver.Media, error)
+	Unpublish(id string)
+	URL(id string, remoteHost string) (string, error)
+	Close() error
+	CloseContext(ctx context.Context) error
+}
+
+type EventSubscriber interface {
+	Subscribe(
+		ctx context.Context,
+		location string,
+		serviceType string,
+		handler upnp.EventHandler,
+	) (*upnp.Subscription, error)
+	Close() error
+	CloseContext(ctx context.Context) error
+}
+
+type DIALClient interface {
+	ApplicationURL() string
+	GetApp(name string) (dial.App, error)
+	GetAppContext(ctx context.Context, name string) (dial.App, error)
+	LaunchApp(name string, payload string) (string, error)
+	LaunchAppContext(ctx context.Context, name string, payload string) (string, error)
+	StopApp(instanceURL string) error
+	StopAppContext(ctx context.Context, instanceURL string) error
 }
@@ -2,10 +2,13 @@ package samsung
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
+	"strings"
+	"sync"
 	"time"
+	"unicode/utf8"
 
+	"github.com/kpeu3i/go-tizen-tv/clock"
 	"github.com/kpeu3i/go-tizen-tv/tizenapi"
 )
 
@@ -17,6 +20,14 @@ const (
 
 type AuthorizeHandler func(token string) error
 
+type TVReconnect struct {
+	Attempt int
+	Err     error
+	GaveUp  bool
+}
+
+type ReconnectHandler func(reconnect TVReconnect)
+
 type TVOption func(*TV)
 
 func WithPowerOnTimeout(timeout time.Duration) TVOption {
@@ -31,12 +42,71 @@ func WithPowerOffTimeout(timeout time.Duration) TVOption {
 	}
 }
 
+// WithClock sets the clock used for timeouts, polling and key sequence waits.
+// Tests use a fake clock to run them instantly.
+func WithClock(c clock.Clock) TVOption {
+	return func(tv *TV) {
+		tv.clock = c
+	}
+}
+
+// WithKeyPowerKey sets the key sent to power the TV off. By default it is
+// chosen per model, KEY_POWEROFF on pre-Tizen TVs and KEY_POWER otherwise.
 func WithKeyPowerKey(key Key) TVOption {
 	return func(tv *TV) {
 		tv.keyPowerOff = key
 	}
 }
 
+// WithKeyPowerOn sets the key sent to wake a TV which is in standby with its
+// network up. By default it is KEY_POWER.
+func WithKeyPowerOn(key Key) TVOption {
+	return func(tv *TV) {
+		tv.keyPowerOn = key
+	}
+}
+
+func WithArtAPIClient(client ArtAPIClient) TVOption {
+	return func(tv *TV) {
+		tv.artClient = client
+	}
+}
+
+func WithRenderingControlClient(client RenderingControlClient) TVOption {
+	return func(tv *TV) {
+		tv.renderingControlClient = client
+	}
+}
+
+func WithAVTransportClient(client AVTransportClient) TVOption {
+	return func(tv *TV) {
+		tv.avTransportClient = client
+	}
+}
+
+// WithMediaServer sets the server which serves local media cast to the TV.
+// Without it a server listening on a random port is started on first use.
+func WithMediaServer(server MediaServer) TVOption {
+	return func(tv *TV) {
+		tv.mediaServer = server
+	}
+}
+
+// WithEventSubscriber sets the subscriber used for UPnP renderer events.
+// Without it a subscriber with a callback server on a random port is started
+// on first use.
+func WithEventSubscriber(subscriber EventSubscriber) TVOption {
+	return func(tv *TV) {
+		tv.eventSubscriber = subscriber
+	}
+}
+
+func WithDIALClient(client DIALClient) TVOption {
+	return func(tv *TV) {
+		tv.dialClient = client
+	}
+}
+
 type TVApp struct {
 	ID        string
 	Name      string
@@ -57,14 +127,28 @@ type TVInfo struct {
 }
 
 type TV struct {
-	udpClient        UDPAPIClient
-	httpClient       HTTPAPIClient
-	websocketClient  WebsocketAPIClient
-	token            string
-	keyPowerOff      Key
-	powerOnTimeout   time.Duration
-	powerOffTimeout  time.Duration
-	authorizeHandler AuthorizeHandler
+	udpClient              UDPAPIClient
+	httpClient             HTTPAPIClient
+	websocketClient        WebsocketAPIClient
+	artClient              ArtAPIClient
+	renderingControlClient RenderingControlClient
+	avTransportClient      AVTransportClient
+	mediaServer            MediaServer
+	eventSubscriber        EventSubscriber
+	dialClient             DIALClient
+	player                 *Player
+	token                  string
+	keyPowerOff            Key
+	keyPowerOn             Key
+	powerOnTimeout         time.Duration
+	powerOffTimeout        time.Duration
+	authorizeHandler       AuthorizeHandler
+	reconnectHandler       ReconnectHandler
+	wakeStrategy           WakeStrategy
+	wakeInterval           time.Duration
+	powerOnProgressHandler PowerOnProgressHandler
+	clock                  clock.Clock
+	mu                     sync.Mutex
 }
 
 func NewTV(
@@ -79,68 +163,72 @@ func NewTV(
 		httpClient:      httpAPIClient,
 		websocketClient: websocketAPIClient,
 		token:           token,
-		keyPowerOff:     KEY_POWER,
 		powerOnTimeout:  defaultTimeoutPowerOn,
 		powerOffTimeout: defaultTimeoutPowerOff,
+		clock:           clock.New(),
 	}
 
 	for _, option := range options {
 		option(tv)
 	}
 
+	websocketAPIClient.OnReconnect(tv.handleReconnect)
+
 	return tv
 }
 
 func (tv *TV) PowerOn() error {
-	ctx, cancel := context.WithTimeout(context.Background(), tv.powerOnTimeout)
-	defer cancel()
-
-	ready := make(chan struct{}, 1)
-	go func() {
-		defer func() {
-			ready <- struct{}{}
-		}()
-
-		for {
-			if tv.IsReady() {
-				return
-			}
-
-			err := tv.udpClient.WakeUp()
-			if err != nil {
-				continue
-			}
+	return tv.PowerOnContext(context.Background())
+}
 
-			if ctx.Err() != nil {
-				return
-			}
+func (tv *TV) PowerOnContext(ctx context.Context) error {
+	timeoutCtx, cancel := clock.WithTimeout(ctx, tv.clock, tv.powerOnTimeout)
+	defer cancel()
 
-			time.Sleep(3 * time.Second)
+	err := tv.wake(timeoutCtx)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
 		}
-	}()
 
-	select {
-	case <-ready:
-		err := tv.establishWebsocketConnection()
-		if err != nil {
+		// The last attempt may have woken the TV while waiting for the deadline.
+		state, stateErr := tv.PowerStateContext(ctx)
+		if stateErr != nil || state != PowerStateOn {
 			return err
 		}
-	case <-ctx.Done():
-		return errors.New("cannot find TV in the network")
+
+		return tv.ensureWebsocketConnection(ctx)
 	}
 
-	return nil
+	// A wake strategy may have connected already to send a key.
+	return tv.ensureWebsocketConnection(timeoutCtx)
 }
 
+// PowerOff powers the TV off and returns once it no longer reports being on.
+// Nothing is sent when the TV is not on.
+//
+// TVs which do not report a power state, like many 2016-2018 models, are taken
+// for on while their remote control API accepts connections. Some keep it open
+// in standby, where the KEY_POWER toggle sent by default turns them on. Use
+// WithKeyPowerKey with KEY_POWEROFF for those which support it.
 func (tv *TV) PowerOff() error {
-	ctx, cancel := context.WithTimeout(context.Background(), tv.powerOffTimeout)
+	return tv.PowerOffContext(context.Background())
+}
+
+func (tv *TV) PowerOffContext(ctx context.Context) error {
+	timeoutCtx, cancel := clock.WithTimeout(ctx, tv.clock, tv.powerOffTimeout)
 	defer cancel()
 
-	if !tv.isAvailable() {
+	state, err := tv.PowerStateContext(timeoutCtx)
+	if err != nil {
+		return tv.powerOffError(ctx)
+	}
+
+	if state != PowerStateOn {
 		return nil
 	}
 
-	err := tv.ClickKey(tv.keyPowerOff)
+	err = tv.ClickKeyContext(timeoutCtx, tv.powerOffKey())
 	if err != nil {
 		return err
 	}
@@ -152,47 +240,85 @@ func (tv *TV) PowerOff() error {
 		}()
 
 		for {
-			if !tv.isAvailable() {
+			state, err := tv.PowerStateContext(timeoutCtx)
+			if err == nil && state != PowerStateOn {
 				break
 			}
 
-			time.Sleep(1 * time.Second)
+			err = tv.sleep(timeoutCtx, 1*time.Second)
+			if err != nil {
+				return
+			}
 		}
 	}()
 
 	select {
 	case <-ready:
+		if timeoutCtx.Err() != nil {
+			return tv.powerOffError(ctx)
+		}
+
 		return nil
-	case <-ctx.Done():
-		return errors.New("unable to power off a TV")
+	case <-timeoutCtx.Done():
+		return tv.powerOffError(ctx)
 	}
 }
 
 func (tv *TV) OnAuthorize(handler AuthorizeHandler) {
+	tv.mu.Lock()
+	defer tv.mu.Unlock()
+
 	tv.authorizeHandler = handler
 }
 
+func (tv *TV) OnReconnect(handler ReconnectHandler) {
+	tv.mu.Lock()
+	defer tv.mu.Unlock()
+
+	tv.reconnectHandler = handler
+}
+
 func (tv *TV) IsReady() bool {
-	if !tv.isAvailable() {
+	return tv.IsReadyContext(context.Background())
+}
+
+// IsReadyContext reports whether the TV is on and accepts connections. A TV in
+// standby with its network up is not ready.
+func (tv *TV) IsReadyContext(ctx context.Context) bool {
+	if !tv.isAvailable(ctx) {
 		return false
 	}
 
-	_, err := tv.httpClient.GetInfo()
+	state, err := tv.PowerStateContext(ctx)
 	if err != nil {
 		return false
 	}
 
-	return true
+	return state == PowerStateOn
+}
+
+func (tv *TV) Connect() error {
+	return tv.ConnectContext(context.Background())
+}
+
+// ConnectContext opens the remote control connection unless it is open. A TV
+// which does not know the client asks on its screen whether to allow it, the
+// token it issues is passed to the authorize handler.
+func (tv *TV) ConnectContext(ctx context.Context) error {
+	return tv.ensureWebsocketConnection(ctx)
 }
 
 func (tv *TV) Info() (TVInfo, error) {
-	response, err := tv.httpClient.GetInfo()
+	return tv.InfoContext(context.Background())
+}
+
+func (tv *TV) InfoContext(ctx context.Context) (TVInfo, error) {
+	response, err := tv.httpClient.GetInfoContext(ctx)
 	if err != nil {
 		return TVInfo{}, err
 	}
 
-	var isSupport map[string]string
-	err = json.Unmarshal([]byte(response.IsSupport), &isSupport)
+	isSupport, err := tizenapi.ParseIsSupport(response.IsSupport)
 	if err != nil {
 		return TVInfo{}, err
 	}
@@ -212,20 +338,28 @@ func (tv *TV) Info() (TVInfo, error) {
 }
 
 func (tv *TV) Apps() ([]TVApp, error) {
-	err := tv.ensureWebsocketConnection()
+	return tv.AppsContext(context.Background())
+}
+
+func (tv *TV) AppsContext(ctx context.Context) ([]TVApp, error) {
+	err := tv.ensureWebsocketConnection(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	appsResponse, err := tv.websocketClient.GetApps()
+	appsResponse, err := tv.websocketClient.GetAppsContext(ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	apps := make([]TVApp, 0, len(appsResponse.Data.Data))
 	for _, item := range appsResponse.Data.Data {
-		appResponse, err := tv.httpClient.GetApp(item.AppId)
+		appResponse, err := tv.httpClient.GetAppContext(ctx, item.AppId)
 		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+
 			continue
 		}
 
@@ -244,7 +378,11 @@ func (tv *TV) Apps() ([]TVApp, error) {
 }
 
 func (tv *TV) App(id string) (TVApp, error) {
-	response, err := tv.httpClient.GetApp(id)
+	return tv.AppContext(context.Background(), id)
+}
+
+func (tv *TV) AppContext(ctx context.Context, id string) (TVApp, error) {
+	response, err := tv.httpClient.GetAppContext(ctx, id)
 	if err != nil {
 		return TVApp{}, err
 	}
@@ -261,24 +399,41 @@ func (tv *TV) App(id string) (TVApp, error) {
 }
 
 func (tv *TV) OpenApp(id string) error {
-	return tv.httpClient.OpenApp(id)
+	return tv.OpenAppContext(context.Background(), id)
+}
+
+func (tv *TV) OpenAppContext(ctx context.Context, id string) error {
+	return tv.httpClient.OpenAppContext(ctx, id)
 }
 
 func (tv *TV) InstallApp(id string) error {
-	return tv.httpClient.InstallApp(id)
+	return tv.InstallAppContext(context.Background(), id)
+}
+
+func (tv *TV) InstallAppContext(ctx context.Context, id string) error {
+	return tv.httpClient.InstallAppContext(ctx, id)
 }
 
 func (tv *TV) CloseApp(id string) error {
-	return tv.httpClient.CloseApp(id)
+	return tv.CloseAppContext(context.Background(), id)
+}
+
+func (tv *TV) CloseAppContext(ctx context.Context, id string) error {
+	return tv.httpClient.CloseAppContext(ctx, id)
 }
 
 func (tv *TV) OpenBrowser(url string) error {
-	err := tv.ensureWebsocketConnection()
+	return tv.OpenBrowserContext(context.Background(), url)
+}
+
+func (tv *TV) OpenBrowserContext(ctx context.Context, url string) error {
+	err := tv.ensureWebsocketConnection(ctx)
 	if err != nil {
 		return err
 	}
 
-	err = tv.websocketClient.OpenApp(
+	err = tv.websocketClient.OpenAppContext(
+		ctx,
 		defaultAppBrowser,
 		tizenapi.WebsocketOpenAppActionTypeNativeLaunch,
 		url,
@@ -291,34 +446,50 @@ func (tv *TV) OpenBrowser(url string) error {
 }
 
 func (tv *TV) ClickKey(key Key) error {
-	err := tv.ensureWebsocketConnection()
+	return tv.ClickKeyContext(context.Background(), key)
+}
+
+func (tv *TV) ClickKeyContext(ctx context.Context, key Key) error {
+	err := tv.ensureWebsocketConnection(ctx)
 	if err != nil {
 		return err
 	}
 
-	return tv.websocketClient.SendKey(string(key), tizenapi.WebsocketKeyStateClick)
+	return tv.websocketClient.SendKeyContext(ctx, string(key), tizenapi.WebsocketKeyStateClick)
 }
 
 func (tv *TV) PressKey(key Key) error {
-	err := tv.ensureWebsocketConnection()
+	return tv.PressKeyContext(context.Background(), key)
+}
+
+func (tv *TV) PressKeyContext(ctx context.Context, key Key) error {
+	err := tv.ensureWebsocketConnection(ctx)
 	if err != nil {
 		return err
 	}
 
-	return tv.websocketClient.SendKey(string(key), tizenapi.WebsocketKeyStatePress)
+	return tv.websocketClient.SendKeyContext(ctx, string(key), tizenapi.WebsocketKeyStatePress)
 }
 
 func (tv *TV) ReleaseKey(key Key) error {
-	err := tv.ensureWebsocketConnection()
+	return tv.ReleaseKeyContext(context.Background(), key)
+}
+
+func (tv *TV) ReleaseKeyContext(ctx context.Context, key Key) error {
+	err := tv.ensureWebsocketConnection(ctx)
 	if err != nil {
 		return err
 	}
 
-	return tv.websocketClient.SendKey(string(key), tizenapi.WebsocketKeyStateRelease)
+	return tv.websocketClient.SendKeyContext(ctx, string(key), tizenapi.WebsocketKeyStateRelease)
 }
 
 func (tv *TV) SendKeys(sequence KeySequence) error {
-	err := tv.ensureWebsocketConnection()
+	return tv.SendKeysContext(context.Background(), sequence)
+}
+
+func (tv *TV) SendKeysContext(ctx context.Context, sequence KeySequence) error {
+	err := tv.ensureWebsocketConnection(ctx)
 	if err != nil {
 		return err
 	}
@@ -326,41 +497,103 @@ func (tv *TV) SendKeys(sequence KeySequence) error {
 	for _, command := range sequence {
 		switch command.action {
 		case keyActionClick:
-			err := tv.ClickKey(command.key)
+			err := tv.ClickKeyContext(ctx, command.key)
 			if err != nil {
 				return err
 			}
 		case keyActionPress:
-			err := tv.PressKey(command.key)
+			err := tv.PressKeyContext(ctx, command.key)
 			if err != nil {
 				return err
 			}
 		case keyActionRelease:
-			err := tv.ReleaseKey(command.key)
+			err := tv.ReleaseKeyContext(ctx, command.key)
 			if err != nil {
 				return err
 			}
 		}
 
 		if command.wait > 0 {
-			time.Sleep(command.wait)
+			err := tv.sleep(ctx, command.wait)
+			if err != nil {
+				return err
+			}
 		}
 	}
 
 	return nil
 }
 
+func (tv *TV) SendText(text string) error {
+	return tv.SendTextContext(context.Background(), text)
+}
+
+func (tv *TV) SendTextContext(ctx context.Context, text string) error {
+	if !utf8.ValidString(text) {
+		return errors.New("text is not a valid UTF-8 string")
+	}
+
+	err := tv.ensureWebsocketConnection(ctx)
+	if err != nil {
+		return err
+	}
+
+	return tv.websocketClient.SendInputStringContext(ctx, text)
+}
+
+func (tv *TV) EndTextInput() error {
+	return tv.EndTextInputContext(context.Background())
+}
+
+func (tv *TV) EndTextInputContext(ctx context.Context) error {
+	err := tv.ensureWebsocketConnection(ctx)
+	if err != nil {
+		return err
+	}
+
+	return tv.websocketClient.SendInputEndContext(ctx)
+}
+
 func (tv *TV) Close() error {
-	return tv.websocketClient.Close()
+	return tv.CloseContext(context.Background())
+}
+
+func (tv *TV) CloseContext(ctx context.Context) error {
+	tv.releasePlayer()
+
+	tv.mu.Lock()
+	mediaServer := tv.mediaServer
+	eventSubscriber := tv.eventSubscriber
+	tv.mu.Unlock()
+
+	// Every resource is closed even if another fails.
+	var errs []error
+
+	if eventSubscriber != nil {
+		errs = append(errs, eventSubscriber.CloseContext(ctx))
+	}
+
+	if mediaServer != nil {
+		errs = append(errs, mediaServer.CloseContext(ctx))
+	}
+
+	if tv.artClient != nil {
+		errs = append(errs, tv.artClient.CloseContext(ctx))
+	}
+
+	errs = append(errs, tv.websocketClient.CloseContext(ctx))
+
+	return combineErrors(errs...)
 }
 
-func (tv *TV) isAvailable() bool {
-	return tv.httpClient.IsAvailable() && tv.websocketClient.IsAvailable()
+func (tv *TV) isAvailable(ctx context.Context) bool {
+	return tv.httpClient.IsAvailableContext(ctx) && tv.websocketClient.IsAvailableContext(ctx)
 }
 
-func (tv *TV) ensureWebsocketConnection() error {
-	if !tv.websocketClient.IsConnected() {
-		err := tv.establishWebsocketConnection()
+func (tv *TV) ensureWebsocketConnection(ctx context.Context) error {
+	// While the client reconnects on its own, sending is left to its send policy.
+	if !tv.websocketClient.IsConnected() && !tv.websocketClient.IsReconnecting() {
+		err := tv.establishWebsocketConnection(ctx)
 		if err != nil {
 			return err
 		}
@@ -369,21 +602,82 @@ func (tv *TV) ensureWebsocketConnection() error {
 	return nil
 }
 
-func (tv *TV) establishWebsocketConnection() error {
-	response, err := tv.websocketClient.Connect(tv.token)
+func (tv *TV) establishWebsocketConnection(ctx context.Context) error {
+	tv.mu.Lock()
+	token := tv.token
+	tv.mu.Unlock()
+
+	response, err := tv.websocketClient.ConnectContext(ctx, token)
 	if err != nil {
 		return err
 	}
 
-	if response.Data.Token != "" {
-		tv.token = response.Data.Token
-		if tv.authorizeHandler != nil {
-			err := tv.authorizeHandler(response.Data.Token)
-			if err != nil {
-				return err
-			}
-		}
+	return tv.authorize(response.Data.Token)
+}
+
+func (tv *TV) authorize(token string) error {
+	if token == "" {
+		return nil
+	}
+
+	tv.mu.Lock()
+	tv.token = token
+	handler := tv.authorizeHandler
+	tv.mu.Unlock()
+
+	if handler != nil {
+		return handler(token)
 	}
 
 	return nil
 }
+
+func (tv *TV) handleReconnect(event tizenapi.WebsocketReconnectEvent) {
+	err := event.Err
+	if err == nil {
+		err = tv.authorize(event.Response.Data.Token)
+	}
+
+	tv.mu.Lock()
+	handler := tv.reconnectHandler
+	tv.mu.Unlock()
+
+	if handler != nil {
+		handler(TVReconnect{
+			Attempt: event.Attempt,
+			Err:     err,
+			GaveUp:  event.GaveUp,
+		})
+	}
+}
+
+func (tv *TV) powerOffError(ctx context.Context) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	return errors.New("unable to power off a TV")
+}
+
+func (tv *TV) sleep(ctx context.Context, duration time.Duration) error {
+	return clock.SleepContext(ctx, tv.clock, duration)
+}
+
+// combineErrors returns the errors which are not nil as one, or nil.
+func combineErrors(errs ...error) error {
+	var messages []string
+	var last error
+
+	for _, err := range errs {
+		if err != nil {
+			messages = append(messages, err.Error())
+			last = err
+		}
+	}
+
+	if len(messages) <= 1 {
+		return last
+	}
+
+	return errors.New(strings.Join(messages, "; "))
+}
@@ -0,0 +1,177 @@
+package samsung
+
+import (
+	"context"
+	"errors"
+
+	"github.com/kpeu3i/go-tizen-tv/tizenapi"
+)
+
+const artModeChangedEvent = "art_mode_changed"
+
+var errArtNotSupported = errors.New("art mode is not supported by the TV")
+
+type ArtModeChangeHandler func(on bool)
+
+func (tv *TV) ArtModeStatus() (bool, error) {
+	return tv.ArtModeStatusContext(context.Background())
+}
+
+func (tv *TV) ArtModeStatusContext(ctx context.Context) (bool, error) {
+	err := tv.ensureArtConnection(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	return tv.artClient.GetArtModeStatusContext(ctx)
+}
+
+func (tv *TV) SetArtMode(on bool) error {
+	return tv.SetArtModeContext(context.Background(), on)
+}
+
+func (tv *TV) SetArtModeContext(ctx context.Context, on bool) error {
+	err := tv.ensureArtConnection(ctx)
+	if err != nil {
+		return err
+	}
+
+	return tv.artClient.SetArtModeStatusContext(ctx, on)
+}
+
+func (tv *TV) ArtBrightness() (int, error) {
+	return tv.ArtBrightnessContext(context.Background())
+}
+
+func (tv *TV) ArtBrightnessContext(ctx context.Context) (int, error) {
+	err := tv.ensureArtConnection(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return tv.artClient.GetBrightnessContext(ctx)
+}
+
+func (tv *TV) SetArtBrightness(value int) error {
+	return tv.SetArtBrightnessContext(context.Background(), value)
+}
+
+func (tv *TV) SetArtBrightnessContext(ctx context.Context, value int) error {
+	err := tv.ensureArtConnection(ctx)
+	if err != nil {
+		return err
+	}
+
+	return tv.artClient.SetBrightnessContext(ctx, value)
+}
+
+func (tv *TV) ArtColorTemperature() (int, error) {
+	return tv.ArtColorTemperatureContext(context.Background())
+}
+
+func (tv *TV) ArtColorTemperatureContext(ctx context.Context) (int, error) {
+	err := tv.ensureArtConnection(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return tv.artClient.GetColorTemperatureContext(ctx)
+}
+
+func (tv *TV) SetArtColorTemperature(value int) error {
+	return tv.SetArtColorTemperatureContext(context.Background(), value)
+}
+
+func (tv *TV) SetArtColorTemperatureContext(ctx context.Context, value int) error {
+	err := tv.ensureArtConnection(ctx)
+	if err != nil {
+		return err
+	}
+
+	return tv.artClient.SetColorTemperatureContext(ctx, value)
+}
+
+func (tv *TV) ArtMotionTimer() (string, error) {
+	return tv.ArtMotionTimerContext(context.Background())
+}
+
+func (tv *TV) ArtMotionTimerContext(ctx context.Context) (string, error) {
+	err := tv.ensureArtConnection(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return tv.artClient.GetMotionTimerContext(ctx)
+}
+
+func (tv *TV) SetArtMotionTimer(value string) error {
+	return tv.SetArtMotionTimerContext(context.Background(), value)
+}
+
+func (tv *TV) SetArtMotionTimerContext(ctx context.Context, value string) error {
+	err := tv.ensureArtConnection(ctx)
+	if err != nil {
+		return err
+	}
+
+	return tv.artClient.SetMotionTimerContext(ctx, value)
+}
+
+func (tv *TV) ArtMotionSensitivity() (int, error) {
+	return tv.ArtMotionSensitivityContext(context.Background())
+}
+
+func (tv *TV) ArtMotionSensitivityContext(ctx context.Context) (int, error) {
+	err := tv.ensureArtConnection(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return tv.artClient.GetMotionSensitivityContext(ctx)
+}
+
+func (tv *TV) SetArtMotionSensitivity(value int) error {
+	return tv.SetArtMotionSensitivityContext(context.Background(), value)
+}
+
+func (tv *TV) SetArtMotionSensitivityContext(ctx context.Context, value int) error {
+	err := tv.ensureArtConnection(ctx)
+	if err != nil {
+		return err
+	}
+
+	return tv.artClient.SetMotionSensitivityContext(ctx, value)
+}
+
+func (tv *TV) OnArtModeChange(handler ArtModeChangeHandler) func() {
+	if tv.artClient == nil {
+		return func() {}
+	}
+
+	return tv.artClient.Subscribe(func(data tizenapi.ArtResponseData) {
+		if data.Event == artModeChangedEvent {
+			handler(data.Status == "on")
+		}
+	})
+}
+
+func (tv *TV) ensureArtConnection(ctx context.Context) error {
+	if tv.artClient == nil {
+		return errArtNotSupported
+	}
+
+	if tv.artClient.IsConnected() {
+		return nil
+	}
+
+	tv.mu.Lock()
+	token := tv.token
+	tv.mu.Unlock()
+
+	response, err := tv.artClient.ConnectContext(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	return tv.authorize(response.Data.Token)
+}
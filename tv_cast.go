@@ -0,0 +1,329 @@
+package samsung
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/kpeu3i/go-tizen-tv/clock"
+	"github.com/kpeu3i/go-tizen-tv/mediaserver"
+	"github.com/kpeu3i/go-tizen-tv/upnp"
+)
+
+const (
+	PlayerStateStopped       = PlayerState(upnp.TransportStateStopped)
+	PlayerStatePlaying       = PlayerState(upnp.TransportStatePlaying)
+	PlayerStatePaused        = PlayerState(upnp.TransportStatePaused)
+	PlayerStateTransitioning = PlayerState(upnp.TransportStateTransitioning)
+	PlayerStateNoMedia       = PlayerState(upnp.TransportStateNoMedia)
+)
+
+var errAVTransportNotConfigured = errors.New("UPnP AV transport is not configured")
+
+type PlayerState string
+
+type MediaMetadata struct {
+	Title       string
+	Creator     string
+	MIMEType    string
+	Duration    time.Duration
+	Size        int64
+	AlbumArtURI string
+}
+
+type PlayerPosition struct {
+	Elapsed  time.Duration
+	Duration time.Duration
+	URL      string
+}
+
+// Player controls media cast to the built-in player of the TV. Media served
+// from the local media server stays published until the player stops or
+// finishes, another media is cast or the TV is closed.
+type Player struct {
+	client  AVTransportClient
+	url     string
+	clock   clock.Clock
+	mu      sync.Mutex
+	played  bool
+	release func()
+}
+
+// Cast loads the media URL into the built-in player of the TV and starts the
+// playback. The URL must be reachable from the TV.
+func (tv *TV) Cast(url string, meta MediaMetadata) (*Player, error) {
+	return tv.CastContext(context.Background(), url, meta)
+}
+
+func (tv *TV) CastContext(ctx context.Context, url string, meta MediaMetadata) (*Player, error) {
+	return tv.cast(ctx, url, meta, nil)
+}
+
+// cast plays the URL. The release function, if any, is called once the media
+// is no longer needed, which is immediately when the cast fails.
+func (tv *TV) cast(ctx context.Context, url string, meta MediaMetadata, release func()) (*Player, error) {
+	player := &Player{client: tv.avTransportClient, url: url, clock: tv.clock, release: release}
+
+	if tv.avTransportClient == nil {
+		player.releaseMedia()
+
+		return nil, errAVTransportNotConfigured
+	}
+
+	metadata := upnp.Metadata{
+		Title:       meta.Title,
+		Creator:     meta.Creator,
+		MIMEType:    meta.MIMEType,
+		Duration:    meta.Duration,
+		Size:        meta.Size,
+		AlbumArtURI: meta.AlbumArtURI,
+	}
+
+	// Some renderers refuse a new URI while playing, the error is not relevant
+	// when nothing is loaded.
+	_ = tv.avTransportClient.StopContext(ctx)
+
+	// The new media replaces whatever the previous player was playing.
+	tv.mu.Lock()
+	previous := tv.player
+	tv.player = player
+	tv.mu.Unlock()
+
+	if previous != nil {
+		previous.releaseMedia()
+	}
+
+	err := tv.avTransportClient.SetAVTransportURIContext(ctx, url, metadata)
+	if err != nil {
+		player.releaseMedia()
+
+		return nil, err
+	}
+
+	err = player.PlayContext(ctx)
+	if err != nil {
+		player.releaseMedia()
+
+		return nil, err
+	}
+
+	return player, nil
+}
+
+func (p *Player) URL() string {
+	return p.url
+}
+
+func (p *Player) Play() error {
+	return p.PlayContext(context.Background())
+}
+
+func (p *Player) PlayContext(ctx context.Context) error {
+	return p.client.PlayContext(ctx)
+}
+
+func (p *Player) Pause() error {
+	return p.PauseContext(context.Background())
+}
+
+func (p *Player) PauseContext(ctx context.Context) error {
+	return p.client.PauseContext(ctx)
+}
+
+func (p *Player) Stop() error {
+	return p.StopContext(context.Background())
+}
+
+// StopContext stops the playback. Media served from the local media server is
+// unpublished, so the player cannot be resumed afterwards.
+func (p *Player) StopContext(ctx context.Context) error {
+	err := p.client.StopContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	p.releaseMedia()
+
+	return nil
+}
+
+func (p *Player) Seek(position time.Duration) error {
+	return p.SeekContext(context.Background(), position)
+}
+
+func (p *Player) SeekContext(ctx context.Context, position time.Duration) error {
+	return p.client.SeekContext(ctx, position)
+}
+
+func (p *Player) State() (PlayerState, error) {
+	return p.StateContext(context.Background())
+}
+
+func (p *Player) StateContext(ctx context.Context) (PlayerState, error) {
+	info, err := p.client.GetTransportInfoContext(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	state := PlayerState(info.State)
+
+	// A player which has been playing and stopped has finished the media.
+	p.mu.Lock()
+	switch state {
+	case PlayerStatePlaying, PlayerStatePaused:
+		p.played = true
+	}
+	finished := p.played && (state == PlayerStateStopped || state == PlayerStateNoMedia)
+	p.mu.Unlock()
+
+	if finished {
+		p.releaseMedia()
+	}
+
+	return state, nil
+}
+
+func (p *Player) Position() (PlayerPosition, error) {
+	return p.PositionContext(context.Background())
+}
+
+func (p *Player) PositionContext(ctx context.Context) (PlayerPosition, error) {
+	info, err := p.client.GetPositionInfoContext(ctx)
+	if err != nil {
+		return PlayerPosition{}, err
+	}
+
+	position := PlayerPosition{
+		Elapsed:  info.RelTime,
+		Duration: info.TrackDuration,
+		URL:      info.TrackURI,
+	}
+
+	return position, nil
+}
+
+// WaitState polls the player until it reaches one of the given states.
+func (p *Player) WaitState(ctx context.Context, interval time.Duration, states ...PlayerState) (PlayerState, error) {
+	for {
+		state, err := p.StateContext(ctx)
+		if err != nil {
+			return "", err
+		}
+
+		for _, s := range states {
+			if state == s {
+				return state, nil
+			}
+		}
+
+		err = clock.SleepContext(ctx, p.clock, interval)
+		if err != nil {
+			return "", err
+		}
+	}
+}
+
+// CastFile serves a local file to the TV and plays it in the built-in player.
+func (tv *TV) CastFile(path string) (*Player, error) {
+	return tv.CastFileContext(context.Background(), path)
+}
+
+func (tv *TV) CastFileContext(ctx context.Context, path string) (*Player, error) {
+	server, err := tv.ensureMediaServer()
+	if err != nil {
+		return nil, err
+	}
+
+	media, err := server.PublishFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return tv.castMedia(ctx, server, media)
+}
+
+// CastReader serves the reader to the TV and plays it in the built-in player.
+// An empty MIME type is guessed from the name.
+func (tv *TV) CastReader(name string, mimeType string, reader io.ReadSeeker) (*Player, error) {
+	return tv.CastReaderContext(context.Background(), name, mimeType, reader)
+}
+
+func (tv *TV) CastReaderContext(
+	ctx context.Context,
+	name string,
+	mimeType string,
+	reader io.ReadSeeker,
+) (*Player, error) {
+	server, err := tv.ensureMediaServer()
+	if err != nil {
+		return nil, err
+	}
+
+	media, err := server.PublishReader(name, mimeType, reader)
+	if err != nil {
+		return nil, err
+	}
+
+	return tv.castMedia(ctx, server, media)
+}
+
+func (tv *TV) castMedia(ctx context.Context, server MediaServer, media mediaserver.Media) (*Player, error) {
+	url, err := server.URL(media.ID, tv.httpClient.Host())
+	if err != nil {
+		server.Unpublish(media.ID)
+
+		return nil, err
+	}
+
+	meta := MediaMetadata{
+		Title:    media.Name,
+		MIMEType: media.MIMEType,
+		Size:     media.Size,
+	}
+
+	return tv.cast(ctx, url, meta, func() {
+		server.Unpublish(media.ID)
+	})
+}
+
+// releaseMedia unpublishes the media of the player once.
+func (p *Player) releaseMedia() {
+	p.mu.Lock()
+	release := p.release
+	p.release = nil
+	p.mu.Unlock()
+
+	if release != nil {
+		release()
+	}
+}
+
+// releasePlayer unpublishes the media of the last cast.
+func (tv *TV) releasePlayer() {
+	tv.mu.Lock()
+	player := tv.player
+	tv.player = nil
+	tv.mu.Unlock()
+
+	if player != nil {
+		player.releaseMedia()
+	}
+}
+
+func (tv *TV) ensureMediaServer() (MediaServer, error) {
+	tv.mu.Lock()
+	if tv.mediaServer == nil {
+		tv.mediaServer = mediaserver.NewServer()
+	}
+	server := tv.mediaServer
+	tv.mu.Unlock()
+
+	err := server.Start()
+	if err != nil {
+		return nil, err
+	}
+
+	return server, nil
+}
@@ -0,0 +1,270 @@
+package samsung_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	samsung "github.com/kpeu3i/go-tizen-tv"
+	"github.com/kpeu3i/go-tizen-tv/mediaserver"
+	"github.com/kpeu3i/go-tizen-tv/tizenapi"
+	"github.com/kpeu3i/go-tizen-tv/upnp"
+)
+
+// fakeAVTransport is a renderer which plays whatever it is told instantly.
+type fakeAVTransport struct {
+	mu    sync.Mutex
+	uri   string
+	state string
+}
+
+func (f *fakeAVTransport) Location() string { return "http://127.0.0.1/dmr" }
+
+func (f *fakeAVTransport) SetAVTransportURI(uri string, metadata upnp.Metadata) error {
+	return f.SetAVTransportURIContext(context.Background(), uri, metadata)
+}
+
+func (f *fakeAVTransport) SetAVTransportURIContext(_ context.Context, uri string, _ upnp.Metadata) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.uri = uri
+	f.state = upnp.TransportStateStopped
+
+	return nil
+}
+
+func (f *fakeAVTransport) Play() error { return f.PlayContext(context.Background()) }
+
+func (f *fakeAVTransport) PlayContext(context.Context) error {
+	f.setState(upnp.TransportStatePlaying)
+
+	return nil
+}
+
+func (f *fakeAVTransport) Pause() error { return f.PauseContext(context.Background()) }
+
+func (f *fakeAVTransport) PauseContext(context.Context) error {
+	f.setState(upnp.TransportStatePaused)
+
+	return nil
+}
+
+func (f *fakeAVTransport) Stop() error { return f.StopContext(context.Background()) }
+
+func (f *fakeAVTransport) StopContext(context.Context) error {
+	f.setState(upnp.TransportStateStopped)
+
+	return nil
+}
+
+func (f *fakeAVTransport) Seek(position time.Duration) error {
+	return f.SeekContext(context.Background(), position)
+}
+
+func (f *fakeAVTransport) SeekContext(context.Context, time.Duration) error { return nil }
+
+func (f *fakeAVTransport) GetTransportInfo() (upnp.TransportInfo, error) {
+	return f.GetTransportInfoContext(context.Background())
+}
+
+func (f *fakeAVTransport) GetTransportInfoContext(context.Context) (upnp.TransportInfo, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return upnp.TransportInfo{State: f.state, Status: "OK", Speed: "1"}, nil
+}
+
+func (f *fakeAVTransport) GetPositionInfo() (upnp.PositionInfo, error) {
+	return f.GetPositionInfoContext(context.Background())
+}
+
+func (f *fakeAVTransport) GetPositionInfoContext(context.Context) (upnp.PositionInfo, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return upnp.PositionInfo{TrackURI: f.uri}, nil
+}
+
+func (f *fakeAVTransport) setState(state string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.state = state
+}
+
+// recordingMediaServer records the media unpublished from the server.
+type recordingMediaServer struct {
+	*mediaserver.Server
+
+	mu          sync.Mutex
+	unpublished []string
+}
+
+func (s *recordingMediaServer) Unpublish(id string) {
+	s.mu.Lock()
+	s.unpublished = append(s.unpublished, id)
+	s.mu.Unlock()
+
+	s.Server.Unpublish(id)
+}
+
+func (s *recordingMediaServer) isUnpublished(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, unpublished := range s.unpublished {
+		if unpublished == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+func newCastTV() (*samsung.TV, *fakeAVTransport, *recordingMediaServer) {
+	transport := &fakeAVTransport{state: upnp.TransportStateNoMedia}
+	server := &recordingMediaServer{
+		Server: mediaserver.NewServer(
+			mediaserver.WithListenAddress("127.0.0.1:0"),
+			mediaserver.WithAdvertisedHost("127.0.0.1"),
+		),
+	}
+
+	tv := samsung.NewTV(
+		tizenapi.NewUDPAPIClient(""),
+		tizenapi.NewHTTPAPIClient("127.0.0.1"),
+		tizenapi.NewWebsocketAPIClient("127.0.0.1", "tizentest"),
+		"",
+		samsung.WithAVTransportClient(transport),
+		samsung.WithMediaServer(server),
+	)
+
+	return tv, transport, server
+}
+
+func mediaStatus(t *testing.T, url string) int {
+	t.Helper()
+
+	response, err := http.Get(url)
+	if err != nil {
+		t.Fatalf("get %s: %v", url, err)
+	}
+
+	_ = response.Body.Close()
+
+	return response.StatusCode
+}
+
+// mediaID returns the ID of the media from its URL, /media/{id}/{name}.
+func mediaID(t *testing.T, url string) string {
+	t.Helper()
+
+	parts := strings.Split(url, "/")
+	if len(parts) < 2 {
+		t.Fatalf("invalid media URL: %s", url)
+	}
+
+	return parts[len(parts)-2]
+}
+
+func TestCastReaderUnpublishesMedia(t *testing.T) {
+	tests := []struct {
+		name string
+		end  func(tv *samsung.TV, player *samsung.Player, transport *fakeAVTransport) error
+	}{
+		{
+			name: "stop",
+			end: func(_ *samsung.TV, player *samsung.Player, _ *fakeAVTransport) error {
+				return player.Stop()
+			},
+		},
+		{
+			name: "finish",
+			end: func(_ *samsung.TV, player *samsung.Player, transport *fakeAVTransport) error {
+				transport.setState(upnp.TransportStateStopped)
+
+				_, err := player.WaitState(context.Background(), time.Millisecond, samsung.PlayerStateStopped)
+
+				return err
+			},
+		},
+		{
+			name: "next cast",
+			end: func(tv *samsung.TV, _ *samsung.Player, _ *fakeAVTransport) error {
+				_, err := tv.CastReader("next.mp3", "", bytes.NewReader([]byte("next")))
+
+				return err
+			},
+		},
+		{
+			name: "close",
+			end: func(tv *samsung.TV, _ *samsung.Player, _ *fakeAVTransport) error {
+				return tv.Close()
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tv, transport, server := newCastTV()
+			defer func() {
+				_ = tv.Close()
+			}()
+
+			player, err := tv.CastReader("song.mp3", "", bytes.NewReader([]byte("tizentest")))
+			if err != nil {
+				t.Fatalf("CastReader() = %v", err)
+			}
+
+			id := mediaID(t, player.URL())
+
+			state, err := player.State()
+			if err != nil || state != samsung.PlayerStatePlaying {
+				t.Fatalf("State() = %s, %v, want %s", state, err, samsung.PlayerStatePlaying)
+			}
+
+			if status := mediaStatus(t, player.URL()); status != http.StatusOK {
+				t.Fatalf("media status = %d, want %d", status, http.StatusOK)
+			}
+
+			if server.isUnpublished(id) {
+				t.Fatal("media has been unpublished while playing")
+			}
+
+			err = test.end(tv, player, transport)
+			if err != nil {
+				t.Fatalf("end = %v", err)
+			}
+
+			if !server.isUnpublished(id) {
+				t.Error("media has not been unpublished")
+			}
+		})
+	}
+}
+
+func TestPlayerStopReleasesReader(t *testing.T) {
+	tv, _, _ := newCastTV()
+	defer func() {
+		_ = tv.Close()
+	}()
+
+	player, err := tv.CastReader("song.mp3", "", bytes.NewReader([]byte("tizentest")))
+	if err != nil {
+		t.Fatalf("CastReader() = %v", err)
+	}
+
+	err = player.Stop()
+	if err != nil {
+		t.Fatalf("Stop() = %v", err)
+	}
+
+	if status := mediaStatus(t, player.URL()); status != http.StatusNotFound {
+		t.Errorf("media status = %d, want %d", status, http.StatusNotFound)
+	}
+}
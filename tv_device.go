@@ -22,6 +22,12 @@ func (d TVDevice) MAC() string {
 	return d["wifiMac"]
 }
 
+// PowerState returns the raw power state, "on" or "standby". It is empty on
+// TVs which do not report one.
+func (d TVDevice) PowerState() string {
+	return d["PowerState"]
+}
+
 func (d TVDevice) TokenAuthSupport() bool {
 	if d["TokenAuthSupport"] == "" {
 		return false
@@ -0,0 +1,50 @@
+package samsung
+
+import (
+	"context"
+	"errors"
+
+	"github.com/kpeu3i/go-tizen-tv/dial"
+)
+
+var errDIALNotConfigured = errors.New("DIAL client is not configured")
+
+// DIALApp returns the state of an app as reported by the DIAL server, e.g.
+// "YouTube" or "Netflix".
+func (tv *TV) DIALApp(name string) (dial.App, error) {
+	return tv.DIALAppContext(context.Background(), name)
+}
+
+func (tv *TV) DIALAppContext(ctx context.Context, name string) (dial.App, error) {
+	if tv.dialClient == nil {
+		return dial.App{}, errDIALNotConfigured
+	}
+
+	return tv.dialClient.GetAppContext(ctx, name)
+}
+
+// OpenDIALApp starts an app with a launch payload, which OpenApp cannot send,
+// and returns the URL of the started instance for CloseDIALApp.
+func (tv *TV) OpenDIALApp(name string, payload string) (string, error) {
+	return tv.OpenDIALAppContext(context.Background(), name, payload)
+}
+
+func (tv *TV) OpenDIALAppContext(ctx context.Context, name string, payload string) (string, error) {
+	if tv.dialClient == nil {
+		return "", errDIALNotConfigured
+	}
+
+	return tv.dialClient.LaunchAppContext(ctx, name, payload)
+}
+
+func (tv *TV) CloseDIALApp(instanceURL string) error {
+	return tv.CloseDIALAppContext(context.Background(), instanceURL)
+}
+
+func (tv *TV) CloseDIALAppContext(ctx context.Context, instanceURL string) error {
+	if tv.dialClient == nil {
+		return errDIALNotConfigured
+	}
+
+	return tv.dialClient.StopAppContext(ctx, instanceURL)
+}
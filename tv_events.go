@@ -0,0 +1,75 @@
+package samsung
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/kpeu3i/go-tizen-tv/tizenapi"
+)
+
+type TVClient struct {
+	ID          string
+	Name        string
+	DeviceName  string
+	IsHost      bool
+	ConnectedAt time.Time
+}
+
+type TVUpdate struct {
+	Type string
+}
+
+type (
+	TVClientEventHandler func(client TVClient)
+	TVUpdateEventHandler func(update TVUpdate)
+)
+
+func (tv *TV) OnClientConnect(handler TVClientEventHandler) func() {
+	return tv.websocketClient.Subscribe(
+		tizenapi.WebsocketEventChannelClientConnect,
+		clientEventHandler(handler),
+	)
+}
+
+func (tv *TV) OnClientDisconnect(handler TVClientEventHandler) func() {
+	return tv.websocketClient.Subscribe(
+		tizenapi.WebsocketEventChannelClientDisconnect,
+		clientEventHandler(handler),
+	)
+}
+
+func (tv *TV) OnUpdate(handler TVUpdateEventHandler) func() {
+	return tv.websocketClient.Subscribe(
+		tizenapi.WebsocketEventEdenTVUpdate,
+		func(message tizenapi.EventMessage) {
+			var data struct {
+				UpdateType string `json:"update_type"`
+			}
+
+			err := json.Unmarshal(message.Data, &data)
+			if err != nil {
+				return
+			}
+
+			handler(TVUpdate{Type: data.UpdateType})
+		},
+	)
+}
+
+func clientEventHandler(handler TVClientEventHandler) tizenapi.WebsocketEventHandler {
+	return func(message tizenapi.EventMessage) {
+		client := tizenapi.ChannelClient{}
+		err := json.Unmarshal(message.Data, &client)
+		if err != nil {
+			return
+		}
+
+		handler(TVClient{
+			ID:          client.ID,
+			Name:        client.Attributes.Name,
+			DeviceName:  client.DeviceName,
+			IsHost:      client.IsHost,
+			ConnectedAt: time.Unix(0, int64(client.ConnectTime)*int64(time.Millisecond)),
+		})
+	}
+}
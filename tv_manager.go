@@ -1,18 +1,32 @@
 package samsung
 
 import (
-	"errors"
+	"context"
+	"encoding/hex"
 	"fmt"
+	"net"
+	"net/http"
 	"net/url"
+	"strings"
 	"time"
 
+	"github.com/kpeu3i/go-tizen-tv/clock"
+	"github.com/kpeu3i/go-tizen-tv/dial"
 	"github.com/kpeu3i/go-tizen-tv/ssdp"
 	"github.com/kpeu3i/go-tizen-tv/tizenapi"
+	"github.com/kpeu3i/go-tizen-tv/upnp"
+	"github.com/kpeu3i/go-tizen-tv/wolrelay"
 )
 
 const (
 	defaultClientID          = "GoTizenTV"
 	defaultConfigStoragePath = "config.yaml"
+	defaultUPnPPort          = "9197"
+	defaultUPnPPath          = "/dmr"
+	upnpMediaRendererType    = "urn:schemas-upnp-org:device:MediaRenderer"
+	upnpDescriptionTimeout   = 2 * time.Second
+	defaultDIALPort          = "8080"
+	defaultDIALPath          = "/ws/apps/"
 )
 
 type TVConfigStorage interface {
@@ -22,6 +36,7 @@ type TVConfigStorage interface {
 
 type SSDPDiscoverer interface {
 	Discover() ([]ssdp.Service, error)
+	DiscoverContext(ctx context.Context) ([]ssdp.Service, error)
 }
 
 type (
@@ -33,6 +48,26 @@ type (
 		clientID string,
 		options ...tizenapi.WebsocketAPIOption,
 	) WebsocketAPIClient
+	ArtAPIClientFactory func(
+		host string,
+		clientID string,
+		options ...tizenapi.WebsocketAPIOption,
+	) ArtAPIClient
+	RenderingControlClientFactory func(location string, options ...upnp.Option) RenderingControlClient
+	AVTransportClientFactory      func(location string, options ...upnp.Option) AVTransportClient
+	DIALClientFactory             func(applicationURL string, options ...dial.Option) DIALClient
+	LegacyHTTPAPIClientFactory    func(location string, options ...tizenapi.HTTPAPIOption) HTTPAPIClient
+	LegacyAPIClientFactory        func(
+		host string,
+		clientID string,
+		options ...tizenapi.LegacyAPIOption,
+	) WebsocketAPIClient
+	EncryptedAPIClientFactory func(
+		host string,
+		clientID string,
+		options ...tizenapi.EncryptedAPIOption,
+	) WebsocketAPIClient
+	PairingAPIClientFactory func(host string, options ...tizenapi.PairingAPIOption) PairingAPIClient
 )
 
 type TVManagerOption func(*TVManager)
@@ -67,13 +102,92 @@ func WithTVManagerWebsocketAPIClientFactory(factory WebsocketAPIClientFactory) T
 	}
 }
 
+func WithTVManagerArtAPIClientFactory(factory ArtAPIClientFactory) TVManagerOption {
+	return func(manager *TVManager) {
+		manager.artClientFactory = factory
+	}
+}
+
+func WithTVManagerRenderingControlClientFactory(factory RenderingControlClientFactory) TVManagerOption {
+	return func(manager *TVManager) {
+		manager.renderingControlClientFactory = factory
+	}
+}
+
+func WithTVManagerAVTransportClientFactory(factory AVTransportClientFactory) TVManagerOption {
+	return func(manager *TVManager) {
+		manager.avTransportClientFactory = factory
+	}
+}
+
+func WithTVManagerDIALClientFactory(factory DIALClientFactory) TVManagerOption {
+	return func(manager *TVManager) {
+		manager.dialClientFactory = factory
+	}
+}
+
+func WithTVManagerLegacyHTTPAPIClientFactory(factory LegacyHTTPAPIClientFactory) TVManagerOption {
+	return func(manager *TVManager) {
+		manager.legacyHTTPClientFactory = factory
+	}
+}
+
+func WithTVManagerLegacyAPIClientFactory(factory LegacyAPIClientFactory) TVManagerOption {
+	return func(manager *TVManager) {
+		manager.legacyClientFactory = factory
+	}
+}
+
+func WithTVManagerEncryptedAPIClientFactory(factory EncryptedAPIClientFactory) TVManagerOption {
+	return func(manager *TVManager) {
+		manager.encryptedClientFactory = factory
+	}
+}
+
+func WithTVManagerPairingAPIClientFactory(factory PairingAPIClientFactory) TVManagerOption {
+	return func(manager *TVManager) {
+		manager.pairingClientFactory = factory
+	}
+}
+
+// WithTVManagerPairingCrypto sets the handshake crypto used to pair with TVs
+// which require a PIN. Without it those TVs cannot be paired.
+func WithTVManagerPairingCrypto(crypto tizenapi.PairingCrypto) TVManagerOption {
+	return func(manager *TVManager) {
+		manager.pairingCrypto = crypto
+	}
+}
+
+// WithTVManagerClock sets the clock passed to the TVs and their clients.
+func WithTVManagerClock(c clock.Clock) TVManagerOption {
+	return func(manager *TVManager) {
+		manager.clock = c
+	}
+}
+
 type TVManager struct {
-	configStorage          TVConfigStorage
-	ssdpDiscovererFactory  SSDPDiscovererFactory
-	udpClientFactory       UDPAPIClientFactory
-	httpClientFactory      HTTPAPIClientFactory
-	websocketClientFactory WebsocketAPIClientFactory
-	ssdpDiscoverer         SSDPDiscoverer
+	configStorage                 TVConfigStorage
+	ssdpDiscovererFactory         SSDPDiscovererFactory
+	udpClientFactory              UDPAPIClientFactory
+	httpClientFactory             HTTPAPIClientFactory
+	websocketClientFactory        WebsocketAPIClientFactory
+	artClientFactory              ArtAPIClientFactory
+	renderingControlClientFactory RenderingControlClientFactory
+	avTransportClientFactory      AVTransportClientFactory
+	dialClientFactory             DIALClientFactory
+	legacyHTTPClientFactory       LegacyHTTPAPIClientFactory
+	legacyClientFactory           LegacyAPIClientFactory
+	encryptedClientFactory        EncryptedAPIClientFactory
+	pairingClientFactory          PairingAPIClientFactory
+	pairingCrypto                 tizenapi.PairingCrypto
+	clock                         clock.Clock
+	ssdpDiscoverer                SSDPDiscoverer
+}
+
+type discoveredHost struct {
+	host      string
+	mac       string
+	locations []string
 }
 
 func NewTVManager(options ...TVManagerOption) *TVManager {
@@ -95,6 +209,43 @@ func NewTVManager(options ...TVManagerOption) *TVManager {
 		) WebsocketAPIClient {
 			return tizenapi.NewWebsocketAPIClient(host, clientID, options...)
 		},
+		artClientFactory: func(
+			host string,
+			clientID string,
+			options ...tizenapi.WebsocketAPIOption,
+		) ArtAPIClient {
+			return tizenapi.NewArtAPIClient(host, clientID, options...)
+		},
+		renderingControlClientFactory: func(location string, options ...upnp.Option) RenderingControlClient {
+			return upnp.NewRenderingControlClient(location, options...)
+		},
+		avTransportClientFactory: func(location string, options ...upnp.Option) AVTransportClient {
+			return upnp.NewAVTransportClient(location, options...)
+		},
+		dialClientFactory: func(applicationURL string, options ...dial.Option) DIALClient {
+			return dial.NewClient(applicationURL, options...)
+		},
+		legacyHTTPClientFactory: func(location string, options ...tizenapi.HTTPAPIOption) HTTPAPIClient {
+			return tizenapi.NewLegacyHTTPAPIClient(location, options...)
+		},
+		legacyClientFactory: func(
+			host string,
+			clientID string,
+			options ...tizenapi.LegacyAPIOption,
+		) WebsocketAPIClient {
+			return tizenapi.NewLegacyAPIClient(host, clientID, options...)
+		},
+		encryptedClientFactory: func(
+			host string,
+			clientID string,
+			options ...tizenapi.EncryptedAPIOption,
+		) WebsocketAPIClient {
+			return tizenapi.NewEncryptedAPIClient(host, clientID, options...)
+		},
+		pairingClientFactory: func(host string, options ...tizenapi.PairingAPIOption) PairingAPIClient {
+			return tizenapi.NewPairingAPIClient(host, options...)
+		},
+		clock: clock.New(),
 	}
 
 	for _, option := range options {
@@ -105,19 +256,33 @@ func NewTVManager(options ...TVManagerOption) *TVManager {
 }
 
 func (m *TVManager) Discover() ([]*TV, error) {
-	hosts, err := m.discoverHosts()
+	return m.DiscoverContext(context.Background())
+}
+
+func (m *TVManager) DiscoverContext(ctx context.Context) ([]*TV, error) {
+	hosts, err := m.discoverHosts(ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	tvs := make([]*TV, 0, len(hosts))
 	for _, host := range hosts {
-		httpClient := m.httpClientFactory(host)
-		if !httpClient.IsAvailable() {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		httpClient := m.httpClientFactory(host.host)
+		if !httpClient.IsAvailableContext(ctx) {
+			// Pre-2016 TVs have no HTTP API but may speak an older protocol.
+			tv, ok := m.discoverPreTizenTV(ctx, host)
+			if ok {
+				tvs = append(tvs, tv)
+			}
+
 			continue
 		}
 
-		info, err := httpClient.GetInfo()
+		info, err := httpClient.GetInfoContext(ctx)
 		if err != nil {
 			continue
 		}
@@ -130,20 +295,26 @@ func (m *TVManager) Discover() ([]*TV, error) {
 			tizenapi.WithWebsocketIsSecure(true),
 		)
 
-		if !websocketClient.IsAvailable() {
+		if !websocketClient.IsAvailableContext(ctx) {
 			websocketClient = m.websocketClientFactory(device.IP(), defaultClientID)
-			if !websocketClient.IsAvailable() {
+			if !websocketClient.IsAvailableContext(ctx) {
 				continue
 			}
 		}
 
 		udpClient := m.udpClientFactory(device.MAC())
+		renderingControlClient := m.renderingControlClientFactory(
+			m.discoverMediaRendererLocation(ctx, device.IP(), host.locations),
+		)
+		dialClient := m.dialClientFactory(m.discoverDIALApplicationURL(ctx, device.IP(), host.locations))
 
 		deviceConfig := buildDeviceConfig(
 			info.Device,
 			udpClient,
 			httpClient,
 			websocketClient,
+			renderingControlClient,
+			dialClient,
 		)
 
 		tvs = append(tvs, m.createTV(deviceConfig))
@@ -152,7 +323,55 @@ func (m *TVManager) Discover() ([]*TV, error) {
 	return tvs, nil
 }
 
+func (m *TVManager) discoverPreTizenTV(ctx context.Context, host discoveredHost) (*TV, bool) {
+	var websocketClient WebsocketAPIClient
+
+	pairingClient := m.pairingClientFactory(host.host)
+	if pairingClient.IsAvailableContext(ctx) {
+		websocketClient = m.encryptedClientFactory(host.host, defaultClientID)
+	} else {
+		websocketClient = m.legacyClientFactory(host.host, defaultClientID)
+		if !websocketClient.IsAvailableContext(ctx) {
+			return nil, false
+		}
+	}
+
+	location, ok := m.discoverDescriptionLocation(ctx, host.locations)
+	if !ok {
+		return nil, false
+	}
+
+	httpClient := m.legacyHTTPClientFactory(location)
+
+	info, err := httpClient.GetInfoContext(ctx)
+	if err != nil {
+		return nil, false
+	}
+
+	// The description has no MAC address, it is needed to wake the TV later.
+	if info.Device["wifiMac"] == "" {
+		info.Device["wifiMac"] = m.discoverMAC(host)
+	}
+
+	device := TVDevice(info.Device)
+
+	deviceConfig := buildDeviceConfig(
+		info.Device,
+		m.udpClientFactory(device.MAC()),
+		httpClient,
+		websocketClient,
+		m.renderingControlClientFactory(location),
+		m.dialClientFactory(m.discoverDIALApplicationURL(ctx, device.IP(), host.locations)),
+	)
+
+	return m.createTV(deviceConfig), true
+}
+
 func (m *TVManager) Store(tvs ...*TV) error {
+	return m.StoreContext(context.Background(), tvs...)
+}
+
+func (m *TVManager) StoreContext(ctx context.Context, tvs ...*TV) error {
 	if len(tvs) == 0 {
 		return nil
 	}
@@ -163,16 +382,24 @@ func (m *TVManager) Store(tvs ...*TV) error {
 	}
 
 	for _, tv := range tvs {
-		info, err := tv.Info()
+		info, err := tv.InfoContext(ctx)
 		if err != nil {
 			return err
 		}
 
+		// Legacy TVs describe themselves without a MAC address, the one used
+		// to wake them is kept.
+		if info.Device != nil && info.Device.MAC() == "" {
+			info.Device["wifiMac"] = tv.udpClient.MAC()
+		}
+
 		newDeviceConfig := buildDeviceConfig(
 			info.Device,
 			tv.udpClient,
 			tv.httpClient,
 			tv.websocketClient,
+			tv.renderingControlClient,
+			tv.dialClient,
 		)
 
 		existingDeviceConfig, exists := config.DeviceConfig(info.Device.ID())
@@ -180,6 +407,26 @@ func (m *TVManager) Store(tvs ...*TV) error {
 			newDeviceConfig.Name = existingDeviceConfig.Name
 			newDeviceConfig.WebsocketAPI.ClientID = existingDeviceConfig.WebsocketAPI.ClientID
 			newDeviceConfig.WebsocketAPI.Token = existingDeviceConfig.WebsocketAPI.Token
+
+			if newDeviceConfig.MAC == "" {
+				newDeviceConfig.MAC = existingDeviceConfig.MAC
+			}
+
+			newDeviceConfig.UDPAPI.SecureOn = existingDeviceConfig.UDPAPI.SecureOn
+			newDeviceConfig.UDPAPI.IPv6 = existingDeviceConfig.UDPAPI.IPv6
+			newDeviceConfig.UDPAPI.Relay = existingDeviceConfig.UDPAPI.Relay
+			newDeviceConfig.UDPAPI.RelaySecret = existingDeviceConfig.UDPAPI.RelaySecret
+
+			for _, mac := range append([]string{existingDeviceConfig.MAC}, existingDeviceConfig.MACs...) {
+				if mac != "" && !strings.EqualFold(mac, newDeviceConfig.MAC) {
+					newDeviceConfig.MACs = appendUnique(newDeviceConfig.MACs, mac)
+				}
+			}
+
+			if newDeviceConfig.WebsocketAPI.SessionID == "" {
+				newDeviceConfig.WebsocketAPI.SessionID = existingDeviceConfig.WebsocketAPI.SessionID
+				newDeviceConfig.WebsocketAPI.SessionKey = existingDeviceConfig.WebsocketAPI.SessionKey
+			}
 		}
 
 		config.SetDeviceConfig(newDeviceConfig)
@@ -217,37 +464,110 @@ func (m *TVManager) LoadByID(id string) (*TV, error) {
 	return nil, fmt.Errorf("tv %s not found", id)
 }
 
-func (m *TVManager) discoverHosts() ([]string, error) {
+func (m *TVManager) discoverHosts(ctx context.Context) ([]discoveredHost, error) {
 	discoverer, err := m.discoverer()
 	if err != nil {
 		return nil, err
 	}
 
-	services, err := discoverer.Discover()
+	services, err := discoverer.DiscoverContext(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	var hosts []string
+	var hosts []discoveredHost
 
-	exists := map[string]struct{}{}
+	indexes := map[string]int{}
 	for _, service := range services {
 		u, err := url.Parse(service.Location)
 		if err != nil {
 			continue
 		}
 
-		if _, ok := exists[u.Hostname()]; ok {
-			continue
+		i, ok := indexes[u.Hostname()]
+		if !ok {
+			i = len(hosts)
+			hosts = append(hosts, discoveredHost{host: u.Hostname()})
+			indexes[u.Hostname()] = i
 		}
 
-		hosts = append(hosts, u.Hostname())
-		exists[u.Hostname()] = struct{}{}
+		hosts[i].locations = appendUnique(hosts[i].locations, service.Location)
+
+		if mac := dial.WakeUpMAC(service.WakeUp); mac != "" {
+			hosts[i].mac = mac
+		}
 	}
 
 	return hosts, nil
 }
 
+// discoverMediaRendererLocation picks the SSDP location which describes a
+// MediaRenderer device. The TV announces several devices on different ports,
+// so every location is checked before falling back to the well-known one.
+func (m *TVManager) discoverMediaRendererLocation(ctx context.Context, host string, locations []string) string {
+	httpClient := &http.Client{Timeout: upnpDescriptionTimeout}
+
+	for _, location := range locations {
+		description, err := upnp.FetchDeviceDescription(ctx, httpClient, location)
+		if err != nil {
+			continue
+		}
+
+		if description.Device.IsType(upnpMediaRendererType) {
+			return location
+		}
+	}
+
+	return defaultMediaRendererLocation(host)
+}
+
+// discoverDescriptionLocation returns a location with a valid device
+// description, preferring the one of a MediaRenderer.
+func (m *TVManager) discoverDescriptionLocation(ctx context.Context, locations []string) (string, bool) {
+	httpClient := &http.Client{Timeout: upnpDescriptionTimeout}
+
+	fallback := ""
+	for _, location := range locations {
+		description, err := upnp.FetchDeviceDescription(ctx, httpClient, location)
+		if err != nil {
+			continue
+		}
+
+		if description.Device.IsType(upnpMediaRendererType) {
+			return location, true
+		}
+
+		if fallback == "" {
+			fallback = location
+		}
+	}
+
+	return fallback, fallback != ""
+}
+
+// discoverDIALApplicationURL looks for the Application-URL header which the
+// DIAL server adds to the description of its device.
+func (m *TVManager) discoverDIALApplicationURL(ctx context.Context, host string, locations []string) string {
+	for _, location := range locations {
+		applicationURL, err := dial.DiscoverApplicationURL(ctx, location, dial.WithRequestTimeout(upnpDescriptionTimeout))
+		if err == nil {
+			return applicationURL
+		}
+	}
+
+	return defaultDIALApplicationURL(host)
+}
+
+// discoverMAC returns the MAC address announced by the DIAL server of the
+// host, falling back to the ARP cache which has it after discovery.
+func (m *TVManager) discoverMAC(host discoveredHost) string {
+	if host.mac != "" {
+		return host.mac
+	}
+
+	return tizenapi.LookupMAC(host.host)
+}
+
 func (m *TVManager) discoverer() (SSDPDiscoverer, error) {
 	if m.ssdpDiscoverer == nil {
 		config, err := m.loadConfig()
@@ -267,6 +587,20 @@ func (m *TVManager) createTV(deviceConfig DeviceConfig) *TV {
 	udpClientOptions := []tizenapi.UDPAPIOption{
 		tizenapi.WithUDPSubnet(deviceConfig.UDPAPI.Subnet),
 		tizenapi.WithUDPPort(deviceConfig.UDPAPI.Port),
+		tizenapi.WithUDPMACs(deviceConfig.MACs...),
+		tizenapi.WithUDPSecureOn(deviceConfig.UDPAPI.SecureOn),
+		tizenapi.WithUDPTargetHost(deviceConfig.Host),
+		tizenapi.WithUDPIPv6(deviceConfig.UDPAPI.IPv6),
+	}
+
+	if deviceConfig.UDPAPI.Relay != "" {
+		udpClientOptions = append(udpClientOptions, tizenapi.WithUDPRelay(
+			wolrelay.NewClient(
+				deviceConfig.UDPAPI.Relay,
+				[]byte(deviceConfig.UDPAPI.RelaySecret),
+				wolrelay.WithClientClock(m.clock),
+			),
+		))
 	}
 
 	httpClientOptions := []tizenapi.HTTPAPIOption{
@@ -281,29 +615,112 @@ func (m *TVManager) createTV(deviceConfig DeviceConfig) *TV {
 		tizenapi.WithWebsocketPort(deviceConfig.WebsocketAPI.Port),
 		tizenapi.WithWebsocketReadTimeout(deviceConfig.WebsocketAPI.ReadTimeout),
 		tizenapi.WithWebsocketWriteTimeout(deviceConfig.WebsocketAPI.WriteTimeout),
+		tizenapi.WithWebsocketClock(m.clock),
+	}
+
+	upnpLocation := deviceConfig.UPnP.Location
+	if upnpLocation == "" {
+		upnpLocation = defaultMediaRendererLocation(deviceConfig.Host)
+	}
+
+	dialApplicationURL := deviceConfig.DIAL.ApplicationURL
+	if dialApplicationURL == "" {
+		dialApplicationURL = defaultDIALApplicationURL(deviceConfig.Host)
+	}
+
+	tvOptions := []TVOption{
+		WithRenderingControlClient(m.renderingControlClientFactory(upnpLocation)),
+		WithAVTransportClient(m.avTransportClientFactory(upnpLocation)),
+		WithDIALClient(m.dialClientFactory(dialApplicationURL)),
+		WithClock(m.clock),
+	}
+
+	var httpClient HTTPAPIClient
+	var websocketClient WebsocketAPIClient
+
+	switch deviceConfig.Protocol {
+	case tizenapi.LegacyProtocol:
+		httpClient = m.legacyHTTPClientFactory(upnpLocation, httpClientOptions...)
+		websocketClient = m.legacyClientFactory(
+			deviceConfig.Host,
+			deviceConfig.WebsocketAPI.ClientID,
+			tizenapi.WithLegacyPort(deviceConfig.WebsocketAPI.Port),
+			tizenapi.WithLegacyReadTimeout(deviceConfig.WebsocketAPI.ReadTimeout),
+			tizenapi.WithLegacyWriteTimeout(deviceConfig.WebsocketAPI.WriteTimeout),
+		)
+	case tizenapi.EncryptedProtocol:
+		// A key which does not decode leaves the session empty, the TV pairs again.
+		sessionKey, _ := hex.DecodeString(deviceConfig.WebsocketAPI.SessionKey)
+
+		httpClient = m.legacyHTTPClientFactory(upnpLocation, httpClientOptions...)
+		websocketClient = m.encryptedClientFactory(
+			deviceConfig.Host,
+			deviceConfig.WebsocketAPI.ClientID,
+			tizenapi.WithEncryptedPort(deviceConfig.WebsocketAPI.Port),
+			tizenapi.WithEncryptedReadTimeout(deviceConfig.WebsocketAPI.ReadTimeout),
+			tizenapi.WithEncryptedWriteTimeout(deviceConfig.WebsocketAPI.WriteTimeout),
+			tizenapi.WithEncryptedSession(tizenapi.PairingSession{
+				ID:  deviceConfig.WebsocketAPI.SessionID,
+				Key: sessionKey,
+			}),
+			tizenapi.WithEncryptedPairingOptions(tizenapi.WithPairingCrypto(m.pairingCrypto)),
+		)
+	default:
+		httpClient = m.httpClientFactory(deviceConfig.Host, httpClientOptions...)
+		websocketClient = m.websocketClientFactory(
+			deviceConfig.Host,
+			deviceConfig.WebsocketAPI.ClientID,
+			websocketClientOptions...,
+		)
+		tvOptions = append(tvOptions, WithArtAPIClient(
+			m.artClientFactory(deviceConfig.Host, deviceConfig.WebsocketAPI.ClientID, websocketClientOptions...),
+		))
 	}
 
 	tv := NewTV(
 		m.udpClientFactory(deviceConfig.MAC, udpClientOptions...),
-		m.httpClientFactory(deviceConfig.Host, httpClientOptions...),
-		m.websocketClientFactory(deviceConfig.Host, deviceConfig.WebsocketAPI.ClientID, websocketClientOptions...),
+		httpClient,
+		websocketClient,
 		deviceConfig.WebsocketAPI.Token,
+		tvOptions...,
 	)
 
+	// Only the clients of TVs which pair with a PIN have a session to store.
+	if client, ok := websocketClient.(pairingClient); ok {
+		client.OnSession(func(session tizenapi.PairingSession) error {
+			config, err := m.loadConfig()
+			if err != nil {
+				return err
+			}
+
+			storedConfig, exists := config.DeviceConfig(deviceConfig.ID)
+			if !exists {
+				return fmt.Errorf("configuration for device %s is not provided", deviceConfig.ID)
+			}
+
+			storedConfig.WebsocketAPI.SessionID = session.ID
+			storedConfig.WebsocketAPI.SessionKey = hex.EncodeToString(session.Key)
+
+			config.SetDeviceConfig(storedConfig)
+
+			return m.storeConfig(config)
+		})
+	}
+
 	tv.OnAuthorize(func(token string) error {
 		config, err := m.loadConfig()
 		if err != nil {
 			return err
 		}
 
-		deviceConfig, exists := config.DeviceConfig(deviceConfig.ID)
+		storedConfig, exists := config.DeviceConfig(deviceConfig.ID)
 		if !exists {
-			return errors.New(fmt.Sprintf("configuration for device %s is not provided", deviceConfig.ID))
+			return fmt.Errorf("configuration for device %s is not provided", deviceConfig.ID)
 		}
 
-		deviceConfig.WebsocketAPI.Token = token
+		storedConfig.WebsocketAPI.Token = token
 
-		config.SetDeviceConfig(deviceConfig)
+		config.SetDeviceConfig(storedConfig)
 
 		return m.storeConfig(config)
 	})
@@ -333,6 +750,8 @@ func buildDeviceConfig(
 	udpClient UDPAPIClient,
 	httpClient HTTPAPIClient,
 	websocketClient WebsocketAPIClient,
+	renderingControlClient RenderingControlClient,
+	dialClient DIALClient,
 ) DeviceConfig {
 	deviceConfig := DeviceConfig{
 		ID:   device.ID(),
@@ -344,6 +763,22 @@ func buildDeviceConfig(
 	deviceConfig.UDPAPI.Subnet = udpClient.Subnet()
 	deviceConfig.UDPAPI.Port = udpClient.Port()
 
+	if client, ok := udpClient.(interface{ MACs() []string }); ok {
+		for _, mac := range client.MACs() {
+			if !strings.EqualFold(mac, deviceConfig.MAC) {
+				deviceConfig.MACs = appendUnique(deviceConfig.MACs, mac)
+			}
+		}
+	}
+
+	if client, ok := udpClient.(interface {
+		SecureOn() string
+		IPv6() bool
+	}); ok {
+		deviceConfig.UDPAPI.SecureOn = client.SecureOn()
+		deviceConfig.UDPAPI.IPv6 = client.IPv6()
+	}
+
 	deviceConfig.HTTPAPI.Port = httpClient.Port()
 	deviceConfig.HTTPAPI.DialTimeout = httpClient.DialTimeout()
 	deviceConfig.HTTPAPI.RequestTimeout = httpClient.RequestTimeout()
@@ -356,5 +791,55 @@ func buildDeviceConfig(
 	deviceConfig.WebsocketAPI.WriteTimeout = websocketClient.WriteTimeout()
 	deviceConfig.WebsocketAPI.ClientID = websocketClient.ClientID()
 
+	if client, ok := websocketClient.(interface{ Protocol() string }); ok {
+		deviceConfig.Protocol = client.Protocol()
+	}
+
+	if client, ok := websocketClient.(interface {
+		Session() tizenapi.PairingSession
+	}); ok {
+		session := client.Session()
+		deviceConfig.WebsocketAPI.SessionID = session.ID
+		deviceConfig.WebsocketAPI.SessionKey = hex.EncodeToString(session.Key)
+	}
+
+	if renderingControlClient != nil {
+		deviceConfig.UPnP.Location = renderingControlClient.Location()
+	}
+
+	if dialClient != nil {
+		deviceConfig.DIAL.ApplicationURL = dialClient.ApplicationURL()
+	}
+
 	return deviceConfig
 }
+
+func defaultMediaRendererLocation(host string) string {
+	u := url.URL{
+		Scheme: "http",
+		Host:   net.JoinHostPort(host, defaultUPnPPort),
+		Path:   defaultUPnPPath,
+	}
+
+	return u.String()
+}
+
+func defaultDIALApplicationURL(host string) string {
+	u := url.URL{
+		Scheme: "http",
+		Host:   net.JoinHostPort(host, defaultDIALPort),
+		Path:   defaultDIALPath,
+	}
+
+	return u.String()
+}
+
+func appendUnique(values []string, value string) []string {
+	for _, v := range values {
+		if v == value {
+			return values
+		}
+	}
+
+	return append(values, value)
+}
@@ -5,13 +5,24 @@ import (
 )
 
 type DeviceConfig struct {
-	ID     string `json:"id" yaml:"id"`
-	Name   string `json:"name" yaml:"name"`
-	Host   string `json:"host" yaml:"host"`
-	MAC    string `json:"mac" yaml:"mac"`
-	UDPAPI struct {
-		Subnet string `json:"subnet" yaml:"subnet"`
-		Port   string `json:"port" yaml:"port"`
+	ID   string `json:"id" yaml:"id"`
+	Name string `json:"name" yaml:"name"`
+	Host string `json:"host" yaml:"host"`
+	MAC  string `json:"mac" yaml:"mac"`
+	// MACs are other MAC addresses of the TV to wake, e.g. the wired one.
+	MACs []string `json:"macs,omitempty" yaml:"macs,omitempty"`
+	// Protocol is empty for Tizen TVs, "encrypted" for 2014-2015 TVs which pair
+	// with a PIN and "legacy" for older ones.
+	Protocol string `json:"protocol,omitempty" yaml:"protocol,omitempty"`
+	UDPAPI   struct {
+		Subnet   string `json:"subnet" yaml:"subnet"`
+		Port     string `json:"port" yaml:"port"`
+		SecureOn string `json:"secure_on,omitempty" yaml:"secure_on,omitempty"`
+		IPv6     bool   `json:"ipv6,omitempty" yaml:"ipv6,omitempty"`
+		// Relay is the address of a Wake-on-LAN relay, "http://host:port" or
+		// "udp://host:port", for TVs on another network.
+		Relay       string `json:"relay,omitempty" yaml:"relay,omitempty"`
+		RelaySecret string `json:"relay_secret,omitempty" yaml:"relay_secret,omitempty"`
 	} `json:"udp_api" yaml:"udp_api"`
 	HTTPAPI struct {
 		Port            string        `json:"port" yaml:"port"`
@@ -27,7 +38,16 @@ type DeviceConfig struct {
 		WriteTimeout time.Duration `json:"write_timeout" yaml:"write_timeout"`
 		ClientID     string        `json:"client_id" yaml:"client_id"`
 		Token        string        `json:"token" yaml:"token"`
+		// Session of a PIN pairing, only used by the encrypted protocol.
+		SessionID  string `json:"session_id,omitempty" yaml:"session_id,omitempty"`
+		SessionKey string `json:"session_key,omitempty" yaml:"session_key,omitempty"`
 	} `json:"websocket_api" yaml:"websocket_api"`
+	UPnP struct {
+		Location string `json:"location" yaml:"location"`
+	} `json:"upnp" yaml:"upnp"`
+	DIAL struct {
+		ApplicationURL string `json:"application_url" yaml:"application_url"`
+	} `json:"dial" yaml:"dial"`
 }
 
 type TVManagerConfig struct {
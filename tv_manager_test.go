@@ -0,0 +1,382 @@
+package samsung_test
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	samsung "github.com/kpeu3i/go-tizen-tv"
+	"github.com/kpeu3i/go-tizen-tv/tizenapi"
+	"github.com/kpeu3i/go-tizen-tv/tizentest"
+)
+
+const testDeviceID = "uuid:0e5f6c4a-0000-4000-8000-74657374746f"
+
+// memoryConfigStorage keeps the config in memory instead of a file.
+type memoryConfigStorage struct {
+	mu     sync.Mutex
+	config samsung.TVManagerConfig
+}
+
+func (s *memoryConfigStorage) Load() (samsung.TVManagerConfig, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.config, nil
+}
+
+func (s *memoryConfigStorage) Store(config samsung.TVManagerConfig) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.config = config
+
+	return nil
+}
+
+func newEmulatorDeviceConfig(emulator *tizentest.Emulator) samsung.DeviceConfig {
+	deviceConfig := samsung.DeviceConfig{
+		ID:   testDeviceID,
+		Name: "tizentest",
+		Host: emulator.Host(),
+		MAC:  emulator.MAC(),
+	}
+
+	deviceConfig.HTTPAPI.Port = emulator.Port()
+	deviceConfig.HTTPAPI.DialTimeout = time.Second
+	deviceConfig.HTTPAPI.RequestTimeout = 2 * time.Second
+	deviceConfig.HTTPAPI.ResponseTimeout = 2 * time.Second
+
+	deviceConfig.WebsocketAPI.Port = emulator.Port()
+	deviceConfig.WebsocketAPI.DialTimeout = time.Second
+	deviceConfig.WebsocketAPI.ReadTimeout = 2 * time.Second
+	deviceConfig.WebsocketAPI.WriteTimeout = 2 * time.Second
+	deviceConfig.WebsocketAPI.ClientID = "tizentest"
+
+	return deviceConfig
+}
+
+func TestTVManagerStoresIssuedToken(t *testing.T) {
+	emulator := tizentest.NewEmulator()
+
+	err := emulator.Start()
+	if err != nil {
+		t.Fatalf("start emulator: %v", err)
+	}
+
+	defer func() {
+		_ = emulator.Close()
+	}()
+
+	storage := &memoryConfigStorage{}
+	storage.config.SetDeviceConfig(newEmulatorDeviceConfig(emulator))
+
+	manager := samsung.NewTVManager(samsung.WithTVManagerConfigStorage(storage))
+
+	tv, err := manager.LoadByID(testDeviceID)
+	if err != nil {
+		t.Fatalf("LoadByID() = %v", err)
+	}
+
+	defer func() {
+		_ = tv.Close()
+	}()
+
+	err = tv.Connect()
+	if err != nil {
+		t.Fatalf("Connect() = %v", err)
+	}
+
+	deviceConfig, _ := storage.config.DeviceConfig(testDeviceID)
+	if deviceConfig.WebsocketAPI.Token == "" || deviceConfig.WebsocketAPI.Token != emulator.Token() {
+		t.Errorf("stored token = %q, want %q", deviceConfig.WebsocketAPI.Token, emulator.Token())
+	}
+}
+
+func TestTVManagerAuthorizeWithoutDeviceConfig(t *testing.T) {
+	emulator := tizentest.NewEmulator()
+
+	err := emulator.Start()
+	if err != nil {
+		t.Fatalf("start emulator: %v", err)
+	}
+
+	defer func() {
+		_ = emulator.Close()
+	}()
+
+	storage := &memoryConfigStorage{}
+	storage.config.SetDeviceConfig(newEmulatorDeviceConfig(emulator))
+
+	manager := samsung.NewTVManager(samsung.WithTVManagerConfigStorage(storage))
+
+	tv, err := manager.LoadByID(testDeviceID)
+	if err != nil {
+		t.Fatalf("LoadByID() = %v", err)
+	}
+
+	defer func() {
+		_ = tv.Close()
+	}()
+
+	// The device is forgotten while the TV is in use.
+	_ = storage.Store(samsung.TVManagerConfig{})
+
+	err = tv.Connect()
+	if err == nil || !strings.Contains(err.Error(), testDeviceID) {
+		t.Errorf("Connect() = %v, want an error naming %s", err, testDeviceID)
+	}
+}
+
+func TestTVManagerStoresPairedSession(t *testing.T) {
+	server := tizentest.NewPairingServer()
+
+	err := server.Start()
+	if err != nil {
+		t.Fatalf("start pairing server: %v", err)
+	}
+
+	defer func() {
+		_ = server.Close()
+	}()
+
+	deviceConfig := samsung.DeviceConfig{ID: testDeviceID, Name: "tizentest", Host: server.Host()}
+	deviceConfig.Protocol = tizenapi.EncryptedProtocol
+	deviceConfig.WebsocketAPI.Port = server.Port()
+	deviceConfig.WebsocketAPI.ReadTimeout = 2 * time.Second
+	deviceConfig.WebsocketAPI.WriteTimeout = 2 * time.Second
+	deviceConfig.WebsocketAPI.ClientID = "tizentest"
+
+	storage := &memoryConfigStorage{}
+	storage.config.SetDeviceConfig(deviceConfig)
+
+	manager := samsung.NewTVManager(
+		samsung.WithTVManagerConfigStorage(storage),
+		samsung.WithTVManagerPairingCrypto(tizentest.PairingCrypto{}),
+		// The fake TV serves the pairing on the port of the keys.
+		samsung.WithTVManagerEncryptedAPIClientFactory(func(
+			host string,
+			clientID string,
+			options ...tizenapi.EncryptedAPIOption,
+		) samsung.WebsocketAPIClient {
+			options = append(options, tizenapi.WithEncryptedPairingOptions(tizenapi.WithPairingPort(server.Port())))
+
+			return tizenapi.NewEncryptedAPIClient(host, clientID, options...)
+		}),
+	)
+
+	tv, err := manager.LoadByID(testDeviceID)
+	if err != nil {
+		t.Fatalf("LoadByID() = %v", err)
+	}
+
+	defer func() {
+		_ = tv.Close()
+	}()
+
+	err = tv.OnPINRequest(func(ctx context.Context) (string, error) {
+		return server.PIN, nil
+	})
+	if err != nil {
+		t.Fatalf("OnPINRequest() = %v", err)
+	}
+
+	err = tv.Connect()
+	if err != nil {
+		t.Fatalf("Connect() = %v", err)
+	}
+
+	storedConfig, _ := storage.config.DeviceConfig(testDeviceID)
+	if storedConfig.WebsocketAPI.SessionID != server.SessionID || storedConfig.WebsocketAPI.SessionKey == "" {
+		t.Errorf(
+			"stored session = %q/%q, want %q with a key",
+			storedConfig.WebsocketAPI.SessionID,
+			storedConfig.WebsocketAPI.SessionKey,
+			server.SessionID,
+		)
+	}
+
+	// The stored session is used without asking for the PIN again.
+	tv, err = manager.LoadByID(testDeviceID)
+	if err != nil {
+		t.Fatalf("LoadByID() = %v", err)
+	}
+
+	defer func() {
+		_ = tv.Close()
+	}()
+
+	err = tv.Connect()
+	if err != nil {
+		t.Fatalf("Connect() with the stored session = %v", err)
+	}
+
+	err = tv.ClickKey("KEY_MUTE")
+	if err != nil {
+		t.Fatalf("ClickKey() = %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(server.Keys()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	keys := server.Keys()
+	if len(keys) != 1 || keys[0].Key != "KEY_MUTE" {
+		t.Errorf("Keys() = %+v, want KEY_MUTE", keys)
+	}
+}
+
+func TestTVManagerPairingNotSupportedByTizenTV(t *testing.T) {
+	emulator := tizentest.NewEmulator()
+
+	err := emulator.Start()
+	if err != nil {
+		t.Fatalf("start emulator: %v", err)
+	}
+
+	defer func() {
+		_ = emulator.Close()
+	}()
+
+	storage := &memoryConfigStorage{}
+	storage.config.SetDeviceConfig(newEmulatorDeviceConfig(emulator))
+
+	manager := samsung.NewTVManager(samsung.WithTVManagerConfigStorage(storage))
+
+	tv, err := manager.LoadByID(testDeviceID)
+	if err != nil {
+		t.Fatalf("LoadByID() = %v", err)
+	}
+
+	defer func() {
+		_ = tv.Close()
+	}()
+
+	err = tv.OnPair(func(sessionID string, sessionKey []byte) error {
+		return nil
+	})
+	if err != samsung.ErrPairingNotSupported {
+		t.Errorf("OnPair() = %v, want %v", err, samsung.ErrPairingNotSupported)
+	}
+}
+
+func TestTVManagerStoreKeepsSettingsOfRediscoveredTV(t *testing.T) {
+	emulator := tizentest.NewEmulator()
+
+	err := emulator.Start()
+	if err != nil {
+		t.Fatalf("start emulator: %v", err)
+	}
+
+	defer func() {
+		_ = emulator.Close()
+	}()
+
+	existingDeviceConfig := newEmulatorDeviceConfig(emulator)
+	existingDeviceConfig.Name = "Living room"
+	existingDeviceConfig.WebsocketAPI.Token = "stored-token"
+	existingDeviceConfig.UDPAPI.SecureOn = "01:02:03:04:05:06"
+	existingDeviceConfig.UDPAPI.IPv6 = true
+	existingDeviceConfig.UDPAPI.Relay = "udp://192.0.2.1:9999"
+	existingDeviceConfig.UDPAPI.RelaySecret = "secret"
+
+	storage := &memoryConfigStorage{}
+	storage.config.SetDeviceConfig(existingDeviceConfig)
+
+	manager := samsung.NewTVManager(samsung.WithTVManagerConfigStorage(storage))
+
+	// A discovered TV has clients with default settings.
+	tv := newEmulatorTV(emulator)
+	defer func() {
+		_ = tv.Close()
+	}()
+
+	err = manager.Store(tv)
+	if err != nil {
+		t.Fatalf("Store() = %v", err)
+	}
+
+	storedConfig, ok := storage.config.DeviceConfig(testDeviceID)
+	if !ok {
+		t.Fatalf("device %s is not stored", testDeviceID)
+	}
+
+	if storedConfig.Name != existingDeviceConfig.Name ||
+		storedConfig.WebsocketAPI.Token != existingDeviceConfig.WebsocketAPI.Token ||
+		storedConfig.UDPAPI.SecureOn != existingDeviceConfig.UDPAPI.SecureOn ||
+		storedConfig.UDPAPI.IPv6 != existingDeviceConfig.UDPAPI.IPv6 ||
+		storedConfig.UDPAPI.Relay != existingDeviceConfig.UDPAPI.Relay ||
+		storedConfig.UDPAPI.RelaySecret != existingDeviceConfig.UDPAPI.RelaySecret {
+		t.Errorf("stored config = %+v, want the settings of %+v", storedConfig, existingDeviceConfig)
+	}
+
+	if !strings.EqualFold(storedConfig.MAC, emulator.MAC()) {
+		t.Errorf("stored MAC = %q, want %q", storedConfig.MAC, emulator.MAC())
+	}
+}
+
+func TestTVManagerStoreKeepsMACOfLegacyTV(t *testing.T) {
+	emulator := tizentest.NewEmulator()
+
+	err := emulator.Start()
+	if err != nil {
+		t.Fatalf("start emulator: %v", err)
+	}
+
+	defer func() {
+		_ = emulator.Close()
+	}()
+
+	// Legacy TVs are identified by the UDN of their description.
+	legacyDeviceID := strings.TrimPrefix(testDeviceID, "uuid:")
+	location := "http://" + net.JoinHostPort(emulator.Host(), emulator.Port()) + "/dmr"
+
+	tests := []struct {
+		name    string
+		udpMAC  string
+		wantMAC string
+	}{
+		{name: "discovered MAC", udpMAC: "aa:bb:cc:dd:ee:ff", wantMAC: "aa:bb:cc:dd:ee:ff"},
+		{name: "stored MAC", udpMAC: "", wantMAC: emulator.MAC()},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			existingDeviceConfig := samsung.DeviceConfig{ID: legacyDeviceID, Host: emulator.Host(), MAC: emulator.MAC()}
+			existingDeviceConfig.Protocol = tizenapi.LegacyProtocol
+
+			storage := &memoryConfigStorage{}
+			storage.config.SetDeviceConfig(existingDeviceConfig)
+
+			manager := samsung.NewTVManager(samsung.WithTVManagerConfigStorage(storage))
+
+			tv := samsung.NewTV(
+				tizenapi.NewUDPAPIClient(test.udpMAC),
+				tizenapi.NewLegacyHTTPAPIClient(location),
+				tizenapi.NewLegacyAPIClient(emulator.Host(), "tizentest"),
+				"",
+			)
+			defer func() {
+				_ = tv.Close()
+			}()
+
+			err := manager.Store(tv)
+			if err != nil {
+				t.Fatalf("Store() = %v", err)
+			}
+
+			storedConfig, ok := storage.config.DeviceConfig(legacyDeviceID)
+			if !ok {
+				t.Fatalf("device %s is not stored", legacyDeviceID)
+			}
+
+			if !strings.EqualFold(storedConfig.MAC, test.wantMAC) {
+				t.Errorf("stored MAC = %q, want %q", storedConfig.MAC, test.wantMAC)
+			}
+		})
+	}
+}
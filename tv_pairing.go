@@ -0,0 +1,49 @@
+package samsung
+
+import (
+	"context"
+	"errors"
+
+	"github.com/kpeu3i/go-tizen-tv/tizenapi"
+)
+
+// PINRequestHandler returns the PIN shown on the screen of a TV which requires
+// PIN pairing (2014-2015 H/J series).
+type PINRequestHandler func(ctx context.Context) (string, error)
+
+// PairHandler is called with the credentials of a new PIN pairing, so that
+// they can be persisted and reused.
+type PairHandler func(sessionID string, sessionKey []byte) error
+
+type pairingClient interface {
+	OnPINRequest(handler tizenapi.PINHandler)
+	OnSession(handler tizenapi.PairingSessionHandler)
+}
+
+var ErrPairingNotSupported = errors.New("TV does not use PIN pairing")
+
+// OnPINRequest sets the handler asked for the PIN when the TV pairs. Only TVs
+// using the encrypted protocol ask for one.
+func (tv *TV) OnPINRequest(handler PINRequestHandler) error {
+	client, ok := tv.websocketClient.(pairingClient)
+	if !ok {
+		return ErrPairingNotSupported
+	}
+
+	client.OnPINRequest(tizenapi.PINHandler(handler))
+
+	return nil
+}
+
+func (tv *TV) OnPair(handler PairHandler) error {
+	client, ok := tv.websocketClient.(pairingClient)
+	if !ok {
+		return ErrPairingNotSupported
+	}
+
+	client.OnSession(func(session tizenapi.PairingSession) error {
+		return handler(session.ID, session.Key)
+	})
+
+	return nil
+}
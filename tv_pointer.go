@@ -0,0 +1,104 @@
+package samsung
+
+import (
+	"context"
+	"time"
+
+	"github.com/kpeu3i/go-tizen-tv/tizenapi"
+)
+
+const (
+	defaultSwipeStepInterval = 20 * time.Millisecond
+	isSupportTouchPad        = "remote_touchPad"
+)
+
+func (tv *TV) SupportsTouchPad() (bool, error) {
+	return tv.SupportsTouchPadContext(context.Background())
+}
+
+func (tv *TV) SupportsTouchPadContext(ctx context.Context) (bool, error) {
+	info, err := tv.InfoContext(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	return info.IsSupport[isSupportTouchPad] == "true", nil
+}
+
+func (tv *TV) MoveCursor(dx, dy int) error {
+	return tv.MoveCursorContext(context.Background(), dx, dy)
+}
+
+func (tv *TV) MoveCursorContext(ctx context.Context, dx, dy int) error {
+	err := tv.ensureWebsocketConnection(ctx)
+	if err != nil {
+		return err
+	}
+
+	return tv.websocketClient.SendMouseMoveContext(ctx, dx, dy)
+}
+
+func (tv *TV) LeftClick() error {
+	return tv.LeftClickContext(context.Background())
+}
+
+func (tv *TV) LeftClickContext(ctx context.Context) error {
+	return tv.click(ctx, tizenapi.WebsocketMouseButtonLeft)
+}
+
+func (tv *TV) RightClick() error {
+	return tv.RightClickContext(context.Background())
+}
+
+func (tv *TV) RightClickContext(ctx context.Context) error {
+	return tv.click(ctx, tizenapi.WebsocketMouseButtonRight)
+}
+
+// Swipe moves the cursor by dx, dy over the given duration, split into small
+// relative moves so the TV renders it as a continuous gesture.
+func (tv *TV) Swipe(dx, dy int, duration time.Duration) error {
+	return tv.SwipeContext(context.Background(), dx, dy, duration)
+}
+
+func (tv *TV) SwipeContext(ctx context.Context, dx, dy int, duration time.Duration) error {
+	err := tv.ensureWebsocketConnection(ctx)
+	if err != nil {
+		return err
+	}
+
+	steps := int(duration / defaultSwipeStepInterval)
+	if steps < 1 {
+		steps = 1
+	}
+
+	movedX, movedY := 0, 0
+	for i := 1; i <= steps; i++ {
+		x := dx * i / steps
+		y := dy * i / steps
+
+		err := tv.websocketClient.SendMouseMoveContext(ctx, x-movedX, y-movedY)
+		if err != nil {
+			return err
+		}
+
+		movedX, movedY = x, y
+
+		if i < steps {
+			err = tv.sleep(ctx, defaultSwipeStepInterval)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (tv *TV) click(ctx context.Context, button tizenapi.WebsocketMouseButton) error {
+	err := tv.ensureWebsocketConnection(ctx)
+	if err != nil {
+		return err
+	}
+
+	return tv.websocketClient.SendMouseClickContext(ctx, button)
+}
@@ -0,0 +1,108 @@
+package samsung
+
+import (
+	"context"
+
+	"github.com/kpeu3i/go-tizen-tv/tizenapi"
+)
+
+type PowerState string
+
+const (
+	PowerStateOn      PowerState = "on"
+	PowerStateStandby PowerState = "standby"
+	PowerStateOff     PowerState = "off" // The TV is off or unreachable
+)
+
+func (tv *TV) PowerState() (PowerState, error) {
+	return tv.PowerStateContext(context.Background())
+}
+
+// PowerStateContext returns the power state reported by the TV. TVs which do
+// not report one, like many 2016-2018 models, are considered on while their
+// remote control API accepts connections and in standby otherwise. An error is
+// only returned when the context is done.
+func (tv *TV) PowerStateContext(ctx context.Context) (PowerState, error) {
+	if !tv.httpClient.IsAvailableContext(ctx) {
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+
+		return PowerStateOff, nil
+	}
+
+	info, err := tv.httpClient.GetInfoContext(ctx)
+	if err != nil {
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+
+		return PowerStateOff, nil
+	}
+
+	switch TVDevice(info.Device).PowerState() {
+	case string(PowerStateStandby):
+		return PowerStateStandby, nil
+	case "":
+		// The HTTP API of these TVs answers in standby as well.
+		if tv.websocketClient.IsAvailableContext(ctx) {
+			return PowerStateOn, nil
+		}
+
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+
+		return PowerStateStandby, nil
+	default:
+		return PowerStateOn, nil
+	}
+}
+
+// SetPower powers the TV on or off. Nothing is sent when the TV is already in
+// the requested state, otherwise the call returns once the transition is
+// confirmed.
+func (tv *TV) SetPower(on bool) error {
+	return tv.SetPowerContext(context.Background(), on)
+}
+
+func (tv *TV) SetPowerContext(ctx context.Context, on bool) error {
+	if on {
+		return tv.PowerOnContext(ctx)
+	}
+
+	return tv.PowerOffContext(ctx)
+}
+
+func (tv *TV) powerOffKey() Key {
+	if tv.keyPowerOff != "" {
+		return tv.keyPowerOff
+	}
+
+	// Pre-Tizen TVs have a discrete power off key, on Tizen ones it is ignored
+	// and the toggle is sent after checking the state. See PowerOff for TVs
+	// whose state cannot be told reliably.
+	switch tv.protocol() {
+	case tizenapi.LegacyProtocol, tizenapi.EncryptedProtocol:
+		return KEY_POWEROFF
+	default:
+		return KEY_POWER
+	}
+}
+
+func (tv *TV) powerOnKey() Key {
+	if tv.keyPowerOn != "" {
+		return tv.keyPowerOn
+	}
+
+	return KEY_POWER
+}
+
+func (tv *TV) protocol() string {
+	client, ok := tv.websocketClient.(interface{ Protocol() string })
+	if !ok {
+		return ""
+	}
+
+	return client.Protocol()
+}
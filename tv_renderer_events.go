@@ -0,0 +1,103 @@
+package samsung
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/kpeu3i/go-tizen-tv/upnp"
+)
+
+// RendererEvent reports state changes of the UPnP media renderer, including
+// the ones made with the physical remote. Nil and empty fields are unchanged.
+type RendererEvent struct {
+	Volume        *int
+	Mute          *bool
+	PlayerState   PlayerState
+	TrackURL      string
+	TrackDuration time.Duration
+}
+
+type RendererEventHandler func(event RendererEvent)
+
+var errEventsNotConfigured = errors.New("UPnP media renderer location is not configured")
+
+// SubscribeRendererEvents subscribes to RenderingControl and AVTransport
+// events. The returned function cancels both subscriptions.
+func (tv *TV) SubscribeRendererEvents(handler RendererEventHandler) (func() error, error) {
+	return tv.SubscribeRendererEventsContext(context.Background(), handler)
+}
+
+func (tv *TV) SubscribeRendererEventsContext(
+	ctx context.Context,
+	handler RendererEventHandler,
+) (func() error, error) {
+	if tv.renderingControlClient == nil {
+		return nil, errEventsNotConfigured
+	}
+
+	location := tv.renderingControlClient.Location()
+
+	tv.mu.Lock()
+	if tv.eventSubscriber == nil {
+		tv.eventSubscriber = upnp.NewSubscriber(upnp.WithSubscriberClock(tv.clock))
+	}
+	subscriber := tv.eventSubscriber
+	tv.mu.Unlock()
+
+	renderingControl, err := subscriber.Subscribe(
+		ctx,
+		location,
+		upnp.ServiceTypeRenderingControl,
+		func(event upnp.Event) {
+			for _, change := range event.Changes {
+				rc := change.RenderingControl()
+				if rc.Volume == nil && rc.Mute == nil {
+					continue
+				}
+
+				handler(RendererEvent{Volume: rc.Volume, Mute: rc.Mute})
+			}
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	avTransport, err := subscriber.Subscribe(
+		ctx,
+		location,
+		upnp.ServiceTypeAVTransport,
+		func(event upnp.Event) {
+			for _, change := range event.Changes {
+				avt := change.AVTransport()
+				if avt.TransportState == "" && avt.CurrentTrackURI == "" && avt.CurrentTrackDuration == 0 {
+					continue
+				}
+
+				handler(RendererEvent{
+					PlayerState:   PlayerState(avt.TransportState),
+					TrackURL:      avt.CurrentTrackURI,
+					TrackDuration: avt.CurrentTrackDuration,
+				})
+			}
+		},
+	)
+	if err != nil {
+		_ = renderingControl.UnsubscribeContext(ctx)
+
+		return nil, err
+	}
+
+	unsubscribe := func() error {
+		err := renderingControl.Unsubscribe()
+		avTransportErr := avTransport.Unsubscribe()
+		if err != nil {
+			return err
+		}
+
+		return avTransportErr
+	}
+
+	return unsubscribe, nil
+}
@@ -0,0 +1,374 @@
+package samsung_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	samsung "github.com/kpeu3i/go-tizen-tv"
+	"github.com/kpeu3i/go-tizen-tv/clock"
+	"github.com/kpeu3i/go-tizen-tv/mediaserver"
+	"github.com/kpeu3i/go-tizen-tv/tizenapi"
+	"github.com/kpeu3i/go-tizen-tv/tizentest"
+	"github.com/kpeu3i/go-tizen-tv/upnp"
+)
+
+// startEmulator starts an emulator and returns a TV talking to it.
+func startEmulator(t *testing.T, options ...tizentest.EmulatorOption) (*tizentest.Emulator, *samsung.TV, func()) {
+	t.Helper()
+
+	emulator := tizentest.NewEmulator(options...)
+
+	err := emulator.Start()
+	if err != nil {
+		t.Fatalf("start emulator: %v", err)
+	}
+
+	tv := newEmulatorTV(emulator)
+
+	stop := func() {
+		_ = tv.Close()
+		_ = emulator.Close()
+	}
+
+	return emulator, tv, stop
+}
+
+// newEmulatorTV returns a TV talking to the emulator which reconnects quickly.
+func newEmulatorTV(emulator *tizentest.Emulator, options ...samsung.TVOption) *samsung.TV {
+	websocketClient := tizenapi.NewWebsocketAPIClient(
+		emulator.Host(),
+		"tizentest",
+		tizenapi.WithWebsocketPort(emulator.Port()),
+		tizenapi.WithWebsocketReadTimeout(2*time.Second),
+		tizenapi.WithWebsocketReconnectBackoff(tizenapi.WebsocketBackoff{
+			InitialDelay: 10 * time.Millisecond,
+			MaxDelay:     100 * time.Millisecond,
+			Multiplier:   2,
+			MaxAttempts:  10,
+		}),
+	)
+
+	return samsung.NewTV(
+		tizenapi.NewUDPAPIClient(emulator.MAC()),
+		tizenapi.NewHTTPAPIClient(emulator.Host(), tizenapi.WithHTTPPort(emulator.Port())),
+		websocketClient,
+		emulator.Token(),
+		options...,
+	)
+}
+
+func TestTVConnectPairing(t *testing.T) {
+	tests := []struct {
+		mode    tizentest.PairingMode
+		wantErr string
+	}{
+		{mode: tizentest.PairingAccept},
+		{mode: tizentest.PairingDeny, wantErr: "unauthorized"},
+		{mode: tizentest.PairingTimeout, wantErr: "timeout"},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.mode), func(t *testing.T) {
+			emulator, tv, stop := startEmulator(t, tizentest.WithEmulatorPairingMode(test.mode))
+			defer stop()
+
+			var token string
+			tv.OnAuthorize(func(issued string) error {
+				token = issued
+
+				return nil
+			})
+
+			err := tv.Connect()
+			if test.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Connect() = %v", err)
+				}
+
+				if token == "" || token != emulator.Token() {
+					t.Errorf("authorized token = %q, want %q", token, emulator.Token())
+				}
+
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+				t.Fatalf("Connect() = %v, want an error containing %q", err, test.wantErr)
+			}
+
+			if token != "" {
+				t.Errorf("authorized token = %q, want none", token)
+			}
+		})
+	}
+}
+
+// failingSubscriber fails to close after unsubscribing everything.
+type failingSubscriber struct {
+	*upnp.Subscriber
+}
+
+func (s failingSubscriber) CloseContext(ctx context.Context) error {
+	_ = s.Subscriber.CloseContext(ctx)
+
+	return errors.New("subscriber failed")
+}
+
+// failingMediaServer fails to close after stopping serving.
+type failingMediaServer struct {
+	*mediaserver.Server
+	closed bool
+}
+
+func (s *failingMediaServer) CloseContext(ctx context.Context) error {
+	_ = s.Server.CloseContext(ctx)
+	s.closed = true
+
+	return errors.New("media server failed")
+}
+
+func TestTVCloseClosesEverything(t *testing.T) {
+	emulator := tizentest.NewEmulator()
+
+	err := emulator.Start()
+	if err != nil {
+		t.Fatalf("start emulator: %v", err)
+	}
+
+	defer func() {
+		_ = emulator.Close()
+	}()
+
+	websocketClient := tizenapi.NewWebsocketAPIClient(
+		emulator.Host(),
+		"tizentest",
+		tizenapi.WithWebsocketPort(emulator.Port()),
+		tizenapi.WithWebsocketReconnect(false),
+	)
+	mediaServer := &failingMediaServer{Server: mediaserver.NewServer()}
+
+	tv := samsung.NewTV(
+		tizenapi.NewUDPAPIClient(emulator.MAC()),
+		tizenapi.NewHTTPAPIClient(emulator.Host(), tizenapi.WithHTTPPort(emulator.Port())),
+		websocketClient,
+		"",
+		samsung.WithEventSubscriber(failingSubscriber{Subscriber: upnp.NewSubscriber()}),
+		samsung.WithMediaServer(mediaServer),
+	)
+
+	err = tv.Connect()
+	if err != nil {
+		t.Fatalf("Connect() = %v", err)
+	}
+
+	err = tv.Close()
+	if err == nil {
+		t.Fatal("Close() = nil, want the close errors")
+	}
+
+	for _, message := range []string{"subscriber failed", "media server failed"} {
+		if !strings.Contains(err.Error(), message) {
+			t.Errorf("Close() = %v, want it to contain %q", err, message)
+		}
+	}
+
+	if !mediaServer.closed {
+		t.Error("media server has not been closed")
+	}
+
+	if websocketClient.IsConnected() {
+		t.Error("websocket connection has not been closed")
+	}
+}
+
+func TestTVInfoHTTPFaults(t *testing.T) {
+	tests := []struct {
+		status int
+	}{
+		{status: http.StatusUnauthorized},
+		{status: http.StatusInternalServerError},
+	}
+
+	for _, test := range tests {
+		t.Run(http.StatusText(test.status), func(t *testing.T) {
+			emulator, tv, stop := startEmulator(t)
+			defer stop()
+
+			emulator.InjectFault(tizentest.Fault{Target: tizentest.FaultTargetInfo, Status: test.status, Times: 1})
+
+			_, err := tv.Info()
+			if err == nil || !strings.Contains(err.Error(), strconv.Itoa(test.status)) {
+				t.Fatalf("Info() = %v, want an error with status %d", err, test.status)
+			}
+
+			// The fault is gone, the TV answers again.
+			info, err := tv.Info()
+			if err != nil || info.Device.ID() != emulator.Device()["id"] {
+				t.Errorf("Info() = %+v, %v", info.Device, err)
+			}
+		})
+	}
+}
+
+func TestTVReconnectsAfterDroppedConnection(t *testing.T) {
+	emulator, tv, stop := startEmulator(t)
+	defer stop()
+
+	reconnects := make(chan samsung.TVReconnect, 8)
+	tv.OnReconnect(func(reconnect samsung.TVReconnect) {
+		reconnects <- reconnect
+	})
+
+	err := tv.Connect()
+	if err != nil {
+		t.Fatalf("Connect() = %v", err)
+	}
+
+	emulator.DropConnections()
+
+	for {
+		select {
+		case reconnect := <-reconnects:
+			if reconnect.GaveUp {
+				t.Fatalf("reconnection gave up: %v", reconnect.Err)
+			}
+
+			if reconnect.Err != nil {
+				continue
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatal("TV has not reconnected")
+		}
+
+		break
+	}
+
+	err = tv.ClickKey(samsung.KEY_HOME)
+	if err != nil {
+		t.Fatalf("ClickKey() = %v", err)
+	}
+
+	// Keys are acknowledged by nothing, the emulator gets them shortly.
+	received := func() bool {
+		keys := emulator.Keys()
+
+		return len(keys) > 0 && keys[len(keys)-1].Key == string(samsung.KEY_HOME)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !received() && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if !received() {
+		t.Errorf("keys = %+v, want %s last", emulator.Keys(), samsung.KEY_HOME)
+	}
+
+	if emulator.Connections() != 1 {
+		t.Errorf("connections = %d, want 1", emulator.Connections())
+	}
+}
+
+func TestTVSendKeysTimesOutOnClock(t *testing.T) {
+	fakeClock := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	emulator := tizentest.NewEmulator()
+
+	err := emulator.Start()
+	if err != nil {
+		t.Fatalf("start emulator: %v", err)
+	}
+
+	defer func() {
+		_ = emulator.Close()
+	}()
+
+	tv := newEmulatorTV(emulator, samsung.WithClock(fakeClock))
+	defer func() {
+		_ = tv.Close()
+	}()
+
+	err = tv.Connect()
+	if err != nil {
+		t.Fatalf("Connect() = %v", err)
+	}
+
+	sequence := samsung.KeySequence{}
+	sequence.Click(samsung.KEY_HOME).Wait(time.Hour).Click(samsung.KEY_MENU)
+
+	ctx, cancel := clock.WithTimeout(context.Background(), fakeClock, 10*time.Second)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- tv.SendKeysContext(ctx, sequence)
+	}()
+
+	// The timeout and the wait after the first key.
+	advanced, err := advanceWhileWaiting(fakeClock, 2, 10*time.Second, done)
+	if err != context.DeadlineExceeded {
+		t.Fatalf("SendKeysContext() = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	if advanced != 10*time.Second {
+		t.Errorf("advanced %s, want 10s", advanced)
+	}
+
+	for _, key := range emulator.Keys() {
+		if key.Key == string(samsung.KEY_MENU) {
+			t.Errorf("%s has been sent after the timeout", key.Key)
+		}
+	}
+}
+
+func TestTVPowerOffTimesOutOnClock(t *testing.T) {
+	fakeClock := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	emulator := tizentest.NewEmulator()
+
+	err := emulator.Start()
+	if err != nil {
+		t.Fatalf("start emulator: %v", err)
+	}
+
+	defer func() {
+		_ = emulator.Close()
+	}()
+
+	tv := newEmulatorTV(emulator, samsung.WithClock(fakeClock), samsung.WithPowerOffTimeout(5*time.Second))
+	defer func() {
+		_ = tv.Close()
+	}()
+
+	err = tv.Connect()
+	if err != nil {
+		t.Fatalf("Connect() = %v", err)
+	}
+
+	// The TV never gets the power key and stays on.
+	emulator.InjectFault(tizentest.Fault{Target: tizentest.FaultTargetMessage, Match: "ms.remote.control", Drop: true})
+
+	done := make(chan error, 1)
+	go func() {
+		done <- tv.PowerOff()
+	}()
+
+	// The timeout and the wait between power state polls.
+	advanced, err := advanceWhileWaiting(fakeClock, 2, time.Second, done)
+	if err == nil || !strings.Contains(err.Error(), "unable to power off") {
+		t.Fatalf("PowerOff() = %v, want a power off error", err)
+	}
+
+	if advanced != 5*time.Second {
+		t.Errorf("advanced %s, want 5s", advanced)
+	}
+
+	if emulator.PowerState() != samsung.PowerStateOn {
+		t.Errorf("power state = %s, want %s", emulator.PowerState(), samsung.PowerStateOn)
+	}
+}
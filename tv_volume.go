@@ -0,0 +1,60 @@
+package samsung
+
+import (
+	"context"
+	"errors"
+)
+
+var errRenderingControlNotConfigured = errors.New("UPnP rendering control is not configured")
+
+func (tv *TV) Volume() (int, error) {
+	return tv.VolumeContext(context.Background())
+}
+
+func (tv *TV) VolumeContext(ctx context.Context) (int, error) {
+	if tv.renderingControlClient == nil {
+		return 0, errRenderingControlNotConfigured
+	}
+
+	return tv.renderingControlClient.GetVolumeContext(ctx)
+}
+
+func (tv *TV) SetVolume(volume int) error {
+	return tv.SetVolumeContext(context.Background(), volume)
+}
+
+func (tv *TV) SetVolumeContext(ctx context.Context, volume int) error {
+	if tv.renderingControlClient == nil {
+		return errRenderingControlNotConfigured
+	}
+
+	if volume < 0 || volume > 100 {
+		return errors.New("volume must be between 0 and 100")
+	}
+
+	return tv.renderingControlClient.SetVolumeContext(ctx, volume)
+}
+
+func (tv *TV) IsMuted() (bool, error) {
+	return tv.IsMutedContext(context.Background())
+}
+
+func (tv *TV) IsMutedContext(ctx context.Context) (bool, error) {
+	if tv.renderingControlClient == nil {
+		return false, errRenderingControlNotConfigured
+	}
+
+	return tv.renderingControlClient.GetMuteContext(ctx)
+}
+
+func (tv *TV) SetMute(mute bool) error {
+	return tv.SetMuteContext(context.Background(), mute)
+}
+
+func (tv *TV) SetMuteContext(ctx context.Context, mute bool) error {
+	if tv.renderingControlClient == nil {
+		return errRenderingControlNotConfigured
+	}
+
+	return tv.renderingControlClient.SetMuteContext(ctx, mute)
+}
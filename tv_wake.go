@@ -0,0 +1,307 @@
+package samsung
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	defaultWakeInterval     = 3 * time.Second
+	defaultWoLBurstCount    = 3
+	defaultWoLBurstInterval = 100 * time.Millisecond
+)
+
+var errWakeNotApplicable = errors.New("wake strategy is not applicable")
+
+// WakeResult tells what a wake strategy sent to the TV.
+type WakeResult struct {
+	PacketsSent int
+	KeysSent    int
+}
+
+// WakeStrategy sends whatever should wake the TV. PowerOn calls it repeatedly
+// until the TV is on or the timeout expires.
+type WakeStrategy interface {
+	Wake(ctx context.Context, tv *TV) (WakeResult, error)
+}
+
+type WakeStrategyFunc func(ctx context.Context, tv *TV) (WakeResult, error)
+
+func (f WakeStrategyFunc) Wake(ctx context.Context, tv *TV) (WakeResult, error) {
+	return f(ctx, tv)
+}
+
+// WoLPattern describes a burst of Wake-on-LAN packets. Some TVs only wake
+// after several packets in a row.
+type WoLPattern struct {
+	Count    int
+	Interval time.Duration
+}
+
+// NewWoLWakeStrategy sends bursts of Wake-on-LAN packets.
+func NewWoLWakeStrategy(pattern WoLPattern) WakeStrategy {
+	if pattern.Count <= 0 {
+		pattern.Count = 1
+	}
+
+	return WakeStrategyFunc(func(ctx context.Context, tv *TV) (WakeResult, error) {
+		result := WakeResult{}
+
+		var lastErr error
+		for i := 0; i < pattern.Count; i++ {
+			if i > 0 {
+				err := tv.sleep(ctx, pattern.Interval)
+				if err != nil {
+					return result, err
+				}
+			}
+
+			err := tv.udpClient.WakeUpContext(ctx)
+			if err != nil {
+				lastErr = err
+
+				continue
+			}
+
+			result.PacketsSent++
+		}
+
+		if result.PacketsSent == 0 {
+			return result, lastErr
+		}
+
+		return result, nil
+	})
+}
+
+// NewKeyWakeStrategy sends a key to a TV in network standby, which keeps its
+// websocket API up. An empty key uses the power on key of the TV, KEY_POWER by
+// default. The key is sent once per power on as power keys may toggle. It is
+// sent through the connection of the TV, which PowerOn keeps afterwards.
+func NewKeyWakeStrategy(key Key) WakeStrategy {
+	return WakeStrategyFunc(func(ctx context.Context, tv *TV) (WakeResult, error) {
+		state, err := tv.PowerStateContext(ctx)
+		if err != nil {
+			return WakeResult{}, err
+		}
+
+		if state != PowerStateStandby {
+			return WakeResult{}, errWakeNotApplicable
+		}
+
+		if powerOnAttempt(ctx).keySent {
+			return WakeResult{}, nil
+		}
+
+		// The strategy may be shared by TVs with different power on keys.
+		wakeKey := key
+		if wakeKey == "" {
+			wakeKey = tv.powerOnKey()
+		}
+
+		err = tv.ClickKeyContext(ctx, wakeKey)
+		if err != nil {
+			return WakeResult{}, err
+		}
+
+		powerOnAttempt(ctx).keySent = true
+
+		return WakeResult{KeysSent: 1}, nil
+	})
+}
+
+// NewCombinedWakeStrategy runs all strategies in order. It fails only when all
+// of them fail.
+func NewCombinedWakeStrategy(strategies ...WakeStrategy) WakeStrategy {
+	return WakeStrategyFunc(func(ctx context.Context, tv *TV) (WakeResult, error) {
+		result := WakeResult{}
+
+		var errs []error
+		for _, strategy := range strategies {
+			r, err := strategy.Wake(ctx, tv)
+			result.PacketsSent += r.PacketsSent
+			result.KeysSent += r.KeysSent
+
+			if err != nil && err != errWakeNotApplicable {
+				errs = append(errs, err)
+			}
+
+			if ctx.Err() != nil {
+				return result, ctx.Err()
+			}
+		}
+
+		if len(errs) > 0 && result.PacketsSent == 0 && result.KeysSent == 0 {
+			return result, errs[0]
+		}
+
+		return result, nil
+	})
+}
+
+// DefaultWakeStrategy sends the power key to TVs in network standby and
+// Wake-on-LAN bursts to the rest.
+func DefaultWakeStrategy() WakeStrategy {
+	return NewCombinedWakeStrategy(
+		NewKeyWakeStrategy(""),
+		NewWoLWakeStrategy(WoLPattern{Count: defaultWoLBurstCount, Interval: defaultWoLBurstInterval}),
+	)
+}
+
+type PowerOnStage string
+
+const (
+	PowerOnStageWakeSent PowerOnStage = "wake_sent" // A wake attempt was made
+	PowerOnStagePortsUp  PowerOnStage = "ports_up"  // The TV accepts connections
+	PowerOnStageStandby  PowerOnStage = "standby"   // The TV reports network standby
+	PowerOnStageReady    PowerOnStage = "ready"     // The TV is on and its API answers
+)
+
+type PowerOnProgress struct {
+	Stage       PowerOnStage
+	Attempt     int
+	PacketsSent int
+	KeysSent    int
+	Elapsed     time.Duration
+	Err         error
+}
+
+type PowerOnProgressHandler func(progress PowerOnProgress)
+
+func WithWakeStrategy(strategy WakeStrategy) TVOption {
+	return func(tv *TV) {
+		tv.wakeStrategy = strategy
+	}
+}
+
+func WithWakeInterval(interval time.Duration) TVOption {
+	return func(tv *TV) {
+		tv.wakeInterval = interval
+	}
+}
+
+func (tv *TV) OnPowerOnProgress(handler PowerOnProgressHandler) {
+	tv.mu.Lock()
+	defer tv.mu.Unlock()
+
+	tv.powerOnProgressHandler = handler
+}
+
+type powerOnState struct {
+	keySent bool
+}
+
+type powerOnStateKey struct{}
+
+// powerOnAttempt returns the state shared by the strategies during one
+// PowerOn call.
+func powerOnAttempt(ctx context.Context) *powerOnState {
+	state, ok := ctx.Value(powerOnStateKey{}).(*powerOnState)
+	if !ok {
+		return &powerOnState{}
+	}
+
+	return state
+}
+
+// wake runs the wake strategy until the TV is ready. It returns an error
+// describing how far the TV got when the context is done.
+func (tv *TV) wake(ctx context.Context) error {
+	ctx = context.WithValue(ctx, powerOnStateKey{}, &powerOnState{})
+
+	strategy := tv.wakeStrategy
+	if strategy == nil {
+		strategy = DefaultWakeStrategy()
+	}
+
+	interval := tv.wakeInterval
+	if interval <= 0 {
+		interval = defaultWakeInterval
+	}
+
+	started := tv.clock.Now()
+	progress := PowerOnProgress{}
+	reached := map[PowerOnStage]bool{}
+
+	report := func(stage PowerOnStage, err error) {
+		progress.Stage = stage
+		progress.Elapsed = tv.clock.Since(started)
+		progress.Err = err
+
+		tv.mu.Lock()
+		handler := tv.powerOnProgressHandler
+		tv.mu.Unlock()
+
+		if handler != nil {
+			handler(progress)
+		}
+	}
+
+	reportOnce := func(stage PowerOnStage) {
+		if !reached[stage] {
+			reached[stage] = true
+			report(stage, nil)
+		}
+	}
+
+	var lastErr error
+	for {
+		if tv.isAvailable(ctx) {
+			reportOnce(PowerOnStagePortsUp)
+
+			state, err := tv.PowerStateContext(ctx)
+			if err == nil && state == PowerStateOn {
+				reportOnce(PowerOnStageReady)
+
+				return nil
+			}
+
+			if state == PowerStateStandby {
+				reportOnce(PowerOnStageStandby)
+			}
+		}
+
+		if ctx.Err() != nil {
+			return tv.wakeError(reached, progress, lastErr)
+		}
+
+		progress.Attempt++
+
+		result, err := strategy.Wake(ctx, tv)
+		progress.PacketsSent += result.PacketsSent
+		progress.KeysSent += result.KeysSent
+		lastErr = err
+
+		report(PowerOnStageWakeSent, err)
+
+		// Sleep even when waking failed, so that a failing strategy does not spin.
+		// The last sleep lasts until the deadline, the TV may still get ready.
+		err = tv.sleep(ctx, interval)
+		if err != nil {
+			return tv.wakeError(reached, progress, lastErr)
+		}
+	}
+}
+
+func (tv *TV) wakeError(
+	reached map[PowerOnStage]bool,
+	progress PowerOnProgress,
+	lastErr error,
+) error {
+	switch {
+	case reached[PowerOnStageStandby]:
+		return errors.New("TV stays in standby")
+	case reached[PowerOnStagePortsUp]:
+		return errors.New("TV accepts connections but its API is not ready")
+	case lastErr != nil:
+		return fmt.Errorf("cannot wake TV after %d attempts: %v", progress.Attempt, lastErr)
+	default:
+		return fmt.Errorf(
+			"cannot find TV in the network after %d attempts (%d packets sent)",
+			progress.Attempt,
+			progress.PacketsSent,
+		)
+	}
+}
@@ -0,0 +1,140 @@
+package samsung_test
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	samsung "github.com/kpeu3i/go-tizen-tv"
+	"github.com/kpeu3i/go-tizen-tv/clock"
+	"github.com/kpeu3i/go-tizen-tv/tizenapi"
+	"github.com/kpeu3i/go-tizen-tv/tizentest"
+)
+
+// closedPort returns a local port nothing listens on.
+func closedPort(t *testing.T) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+
+	_, port, _ := net.SplitHostPort(listener.Addr().String())
+	_ = listener.Close()
+
+	return port
+}
+
+// advanceWhileWaiting moves the fake clock by step each time the code under
+// test waits on at least n timers, until the result arrives. It returns the
+// time advanced.
+func advanceWhileWaiting(fakeClock *clock.Fake, n int, step time.Duration, done <-chan error) (time.Duration, error) {
+	advanced := time.Duration(0)
+
+	for {
+		select {
+		case err := <-done:
+			return advanced, err
+		case <-time.After(time.Millisecond):
+		}
+
+		if fakeClock.Waiters() >= n {
+			fakeClock.Advance(step)
+			advanced += step
+		}
+	}
+}
+
+func TestTVPowerOnWaitsUntilDeadlineAfterLastAttempt(t *testing.T) {
+	fakeClock := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	port := closedPort(t)
+
+	var attempts int32
+	strategy := samsung.WakeStrategyFunc(func(ctx context.Context, tv *samsung.TV) (samsung.WakeResult, error) {
+		atomic.AddInt32(&attempts, 1)
+
+		return samsung.WakeResult{PacketsSent: 1}, nil
+	})
+
+	tv := samsung.NewTV(
+		tizenapi.NewUDPAPIClient(""),
+		tizenapi.NewHTTPAPIClient("127.0.0.1", tizenapi.WithHTTPPort(port)),
+		tizenapi.NewWebsocketAPIClient("127.0.0.1", "tizentest", tizenapi.WithWebsocketPort(port)),
+		"",
+		samsung.WithClock(fakeClock),
+		samsung.WithPowerOnTimeout(10*time.Second),
+		samsung.WithWakeInterval(3*time.Second),
+		samsung.WithWakeStrategy(strategy),
+	)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- tv.PowerOn()
+	}()
+
+	// The timeout and the wait between attempts.
+	advanced, err := advanceWhileWaiting(fakeClock, 2, time.Second, done)
+	if err == nil || !strings.Contains(err.Error(), "after 4 attempts") {
+		t.Errorf("PowerOn() = %v, want a failure after 4 attempts", err)
+	}
+
+	// Attempts run at 0s, 3s, 6s and 9s, the next one would be too late but
+	// the last one is given until the deadline.
+	if advanced != 10*time.Second {
+		t.Errorf("PowerOn() returned after %s, want 10s", advanced)
+	}
+
+	if atomic.LoadInt32(&attempts) != 4 {
+		t.Errorf("attempts = %d, want 4", attempts)
+	}
+}
+
+func TestTVPowerOnChecksStateAtDeadline(t *testing.T) {
+	fakeClock := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	emulator, _, stop := startEmulator(t, tizentest.WithEmulatorPowerState(samsung.PowerStateStandby))
+	defer stop()
+
+	// Only the last attempt which fits before the deadline wakes the TV.
+	var attempts int32
+	strategy := samsung.WakeStrategyFunc(func(ctx context.Context, tv *samsung.TV) (samsung.WakeResult, error) {
+		if atomic.AddInt32(&attempts, 1) == 4 {
+			_ = emulator.SetPowerState(samsung.PowerStateOn)
+		}
+
+		return samsung.WakeResult{KeysSent: 1}, nil
+	})
+
+	tv := newEmulatorTV(
+		emulator,
+		samsung.WithClock(fakeClock),
+		samsung.WithPowerOnTimeout(10*time.Second),
+		samsung.WithWakeInterval(3*time.Second),
+		samsung.WithWakeStrategy(strategy),
+	)
+
+	defer func() {
+		_ = tv.Close()
+	}()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- tv.PowerOn()
+	}()
+
+	advanced, err := advanceWhileWaiting(fakeClock, 2, time.Second, done)
+	if err != nil {
+		t.Fatalf("PowerOn() = %v", err)
+	}
+
+	if advanced != 10*time.Second {
+		t.Errorf("PowerOn() returned after %s, want 10s", advanced)
+	}
+
+	if atomic.LoadInt32(&attempts) != 4 {
+		t.Errorf("attempts = %d, want 4", attempts)
+	}
+}
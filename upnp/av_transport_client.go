@@ -0,0 +1,179 @@
+package upnp
+
+import (
+	"context"
+	"strconv"
+	"time"
+)
+
+const (
+	ServiceTypeAVTransport = "urn:schemas-upnp-org:service:AVTransport:1"
+
+	TransportStateStopped       = "STOPPED"
+	TransportStatePlaying       = "PLAYING"
+	TransportStatePaused        = "PAUSED_PLAYBACK"
+	TransportStateTransitioning = "TRANSITIONING"
+	TransportStateNoMedia       = "NO_MEDIA_PRESENT"
+
+	defaultPlaySpeed = "1"
+	seekUnitRelTime  = "REL_TIME"
+)
+
+type TransportInfo struct {
+	State  string
+	Status string
+	Speed  string
+}
+
+type PositionInfo struct {
+	Track         int
+	TrackDuration time.Duration
+	TrackMetaData string
+	TrackURI      string
+	RelTime       time.Duration
+	AbsTime       time.Duration
+}
+
+type AVTransportClient struct {
+	client *Client
+}
+
+func NewAVTransportClient(location string, options ...Option) *AVTransportClient {
+	return &AVTransportClient{client: NewClient(location, options...)}
+}
+
+func (c *AVTransportClient) Location() string {
+	return c.client.Location()
+}
+
+// SetAVTransportURI loads a media URL into the renderer. The metadata is
+// rendered as DIDL-Lite, many renderers refuse URIs without it.
+func (c *AVTransportClient) SetAVTransportURI(uri string, metadata Metadata) error {
+	return c.SetAVTransportURIContext(context.Background(), uri, metadata)
+}
+
+func (c *AVTransportClient) SetAVTransportURIContext(ctx context.Context, uri string, metadata Metadata) error {
+	didl, err := DIDLLite(uri, metadata)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.call(
+		ctx,
+		"SetAVTransportURI",
+		Argument{Name: "CurrentURI", Value: uri},
+		Argument{Name: "CurrentURIMetaData", Value: didl},
+	)
+
+	return err
+}
+
+func (c *AVTransportClient) Play() error {
+	return c.PlayContext(context.Background())
+}
+
+func (c *AVTransportClient) PlayContext(ctx context.Context) error {
+	_, err := c.call(ctx, "Play", Argument{Name: "Speed", Value: defaultPlaySpeed})
+
+	return err
+}
+
+func (c *AVTransportClient) Pause() error {
+	return c.PauseContext(context.Background())
+}
+
+func (c *AVTransportClient) PauseContext(ctx context.Context) error {
+	_, err := c.call(ctx, "Pause")
+
+	return err
+}
+
+func (c *AVTransportClient) Stop() error {
+	return c.StopContext(context.Background())
+}
+
+func (c *AVTransportClient) StopContext(ctx context.Context) error {
+	_, err := c.call(ctx, "Stop")
+
+	return err
+}
+
+func (c *AVTransportClient) Seek(position time.Duration) error {
+	return c.SeekContext(context.Background(), position)
+}
+
+func (c *AVTransportClient) SeekContext(ctx context.Context, position time.Duration) error {
+	_, err := c.call(
+		ctx,
+		"Seek",
+		Argument{Name: "Unit", Value: seekUnitRelTime},
+		Argument{Name: "Target", Value: FormatDuration(position)},
+	)
+
+	return err
+}
+
+func (c *AVTransportClient) GetTransportInfo() (TransportInfo, error) {
+	return c.GetTransportInfoContext(context.Background())
+}
+
+func (c *AVTransportClient) GetTransportInfoContext(ctx context.Context) (TransportInfo, error) {
+	values, err := c.call(ctx, "GetTransportInfo")
+	if err != nil {
+		return TransportInfo{}, err
+	}
+
+	info := TransportInfo{
+		State:  values["CurrentTransportState"],
+		Status: values["CurrentTransportStatus"],
+		Speed:  values["CurrentSpeed"],
+	}
+
+	return info, nil
+}
+
+func (c *AVTransportClient) GetPositionInfo() (PositionInfo, error) {
+	return c.GetPositionInfoContext(context.Background())
+}
+
+func (c *AVTransportClient) GetPositionInfoContext(ctx context.Context) (PositionInfo, error) {
+	values, err := c.call(ctx, "GetPositionInfo")
+	if err != nil {
+		return PositionInfo{}, err
+	}
+
+	info := PositionInfo{
+		TrackMetaData: values["TrackMetaData"],
+		TrackURI:      values["TrackURI"],
+	}
+
+	if values["Track"] != "" {
+		info.Track, err = strconv.Atoi(values["Track"])
+		if err != nil {
+			return PositionInfo{}, err
+		}
+	}
+
+	info.TrackDuration, err = ParseDuration(values["TrackDuration"])
+	if err != nil {
+		return PositionInfo{}, err
+	}
+
+	info.RelTime, err = ParseDuration(values["RelTime"])
+	if err != nil {
+		return PositionInfo{}, err
+	}
+
+	info.AbsTime, err = ParseDuration(values["AbsTime"])
+	if err != nil {
+		return PositionInfo{}, err
+	}
+
+	return info, nil
+}
+
+func (c *AVTransportClient) call(ctx context.Context, action string, arguments ...Argument) (map[string]string, error) {
+	arguments = append([]Argument{{Name: "InstanceID", Value: defaultInstanceID}}, arguments...)
+
+	return c.client.Call(ctx, ServiceTypeAVTransport, action, arguments...)
+}
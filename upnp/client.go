@@ -0,0 +1,272 @@
+package upnp
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultDialTimeout    = 1 * time.Second
+	defaultRequestTimeout = 10 * time.Second
+)
+
+type Option func(*Client)
+
+func WithDialTimeout(timeout time.Duration) Option {
+	return func(client *Client) {
+		client.dialTimeout = timeout
+	}
+}
+
+func WithRequestTimeout(timeout time.Duration) Option {
+	return func(client *Client) {
+		client.requestTimeout = timeout
+	}
+}
+
+type Argument struct {
+	Name  string
+	Value string
+}
+
+type SOAPError struct {
+	Code        string
+	Description string
+}
+
+func (e *SOAPError) Error() string {
+	return fmt.Sprintf("upnp error %s: %s", e.Code, e.Description)
+}
+
+// Client talks to the services of one UPnP device. The device description is
+// fetched from the location on first use and cached afterwards.
+type Client struct {
+	location       string
+	dialTimeout    time.Duration
+	requestTimeout time.Duration
+	httpClient     *http.Client
+	mu             sync.Mutex
+	description    *DeviceDescription
+}
+
+func NewClient(location string, options ...Option) *Client {
+	client := &Client{
+		location:       location,
+		dialTimeout:    defaultDialTimeout,
+		requestTimeout: defaultRequestTimeout,
+	}
+
+	for _, option := range options {
+		option(client)
+	}
+
+	client.httpClient = &http.Client{
+		Timeout: client.requestTimeout,
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+				dialer := net.Dialer{Timeout: client.dialTimeout}
+
+				return dialer.DialContext(ctx, network, addr)
+			},
+		},
+	}
+
+	return client
+}
+
+func (c *Client) Location() string {
+	return c.location
+}
+
+func (c *Client) HTTPClient() *http.Client {
+	return c.httpClient
+}
+
+func (c *Client) Description(ctx context.Context) (DeviceDescription, error) {
+	c.mu.Lock()
+	description := c.description
+	c.mu.Unlock()
+
+	if description != nil {
+		return *description, nil
+	}
+
+	fetched, err := FetchDeviceDescription(ctx, c.httpClient, c.location)
+	if err != nil {
+		return DeviceDescription{}, err
+	}
+
+	c.mu.Lock()
+	c.description = &fetched
+	c.mu.Unlock()
+
+	return fetched, nil
+}
+
+func (c *Client) Service(ctx context.Context, serviceType string) (Service, error) {
+	description, err := c.Description(ctx)
+	if err != nil {
+		return Service{}, err
+	}
+
+	service, ok := description.Device.Service(serviceType)
+	if !ok {
+		return Service{}, fmt.Errorf("service %s is not provided by %s", serviceType, c.location)
+	}
+
+	return service, nil
+}
+
+// Call invokes a SOAP action and returns the output arguments by name.
+func (c *Client) Call(
+	ctx context.Context,
+	serviceType string,
+	action string,
+	arguments ...Argument,
+) (map[string]string, error) {
+	service, err := c.Service(ctx, serviceType)
+	if err != nil {
+		return nil, err
+	}
+
+	body := &bytes.Buffer{}
+	body.WriteString(`<?xml version="1.0" encoding="utf-8"?>`)
+	body.WriteString(`<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/" `)
+	body.WriteString(`s:encodingStyle="http://schemas.xmlsoap.org/soap/encoding/"><s:Body>`)
+	fmt.Fprintf(body, `<u:%s xmlns:u="%s">`, action, service.ServiceType)
+
+	for _, argument := range arguments {
+		fmt.Fprintf(body, "<%s>", argument.Name)
+		err = xml.EscapeText(body, []byte(argument.Value))
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(body, "</%s>", argument.Name)
+	}
+
+	fmt.Fprintf(body, "</u:%s></s:Body></s:Envelope>", action)
+
+	request, err := http.NewRequest(http.MethodPost, service.ControlURL, body)
+	if err != nil {
+		return nil, err
+	}
+
+	request.Header.Set("Content-Type", `text/xml; charset="utf-8"`)
+	request.Header.Set("SOAPAction", fmt.Sprintf(`"%s#%s"`, service.ServiceType, action))
+
+	response, err := c.httpClient.Do(request.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = response.Body.Close()
+	}()
+
+	data, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := parseSOAPResponse(data, action+"Response")
+	if err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("invalid response status: %d", response.StatusCode)
+	}
+
+	return values, nil
+}
+
+func FetchDeviceDescription(ctx context.Context, httpClient *http.Client, location string) (DeviceDescription, error) {
+	request, err := http.NewRequest(http.MethodGet, location, nil)
+	if err != nil {
+		return DeviceDescription{}, err
+	}
+
+	response, err := httpClient.Do(request.WithContext(ctx))
+	if err != nil {
+		return DeviceDescription{}, err
+	}
+
+	defer func() {
+		_ = response.Body.Close()
+	}()
+
+	if response.StatusCode != http.StatusOK {
+		return DeviceDescription{}, fmt.Errorf("invalid response status: %d", response.StatusCode)
+	}
+
+	data, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return DeviceDescription{}, err
+	}
+
+	return parseDeviceDescription(data, location)
+}
+
+func parseSOAPResponse(data []byte, responseElement string) (map[string]string, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+
+	values := map[string]string{}
+	depth := 0
+	inResponse := false
+	inFault := false
+	current := ""
+
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			break
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			depth++
+			switch {
+			case t.Name.Local == responseElement:
+				inResponse = true
+				depth = 0
+			case t.Name.Local == "Fault":
+				inFault = true
+			case inResponse && depth == 1, inFault:
+				current = t.Name.Local
+				values[current] = ""
+			}
+		case xml.CharData:
+			if current != "" {
+				values[current] += string(t)
+			}
+		case xml.EndElement:
+			depth--
+			current = ""
+			if t.Name.Local == responseElement {
+				return values, nil
+			}
+		}
+	}
+
+	if inFault {
+		return nil, &SOAPError{
+			Code:        strings.TrimSpace(values["errorCode"]),
+			Description: strings.TrimSpace(values["errorDescription"]),
+		}
+	}
+
+	if !inResponse {
+		return nil, errors.New("invalid SOAP response")
+	}
+
+	return values, nil
+}
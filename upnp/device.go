@@ -0,0 +1,124 @@
+package upnp
+
+import (
+	"encoding/xml"
+	"net/url"
+	"strings"
+)
+
+type Service struct {
+	ServiceType string `xml:"serviceType"`
+	ServiceID   string `xml:"serviceId"`
+	SCPDURL     string `xml:"SCPDURL"`
+	ControlURL  string `xml:"controlURL"`
+	EventSubURL string `xml:"eventSubURL"`
+}
+
+type Device struct {
+	DeviceType   string    `xml:"deviceType"`
+	FriendlyName string    `xml:"friendlyName"`
+	Manufacturer string    `xml:"manufacturer"`
+	ModelName    string    `xml:"modelName"`
+	ModelNumber  string    `xml:"modelNumber"`
+	UDN          string    `xml:"UDN"`
+	Services     []Service `xml:"serviceList>service"`
+	Devices      []Device  `xml:"deviceList>device"`
+}
+
+type DeviceDescription struct {
+	XMLName xml.Name `xml:"root"`
+	URLBase string   `xml:"URLBase"`
+	Device  Device   `xml:"device"`
+}
+
+// Service looks for a service of the given type in the device and its
+// embedded devices. A type without a version matches any version.
+func (d Device) Service(serviceType string) (Service, bool) {
+	for _, service := range d.Services {
+		if matchType(service.ServiceType, serviceType) {
+			return service, true
+		}
+	}
+
+	for _, device := range d.Devices {
+		service, ok := device.Service(serviceType)
+		if ok {
+			return service, true
+		}
+	}
+
+	return Service{}, false
+}
+
+func (d Device) IsType(deviceType string) bool {
+	if matchType(d.DeviceType, deviceType) {
+		return true
+	}
+
+	for _, device := range d.Devices {
+		if device.IsType(deviceType) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func parseDeviceDescription(data []byte, location string) (DeviceDescription, error) {
+	description := DeviceDescription{}
+	err := xml.Unmarshal(data, &description)
+	if err != nil {
+		return DeviceDescription{}, err
+	}
+
+	base := description.URLBase
+	if base == "" {
+		base = location
+	}
+
+	baseURL, err := url.Parse(base)
+	if err != nil {
+		return DeviceDescription{}, err
+	}
+
+	resolveDeviceURLs(&description.Device, baseURL)
+
+	return description, nil
+}
+
+func resolveDeviceURLs(device *Device, baseURL *url.URL) {
+	for i := range device.Services {
+		service := &device.Services[i]
+		service.SCPDURL = resolveURL(baseURL, service.SCPDURL)
+		service.ControlURL = resolveURL(baseURL, service.ControlURL)
+		service.EventSubURL = resolveURL(baseURL, service.EventSubURL)
+	}
+
+	for i := range device.Devices {
+		resolveDeviceURLs(&device.Devices[i], baseURL)
+	}
+}
+
+func resolveURL(baseURL *url.URL, ref string) string {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return ""
+	}
+
+	u, err := url.Parse(ref)
+	if err != nil {
+		return ref
+	}
+
+	return baseURL.ResolveReference(u).String()
+}
+
+func matchType(actual string, expected string) bool {
+	actual = strings.TrimSpace(actual)
+	if actual == expected {
+		return true
+	}
+
+	// "urn:schemas-upnp-org:service:RenderingControl" matches any version.
+	return strings.Count(expected, ":") == 3 && strings.HasPrefix(actual, expected+":")
+}
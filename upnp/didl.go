@@ -0,0 +1,153 @@
+package upnp
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	ClassVideoItem = "object.item.videoItem"
+	ClassAudioItem = "object.item.audioItem.musicTrack"
+	ClassImageItem = "object.item.imageItem.photo"
+)
+
+// Metadata describes a media item for SetAVTransportURI. Empty fields are
+// omitted from the DIDL-Lite document, the class is derived from the MIME type
+// when not set.
+type Metadata struct {
+	Title       string
+	Creator     string
+	Class       string
+	MIMEType    string
+	Duration    time.Duration
+	Size        int64
+	AlbumArtURI string
+}
+
+// DIDLLite renders the metadata of a single item served at the given URL.
+func DIDLLite(uri string, metadata Metadata) (string, error) {
+	class := metadata.Class
+	if class == "" {
+		class = classFromMIMEType(metadata.MIMEType)
+	}
+
+	mimeType := metadata.MIMEType
+	if mimeType == "" {
+		mimeType = "*"
+	}
+
+	title := metadata.Title
+	if title == "" {
+		title = uri
+	}
+
+	body := &bytes.Buffer{}
+	body.WriteString(`<DIDL-Lite xmlns="urn:schemas-upnp-org:metadata-1-0/DIDL-Lite/" `)
+	body.WriteString(`xmlns:dc="http://purl.org/dc/elements/1.1/" `)
+	body.WriteString(`xmlns:upnp="urn:schemas-upnp-org:metadata-1-0/upnp/">`)
+	body.WriteString(`<item id="0" parentID="-1" restricted="1">`)
+
+	elements := []struct {
+		name  string
+		value string
+	}{
+		{"dc:title", title},
+		{"dc:creator", metadata.Creator},
+		{"upnp:class", class},
+		{"upnp:albumArtURI", metadata.AlbumArtURI},
+	}
+
+	for _, element := range elements {
+		if element.value == "" {
+			continue
+		}
+
+		err := writeElement(body, element.name, "", element.value)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	attributes := fmt.Sprintf(` protocolInfo="http-get:*:%s:*"`, escapeAttribute(mimeType))
+	if metadata.Duration > 0 {
+		attributes += fmt.Sprintf(` duration="%s.000"`, FormatDuration(metadata.Duration))
+	}
+	if metadata.Size > 0 {
+		attributes += fmt.Sprintf(` size="%d"`, metadata.Size)
+	}
+
+	err := writeElement(body, "res", attributes, uri)
+	if err != nil {
+		return "", err
+	}
+
+	body.WriteString(`</item></DIDL-Lite>`)
+
+	return body.String(), nil
+}
+
+// FormatDuration formats a duration as H+:MM:SS which is what AVTransport uses
+// for track durations and seek targets.
+func FormatDuration(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+
+	seconds := int64(d / time.Second)
+
+	return fmt.Sprintf("%d:%02d:%02d", seconds/3600, seconds/60%60, seconds%60)
+}
+
+// ParseDuration parses H+:MM:SS[.F+] values. Values the renderer does not know,
+// like "NOT_IMPLEMENTED", parse as zero.
+func ParseDuration(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || value == "NOT_IMPLEMENTED" {
+		return 0, nil
+	}
+
+	var hours, minutes int64
+	var seconds float64
+
+	_, err := fmt.Sscanf(value, "%d:%d:%f", &hours, &minutes, &seconds)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration: %s", value)
+	}
+
+	d := time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute
+	d += time.Duration(seconds * float64(time.Second))
+
+	return d, nil
+}
+
+func classFromMIMEType(mimeType string) string {
+	switch {
+	case strings.HasPrefix(mimeType, "audio/"):
+		return ClassAudioItem
+	case strings.HasPrefix(mimeType, "image/"):
+		return ClassImageItem
+	default:
+		return ClassVideoItem
+	}
+}
+
+func writeElement(body *bytes.Buffer, name string, attributes string, value string) error {
+	fmt.Fprintf(body, "<%s%s>", name, attributes)
+	err := xml.EscapeText(body, []byte(value))
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(body, "</%s>", name)
+
+	return nil
+}
+
+func escapeAttribute(value string) string {
+	buffer := &bytes.Buffer{}
+	_ = xml.EscapeText(buffer, []byte(value))
+
+	return buffer.String()
+}
@@ -0,0 +1,145 @@
+package upnp
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const lastChangeVariable = "LastChange"
+
+// Event is a GENA notification. Properties holds the evented state variables,
+// LastChange is decoded into Changes when the service moderates its events.
+type Event struct {
+	SID         string
+	Seq         uint32
+	ServiceType string
+	Properties  map[string]string
+	Changes     []InstanceChange
+}
+
+// InstanceChange holds the state variables changed for one instance. Variables
+// that carry a channel are stored only for the Master channel.
+type InstanceChange struct {
+	InstanceID string
+	Variables  map[string]string
+}
+
+type EventHandler func(event Event)
+
+type RenderingControlChange struct {
+	InstanceID string
+	Volume     *int
+	Mute       *bool
+}
+
+type AVTransportChange struct {
+	InstanceID           string
+	TransportState       string
+	TransportStatus      string
+	CurrentTrackURI      string
+	CurrentTrackDuration time.Duration
+	AVTransportURI       string
+}
+
+func (c InstanceChange) RenderingControl() RenderingControlChange {
+	change := RenderingControlChange{InstanceID: c.InstanceID}
+
+	if value, ok := c.Variables["Volume"]; ok {
+		volume, err := strconv.Atoi(value)
+		if err == nil {
+			change.Volume = &volume
+		}
+	}
+
+	if value, ok := c.Variables["Mute"]; ok {
+		mute, err := parseBool(value)
+		if err == nil {
+			change.Mute = &mute
+		}
+	}
+
+	return change
+}
+
+func (c InstanceChange) AVTransport() AVTransportChange {
+	change := AVTransportChange{
+		InstanceID:      c.InstanceID,
+		TransportState:  c.Variables["TransportState"],
+		TransportStatus: c.Variables["TransportStatus"],
+		CurrentTrackURI: c.Variables["CurrentTrackURI"],
+		AVTransportURI:  c.Variables["AVTransportURI"],
+	}
+
+	change.CurrentTrackDuration, _ = ParseDuration(c.Variables["CurrentTrackDuration"])
+
+	return change
+}
+
+type propertySet struct {
+	Properties []struct {
+		Values []struct {
+			XMLName xml.Name
+			Value   string `xml:",chardata"`
+		} `xml:",any"`
+	} `xml:"property"`
+}
+
+type lastChangeEvent struct {
+	Instances []struct {
+		Val       string `xml:"val,attr"`
+		Variables []struct {
+			XMLName xml.Name
+			Val     string `xml:"val,attr"`
+			Channel string `xml:"channel,attr"`
+		} `xml:",any"`
+	} `xml:"InstanceID"`
+}
+
+func parsePropertySet(data []byte) (map[string]string, error) {
+	set := propertySet{}
+	err := xml.Unmarshal(data, &set)
+	if err != nil {
+		return nil, err
+	}
+
+	properties := map[string]string{}
+	for _, property := range set.Properties {
+		for _, value := range property.Values {
+			properties[value.XMLName.Local] = value.Value
+		}
+	}
+
+	return properties, nil
+}
+
+func parseLastChange(value string) ([]InstanceChange, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+
+	event := lastChangeEvent{}
+	err := xml.NewDecoder(bytes.NewReader([]byte(value))).Decode(&event)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make([]InstanceChange, 0, len(event.Instances))
+	for _, instance := range event.Instances {
+		change := InstanceChange{InstanceID: instance.Val, Variables: map[string]string{}}
+		for _, variable := range instance.Variables {
+			if variable.Channel != "" && variable.Channel != defaultChannel {
+				continue
+			}
+
+			change.Variables[variable.XMLName.Local] = variable.Val
+		}
+
+		changes = append(changes, change)
+	}
+
+	return changes, nil
+}
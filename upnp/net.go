@@ -0,0 +1,27 @@
+package upnp
+
+import (
+	"fmt"
+	"net"
+)
+
+// LocalIP returns the address of the local interface which routes to the
+// remote host. No packets are sent. Devices are given this address in the
+// callback and media URLs they fetch from us.
+func LocalIP(remoteHost string) (net.IP, error) {
+	connection, err := net.Dial("udp", net.JoinHostPort(remoteHost, "9"))
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = connection.Close()
+	}()
+
+	address, ok := connection.LocalAddr().(*net.UDPAddr)
+	if !ok {
+		return nil, fmt.Errorf("unexpected local address: %s", connection.LocalAddr())
+	}
+
+	return address.IP, nil
+}
@@ -0,0 +1,116 @@
+package upnp
+
+import (
+	"context"
+	"strconv"
+)
+
+const (
+	ServiceTypeRenderingControl = "urn:schemas-upnp-org:service:RenderingControl:1"
+
+	defaultInstanceID = "0"
+	defaultChannel    = "Master"
+)
+
+type RenderingControlClient struct {
+	client *Client
+}
+
+func NewRenderingControlClient(location string, options ...Option) *RenderingControlClient {
+	return &RenderingControlClient{client: NewClient(location, options...)}
+}
+
+func (c *RenderingControlClient) Location() string {
+	return c.client.Location()
+}
+
+func (c *RenderingControlClient) GetVolume() (int, error) {
+	return c.GetVolumeContext(context.Background())
+}
+
+func (c *RenderingControlClient) GetVolumeContext(ctx context.Context) (int, error) {
+	values, err := c.client.Call(
+		ctx,
+		ServiceTypeRenderingControl,
+		"GetVolume",
+		Argument{Name: "InstanceID", Value: defaultInstanceID},
+		Argument{Name: "Channel", Value: defaultChannel},
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.Atoi(values["CurrentVolume"])
+}
+
+func (c *RenderingControlClient) SetVolume(volume int) error {
+	return c.SetVolumeContext(context.Background(), volume)
+}
+
+func (c *RenderingControlClient) SetVolumeContext(ctx context.Context, volume int) error {
+	_, err := c.client.Call(
+		ctx,
+		ServiceTypeRenderingControl,
+		"SetVolume",
+		Argument{Name: "InstanceID", Value: defaultInstanceID},
+		Argument{Name: "Channel", Value: defaultChannel},
+		Argument{Name: "DesiredVolume", Value: strconv.Itoa(volume)},
+	)
+
+	return err
+}
+
+func (c *RenderingControlClient) GetMute() (bool, error) {
+	return c.GetMuteContext(context.Background())
+}
+
+func (c *RenderingControlClient) GetMuteContext(ctx context.Context) (bool, error) {
+	values, err := c.client.Call(
+		ctx,
+		ServiceTypeRenderingControl,
+		"GetMute",
+		Argument{Name: "InstanceID", Value: defaultInstanceID},
+		Argument{Name: "Channel", Value: defaultChannel},
+	)
+	if err != nil {
+		return false, err
+	}
+
+	return parseBool(values["CurrentMute"])
+}
+
+func (c *RenderingControlClient) SetMute(mute bool) error {
+	return c.SetMuteContext(context.Background(), mute)
+}
+
+func (c *RenderingControlClient) SetMuteContext(ctx context.Context, mute bool) error {
+	_, err := c.client.Call(
+		ctx,
+		ServiceTypeRenderingControl,
+		"SetMute",
+		Argument{Name: "InstanceID", Value: defaultInstanceID},
+		Argument{Name: "Channel", Value: defaultChannel},
+		Argument{Name: "DesiredMute", Value: formatBool(mute)},
+	)
+
+	return err
+}
+
+func parseBool(value string) (bool, error) {
+	switch value {
+	case "1", "true", "True", "yes":
+		return true, nil
+	case "0", "false", "False", "no":
+		return false, nil
+	default:
+		return strconv.ParseBool(value)
+	}
+}
+
+func formatBool(value bool) string {
+	if value {
+		return "1"
+	}
+
+	return "0"
+}
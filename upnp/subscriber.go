@@ -0,0 +1,553 @@
+package upnp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kpeu3i/go-tizen-tv/clock"
+)
+
+const (
+	defaultCallbackAddress       = ":0"
+	defaultSubscriptionTimeout   = 30 * time.Minute
+	defaultResubscribeDelay      = 10 * time.Second
+	defaultSubscriberStopTimeout = 5 * time.Second
+	eventsPathPrefix             = "/events/"
+
+	methodSubscribe   = "SUBSCRIBE"
+	methodUnsubscribe = "UNSUBSCRIBE"
+	methodNotify      = "NOTIFY"
+)
+
+var ErrSubscriptionClosed = errors.New("subscription is closed")
+
+type SubscriberOption func(*Subscriber)
+
+// WithCallbackAddress sets the address the callback server listens on. By
+// default it listens on all interfaces on a random port.
+func WithCallbackAddress(address string) SubscriberOption {
+	return func(subscriber *Subscriber) {
+		subscriber.callbackAddress = address
+	}
+}
+
+// WithCallbackHost sets the host put into callback URLs instead of the address
+// of the interface which routes to the device.
+func WithCallbackHost(host string) SubscriberOption {
+	return func(subscriber *Subscriber) {
+		subscriber.callbackHost = host
+	}
+}
+
+func WithSubscriptionTimeout(timeout time.Duration) SubscriberOption {
+	return func(subscriber *Subscriber) {
+		subscriber.timeout = timeout
+	}
+}
+
+// WithSubscriberClock sets the clock which schedules renewals.
+func WithSubscriberClock(c clock.Clock) SubscriberOption {
+	return func(subscriber *Subscriber) {
+		subscriber.clock = c
+	}
+}
+
+func WithSubscriberClientOptions(options ...Option) SubscriberOption {
+	return func(subscriber *Subscriber) {
+		subscriber.clientOptions = options
+	}
+}
+
+// Subscriber manages GENA subscriptions. It runs the HTTP server receiving
+// NOTIFY requests and renews subscriptions before they time out.
+type Subscriber struct {
+	callbackAddress string
+	callbackHost    string
+	timeout         time.Duration
+	clientOptions   []Option
+	clock           clock.Clock
+	httpClient      *http.Client
+	mu              sync.Mutex
+	listener        net.Listener
+	httpServer      *http.Server
+	subscriptions   map[string]*Subscription
+	nextID          uint64
+}
+
+type Subscription struct {
+	subscriber  *Subscriber
+	id          string
+	serviceType string
+	eventSubURL string
+	callbackURL string
+	handler     EventHandler
+	mu          sync.Mutex
+	sid         string
+	seqSID      string
+	nextSeq     uint32
+	resync      chan struct{}
+	stop        chan struct{}
+	done        chan struct{}
+	closeOnce   sync.Once
+}
+
+func NewSubscriber(options ...SubscriberOption) *Subscriber {
+	subscriber := &Subscriber{
+		callbackAddress: defaultCallbackAddress,
+		timeout:         defaultSubscriptionTimeout,
+		clock:           clock.New(),
+		subscriptions:   map[string]*Subscription{},
+	}
+
+	for _, option := range options {
+		option(subscriber)
+	}
+
+	subscriber.httpClient = NewClient("", subscriber.clientOptions...).HTTPClient()
+
+	return subscriber
+}
+
+// Start starts the callback server. Calling Start on a started subscriber does
+// nothing.
+func (s *Subscriber) Start() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.listener != nil {
+		return nil
+	}
+
+	listener, err := net.Listen("tcp", s.callbackAddress)
+	if err != nil {
+		return err
+	}
+
+	httpServer := &http.Server{Handler: http.HandlerFunc(s.handleNotify)}
+	s.listener = listener
+	s.httpServer = httpServer
+
+	go func() {
+		_ = httpServer.Serve(listener)
+	}()
+
+	return nil
+}
+
+// Subscribe subscribes to the events of a service of the device described at
+// the location. The handler is called from the callback server goroutines.
+func (s *Subscriber) Subscribe(
+	ctx context.Context,
+	location string,
+	serviceType string,
+	handler EventHandler,
+) (*Subscription, error) {
+	err := s.Start()
+	if err != nil {
+		return nil, err
+	}
+
+	service, err := NewClient(location, s.clientOptions...).Service(ctx, serviceType)
+	if err != nil {
+		return nil, err
+	}
+
+	if service.EventSubURL == "" {
+		return nil, fmt.Errorf("service %s has no event subscription URL", serviceType)
+	}
+
+	s.mu.Lock()
+	s.nextID++
+	id := strconv.FormatUint(s.nextID, 10)
+	s.mu.Unlock()
+
+	callbackURL, err := s.callbackURL(service.EventSubURL, id)
+	if err != nil {
+		return nil, err
+	}
+
+	subscription := &Subscription{
+		subscriber:  s,
+		id:          id,
+		serviceType: service.ServiceType,
+		eventSubURL: service.EventSubURL,
+		callbackURL: callbackURL,
+		handler:     handler,
+		resync:      make(chan struct{}, 1),
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+
+	// Registered before subscribing, the initial event may arrive before the
+	// response to SUBSCRIBE.
+	s.mu.Lock()
+	s.subscriptions[id] = subscription
+	s.mu.Unlock()
+
+	timeout, err := subscription.subscribe(ctx)
+	if err != nil {
+		s.remove(id)
+
+		return nil, err
+	}
+
+	go subscription.renew(timeout)
+
+	return subscription, nil
+}
+
+func (s *Subscriber) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultSubscriberStopTimeout)
+	defer cancel()
+
+	return s.CloseContext(ctx)
+}
+
+// CloseContext cancels all subscriptions and stops the callback server.
+func (s *Subscriber) CloseContext(ctx context.Context) error {
+	s.mu.Lock()
+	subscriptions := make([]*Subscription, 0, len(s.subscriptions))
+	for _, subscription := range s.subscriptions {
+		subscriptions = append(subscriptions, subscription)
+	}
+	s.mu.Unlock()
+
+	for _, subscription := range subscriptions {
+		_ = subscription.UnsubscribeContext(ctx)
+	}
+
+	s.mu.Lock()
+	httpServer := s.httpServer
+	s.listener = nil
+	s.httpServer = nil
+	s.mu.Unlock()
+
+	if httpServer == nil {
+		return nil
+	}
+
+	return httpServer.Shutdown(ctx)
+}
+
+func (s *Subscriber) callbackURL(eventSubURL string, id string) (string, error) {
+	s.mu.Lock()
+	listener := s.listener
+	s.mu.Unlock()
+
+	if listener == nil {
+		return "", errors.New("subscriber is not started")
+	}
+
+	host := s.callbackHost
+	if host == "" {
+		request, err := http.NewRequest(methodSubscribe, eventSubURL, nil)
+		if err != nil {
+			return "", err
+		}
+
+		ip, err := LocalIP(request.URL.Hostname())
+		if err != nil {
+			return "", err
+		}
+
+		host = ip.String()
+	}
+
+	_, port, err := net.SplitHostPort(listener.Addr().String())
+	if err != nil {
+		return "", err
+	}
+
+	return "http://" + net.JoinHostPort(host, port) + eventsPathPrefix + id, nil
+}
+
+func (s *Subscriber) remove(id string) {
+	s.mu.Lock()
+	delete(s.subscriptions, id)
+	s.mu.Unlock()
+}
+
+func (s *Subscriber) handleNotify(w http.ResponseWriter, r *http.Request) {
+	if r.Method != methodNotify || !strings.HasPrefix(r.URL.Path, eventsPathPrefix) {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+
+		return
+	}
+
+	s.mu.Lock()
+	subscription, ok := s.subscriptions[strings.TrimPrefix(r.URL.Path, eventsPathPrefix)]
+	s.mu.Unlock()
+
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusPreconditionFailed), http.StatusPreconditionFailed)
+
+		return
+	}
+
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+
+		return
+	}
+
+	properties, err := parsePropertySet(data)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+
+		return
+	}
+
+	seq, _ := strconv.ParseUint(r.Header.Get("SEQ"), 10, 32)
+
+	event := Event{
+		SID:         r.Header.Get("SID"),
+		Seq:         uint32(seq),
+		ServiceType: subscription.serviceType,
+		Properties:  properties,
+	}
+
+	// A malformed LastChange still delivers the raw properties.
+	event.Changes, _ = parseLastChange(properties[lastChangeVariable])
+
+	w.WriteHeader(http.StatusOK)
+
+	missed := subscription.missed(event.SID, event.Seq)
+
+	subscription.handler(event)
+
+	if missed {
+		subscription.requestResync()
+	}
+}
+
+func (s *Subscription) SID() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.sid
+}
+
+func (s *Subscription) ServiceType() string {
+	return s.serviceType
+}
+
+func (s *Subscription) Unsubscribe() error {
+	return s.UnsubscribeContext(context.Background())
+}
+
+// UnsubscribeContext stops renewing and cancels the subscription on the device.
+func (s *Subscription) UnsubscribeContext(ctx context.Context) error {
+	err := ErrSubscriptionClosed
+
+	s.closeOnce.Do(func() {
+		close(s.stop)
+		<-s.done
+
+		s.subscriber.remove(s.id)
+
+		err = s.unsubscribe(ctx)
+	})
+
+	return err
+}
+
+func (s *Subscription) subscribe(ctx context.Context) (time.Duration, error) {
+	request, err := http.NewRequest(methodSubscribe, s.eventSubURL, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	request.Header.Set("CALLBACK", "<"+s.callbackURL+">")
+	request.Header.Set("NT", "upnp:event")
+	request.Header.Set("TIMEOUT", formatTimeout(s.subscriber.timeout))
+
+	return s.do(ctx, request)
+}
+
+func (s *Subscription) resubscribe(ctx context.Context) (time.Duration, error) {
+	sid := s.SID()
+	if sid == "" {
+		return s.subscribe(ctx)
+	}
+
+	request, err := http.NewRequest(methodSubscribe, s.eventSubURL, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	request.Header.Set("SID", sid)
+	request.Header.Set("TIMEOUT", formatTimeout(s.subscriber.timeout))
+
+	timeout, err := s.do(ctx, request)
+	if err != nil {
+		// The device dropped the subscription, e.g. after a restart.
+		return s.subscribe(ctx)
+	}
+
+	return timeout, nil
+}
+
+// restart replaces the subscription with a new one. Its initial event carries
+// the whole state again, which makes up for missed events.
+func (s *Subscription) restart(ctx context.Context) (time.Duration, error) {
+	_ = s.unsubscribe(ctx)
+
+	s.mu.Lock()
+	s.sid = ""
+	s.mu.Unlock()
+
+	return s.subscribe(ctx)
+}
+
+// missed tells whether events have been missed before the one with the
+// sequence number. Events of a subscription which has been replaced are not
+// checked.
+func (s *Subscription) missed(sid string, seq uint32) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.sid != "" && sid != s.sid {
+		return false
+	}
+
+	expected := s.nextSeq
+	isFirst := sid != s.seqSID
+
+	s.seqSID = sid
+	s.nextSeq = nextSeq(seq)
+
+	// The initial event of a subscription is numbered 0.
+	if isFirst {
+		return seq != 0
+	}
+
+	return seq != expected
+}
+
+func (s *Subscription) requestResync() {
+	select {
+	case s.resync <- struct{}{}:
+	default:
+	}
+}
+
+func (s *Subscription) unsubscribe(ctx context.Context) error {
+	sid := s.SID()
+	if sid == "" {
+		return nil
+	}
+
+	request, err := http.NewRequest(methodUnsubscribe, s.eventSubURL, nil)
+	if err != nil {
+		return err
+	}
+
+	request.Header.Set("SID", sid)
+
+	_, err = s.do(ctx, request)
+
+	return err
+}
+
+func (s *Subscription) do(ctx context.Context, request *http.Request) (time.Duration, error) {
+	response, err := s.subscriber.httpClient.Do(request.WithContext(ctx))
+	if err != nil {
+		return 0, err
+	}
+
+	defer func() {
+		_ = response.Body.Close()
+	}()
+
+	if response.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("invalid response status: %d", response.StatusCode)
+	}
+
+	if request.Method == methodUnsubscribe {
+		return 0, nil
+	}
+
+	sid := response.Header.Get("SID")
+	if sid == "" {
+		return 0, errors.New("subscription response has no SID")
+	}
+
+	s.mu.Lock()
+	s.sid = sid
+	s.mu.Unlock()
+
+	return parseTimeout(response.Header.Get("TIMEOUT"), s.subscriber.timeout), nil
+}
+
+// renew renews the subscription at half of its timeout until it is stopped.
+// When events have been missed, the subscription is restarted instead.
+func (s *Subscription) renew(timeout time.Duration) {
+	defer close(s.done)
+
+	for {
+		delay := timeout / 2
+		if delay <= 0 {
+			delay = defaultResubscribeDelay
+		}
+
+		resubscribe := s.resubscribe
+
+		select {
+		case <-s.stop:
+			return
+		case <-s.subscriber.clock.After(delay):
+		case <-s.resync:
+			resubscribe = s.restart
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), defaultResubscribeDelay)
+		renewed, err := resubscribe(ctx)
+		cancel()
+
+		if err != nil {
+			timeout = 2 * defaultResubscribeDelay
+
+			continue
+		}
+
+		timeout = renewed
+	}
+}
+
+func formatTimeout(timeout time.Duration) string {
+	return "Second-" + strconv.Itoa(int(timeout/time.Second))
+}
+
+func parseTimeout(value string, fallback time.Duration) time.Duration {
+	value = strings.TrimSpace(strings.ToLower(value))
+	if !strings.HasPrefix(value, "second-") {
+		return fallback
+	}
+
+	seconds, err := strconv.Atoi(strings.TrimPrefix(value, "second-"))
+	if err != nil || seconds <= 0 {
+		return fallback
+	}
+
+	return time.Duration(seconds) * time.Second
+}
+
+// nextSeq returns the sequence number following seq. After the maximum it
+// wraps to 1, as 0 is reserved for the initial event.
+func nextSeq(seq uint32) uint32 {
+	if seq == math.MaxUint32 {
+		return 1
+	}
+
+	return seq + 1
+}
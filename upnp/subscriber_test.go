@@ -0,0 +1,274 @@
+package upnp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kpeu3i/go-tizen-tv/clock"
+)
+
+const testEventBody = `<?xml version="1.0"?>
+<e:propertyset xmlns:e="urn:schemas-upnp-org:event-1-0">
+  <e:property><Volume>10</Volume></e:property>
+</e:propertyset>`
+
+// fakeEventDevice is a device with one evented service which records the
+// subscriptions made to it.
+type fakeEventDevice struct {
+	server *httptest.Server
+
+	mu         sync.Mutex
+	subscribes int
+	renewals   int
+	callback   string
+	sid        string
+	subscribed chan struct{}
+}
+
+func newFakeEventDevice() *fakeEventDevice {
+	device := &fakeEventDevice{subscribed: make(chan struct{}, 8)}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/dmr", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `<?xml version="1.0"?>
+<root xmlns="urn:schemas-upnp-org:device-1-0">
+  <device>
+    <deviceType>urn:schemas-upnp-org:device:MediaRenderer:1</deviceType>
+    <serviceList>
+      <service>
+        <serviceType>urn:schemas-upnp-org:service:RenderingControl:1</serviceType>
+        <serviceId>urn:upnp-org:serviceId:RenderingControl</serviceId>
+        <controlURL>/control</controlURL>
+        <eventSubURL>/events</eventSubURL>
+      </service>
+    </serviceList>
+  </device>
+</root>`)
+	})
+	mux.HandleFunc("/events", device.handleEvents)
+
+	device.server = httptest.NewServer(mux)
+
+	return device
+}
+
+func (d *fakeEventDevice) handleEvents(w http.ResponseWriter, r *http.Request) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	switch r.Method {
+	case methodSubscribe:
+		if callback := r.Header.Get("CALLBACK"); callback != "" {
+			// A new subscription rather than a renewal.
+			d.subscribes++
+			d.callback = strings.Trim(callback, "<>")
+			d.sid = "uuid:sid-" + strconv.Itoa(d.subscribes)
+		} else {
+			d.renewals++
+		}
+
+		w.Header().Set("SID", d.sid)
+		w.Header().Set("TIMEOUT", "Second-1800")
+
+		select {
+		case d.subscribed <- struct{}{}:
+		default:
+		}
+	case methodUnsubscribe:
+	default:
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+	}
+}
+
+func (d *fakeEventDevice) notify(t *testing.T, seq uint32) {
+	t.Helper()
+
+	d.mu.Lock()
+	callback, sid := d.callback, d.sid
+	d.mu.Unlock()
+
+	request, err := http.NewRequest(methodNotify, callback, strings.NewReader(testEventBody))
+	if err != nil {
+		t.Fatalf("create notify: %v", err)
+	}
+
+	request.Header.Set("SID", sid)
+	request.Header.Set("SEQ", strconv.FormatUint(uint64(seq), 10))
+
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatalf("notify: %v", err)
+	}
+
+	_ = response.Body.Close()
+}
+
+func (d *fakeEventDevice) subscriptions() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.subscribes
+}
+
+func (d *fakeEventDevice) renewed() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.renewals
+}
+
+func TestSubscriptionRenewsAtHalfOfTimeout(t *testing.T) {
+	fakeClock := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	device := newFakeEventDevice()
+	defer device.server.Close()
+
+	subscriber := NewSubscriber(
+		WithCallbackAddress("127.0.0.1:0"),
+		WithCallbackHost("127.0.0.1"),
+		WithSubscriberClock(fakeClock),
+	)
+	defer func() {
+		_ = subscriber.Close()
+	}()
+
+	_, err := subscriber.Subscribe(
+		context.Background(),
+		device.server.URL+"/dmr",
+		"urn:schemas-upnp-org:service:RenderingControl",
+		func(Event) {},
+	)
+	if err != nil {
+		t.Fatalf("Subscribe() = %v", err)
+	}
+
+	<-device.subscribed
+
+	// The device grants 1800 seconds, so the renewal is due after 900.
+	for i := 1; i <= 2; i++ {
+		fakeClock.BlockUntil(1)
+		fakeClock.Advance(900 * time.Second)
+
+		select {
+		case <-device.subscribed:
+		case <-time.After(2 * time.Second):
+			t.Fatalf("subscription has not been renewed %d times", i)
+		}
+
+		if device.renewed() != i || device.subscriptions() != 1 {
+			t.Errorf("renewals = %d, subscriptions = %d, want %d and 1", device.renewed(), device.subscriptions(), i)
+		}
+	}
+}
+
+func TestSubscriptionResubscribesOnMissedEvents(t *testing.T) {
+	device := newFakeEventDevice()
+	defer device.server.Close()
+
+	subscriber := NewSubscriber(WithCallbackAddress("127.0.0.1:0"), WithCallbackHost("127.0.0.1"))
+	defer func() {
+		_ = subscriber.Close()
+	}()
+
+	events := make(chan Event, 8)
+	_, err := subscriber.Subscribe(
+		context.Background(),
+		device.server.URL+"/dmr",
+		"urn:schemas-upnp-org:service:RenderingControl",
+		func(event Event) {
+			events <- event
+		},
+	)
+	if err != nil {
+		t.Fatalf("Subscribe() = %v", err)
+	}
+
+	<-device.subscribed
+
+	for _, seq := range []uint32{0, 1, 2} {
+		device.notify(t, seq)
+	}
+
+	if device.subscriptions() != 1 {
+		t.Fatalf("subscriptions = %d, want 1 without missed events", device.subscriptions())
+	}
+
+	// The event numbered 3 is lost.
+	device.notify(t, 4)
+
+	select {
+	case <-device.subscribed:
+	case <-time.After(2 * time.Second):
+		t.Fatal("subscription has not been restarted after missed events")
+	}
+
+	if device.subscriptions() != 2 {
+		t.Errorf("subscriptions = %d, want 2", device.subscriptions())
+	}
+
+	// All events are still delivered.
+	for i := 0; i < 4; i++ {
+		select {
+		case event := <-events:
+			if event.Properties["Volume"] != "10" {
+				t.Errorf("event properties = %v", event.Properties)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("event %d has not been delivered", i)
+		}
+	}
+}
+
+func TestSubscriptionMissed(t *testing.T) {
+	tests := []struct {
+		name   string
+		events []Event
+		want   []bool
+	}{
+		{
+			name:   "in order",
+			events: []Event{{SID: "a", Seq: 0}, {SID: "a", Seq: 1}, {SID: "a", Seq: 2}},
+			want:   []bool{false, false, false},
+		},
+		{
+			name:   "gap",
+			events: []Event{{SID: "a", Seq: 0}, {SID: "a", Seq: 2}},
+			want:   []bool{false, true},
+		},
+		{
+			name:   "initial event lost",
+			events: []Event{{SID: "a", Seq: 1}},
+			want:   []bool{true},
+		},
+		{
+			name:   "wrap",
+			events: []Event{{SID: "a", Seq: 0}, {SID: "a", Seq: 4294967295}, {SID: "a", Seq: 1}},
+			want:   []bool{false, true, false},
+		},
+		{
+			name:   "new subscription",
+			events: []Event{{SID: "a", Seq: 0}, {SID: "a", Seq: 1}, {SID: "b", Seq: 0}},
+			want:   []bool{false, false, false},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			subscription := &Subscription{}
+
+			for i, event := range test.events {
+				missed := subscription.missed(event.SID, event.Seq)
+				if missed != test.want[i] {
+					t.Errorf("missed(%s, %d) = %v, want %v", event.SID, event.Seq, missed, test.want[i])
+				}
+			}
+		})
+	}
+}
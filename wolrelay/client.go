@@ -0,0 +1,148 @@
+package wolrelay
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/kpeu3i/go-tizen-tv/clock"
+	"github.com/kpeu3i/go-tizen-tv/tizenapi"
+)
+
+const defaultRequestTimeout = 5 * time.Second
+
+type ClientOption func(client *Client)
+
+func WithRequestTimeout(timeout time.Duration) ClientOption {
+	return func(client *Client) {
+		client.requestTimeout = timeout
+	}
+}
+
+// WithClientClock sets the clock which timestamps requests.
+func WithClientClock(c clock.Clock) ClientOption {
+	return func(client *Client) {
+		client.clock = c
+	}
+}
+
+// Client sends wake requests to a relay. The address is either
+// "http://host:port" or "udp://host:port". Over UDP requests are not
+// acknowledged.
+type Client struct {
+	address        string
+	secret         []byte
+	requestTimeout time.Duration
+	clock          clock.Clock
+}
+
+func NewClient(address string, secret []byte, options ...ClientOption) *Client {
+	client := &Client{
+		address:        address,
+		secret:         secret,
+		requestTimeout: defaultRequestTimeout,
+		clock:          clock.New(),
+	}
+
+	for _, option := range options {
+		option(client)
+	}
+
+	return client
+}
+
+func (c *Client) Address() string {
+	return c.address
+}
+
+func (c *Client) Relay(request tizenapi.WOLRequest) error {
+	return c.RelayContext(context.Background(), request)
+}
+
+// RelayContext asks the relay to broadcast magic packets for the MACs.
+func (c *Client) RelayContext(ctx context.Context, request tizenapi.WOLRequest) error {
+	u, err := url.Parse(c.address)
+	if err != nil {
+		return fmt.Errorf("parse relay address error: %s", err.Error())
+	}
+
+	m, err := newMessage(request.MACs, request.SecureOn, request.Port, c.clock.Now())
+	if err != nil {
+		return err
+	}
+
+	body, signature, err := encodeMessage(m, c.secret)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	switch u.Scheme {
+	case "udp":
+		return c.sendUDP(ctx, u.Host, body, signature)
+	case "http", "https":
+		return c.sendHTTP(ctx, u, body, signature)
+	default:
+		return fmt.Errorf("unsupported relay scheme: %q", u.Scheme)
+	}
+}
+
+func (c *Client) sendUDP(ctx context.Context, address string, body []byte, signature string) error {
+	dialer := net.Dialer{}
+
+	connection, err := dialer.DialContext(ctx, "udp", address)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = connection.Close()
+	}()
+
+	_, err = connection.Write(append([]byte(signature+"\n"), body...))
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *Client) sendHTTP(ctx context.Context, u *url.URL, body []byte, signature string) error {
+	if u.Path == "" || u.Path == "/" {
+		u.Path = wakePath
+	}
+
+	request, err := http.NewRequest(http.MethodPost, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	request = request.WithContext(ctx)
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(signatureHeader, signature)
+
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = response.Body.Close()
+	}()
+
+	if response.StatusCode != http.StatusNoContent && response.StatusCode != http.StatusOK {
+		message, _ := ioutil.ReadAll(io.LimitReader(response.Body, 512))
+
+		return fmt.Errorf("relay error: %s: %s", response.Status, bytes.TrimSpace(message))
+	}
+
+	return nil
+}
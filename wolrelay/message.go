@@ -0,0 +1,86 @@
+package wolrelay
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+const signatureHeader = "X-WoL-Relay-Signature"
+
+var (
+	ErrInvalidSignature = errors.New("invalid signature")
+	ErrExpiredRequest   = errors.New("request is expired")
+	ErrReplayedRequest  = errors.New("request is replayed")
+)
+
+// message is what a client sends to the relay. It is signed with HMAC-SHA256
+// of the shared secret. The timestamp and the nonce prevent replays.
+type message struct {
+	MACs      []string `json:"macs"`
+	SecureOn  string   `json:"secure_on,omitempty"`
+	Port      string   `json:"port,omitempty"`
+	Timestamp int64    `json:"timestamp"`
+	Nonce     string   `json:"nonce"`
+}
+
+func newMessage(macs []string, secureOn, port string, now time.Time) (message, error) {
+	nonce := make([]byte, 16)
+
+	_, err := rand.Read(nonce)
+	if err != nil {
+		return message{}, err
+	}
+
+	return message{
+		MACs:      macs,
+		SecureOn:  secureOn,
+		Port:      port,
+		Timestamp: now.Unix(),
+		Nonce:     hex.EncodeToString(nonce),
+	}, nil
+}
+
+func encodeMessage(m message, secret []byte) (body []byte, signature string, err error) {
+	body, err = json.Marshal(m)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return body, sign(body, secret), nil
+}
+
+func decodeMessage(body []byte, signature string, secret []byte) (message, error) {
+	expected, err := hex.DecodeString(signature)
+	if err != nil || !hmac.Equal(expected, mac(body, secret)) {
+		return message{}, ErrInvalidSignature
+	}
+
+	var m message
+
+	err = json.Unmarshal(body, &m)
+	if err != nil {
+		return message{}, err
+	}
+
+	if len(m.MACs) == 0 {
+		return message{}, errors.New("no MAC address")
+	}
+
+	return m, nil
+}
+
+func sign(body, secret []byte) string {
+	return hex.EncodeToString(mac(body, secret))
+}
+
+func mac(body, secret []byte) []byte {
+	h := hmac.New(sha256.New, secret)
+	_, _ = h.Write(body)
+
+	return h.Sum(nil)
+}
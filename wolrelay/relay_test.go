@@ -0,0 +1,196 @@
+package wolrelay
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/kpeu3i/go-tizen-tv/clock"
+	"github.com/kpeu3i/go-tizen-tv/tizenapi"
+)
+
+var (
+	testSecret = []byte("tizentest")
+	testNow    = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+)
+
+// testRelay is a relay on the loopback interface which records the requests
+// it accepts and the errors of those it rejects.
+type testRelay struct {
+	*Server
+	requests chan tizenapi.WOLRequest
+	errors   chan error
+}
+
+func startTestRelay(t *testing.T) *testRelay {
+	t.Helper()
+
+	relay := &testRelay{
+		requests: make(chan tizenapi.WOLRequest, 4),
+		errors:   make(chan error, 4),
+	}
+
+	relay.Server = NewServer(
+		testSecret,
+		WithHTTPListenAddress("127.0.0.1:0"),
+		WithUDPListenAddress("127.0.0.1:0"),
+		WithServerClock(clock.NewFake(testNow)),
+		WithWakeFunc(func(ctx context.Context, request tizenapi.WOLRequest) error {
+			relay.requests <- request
+
+			return nil
+		}),
+		WithErrorHandler(func(err error) {
+			relay.errors <- err
+		}),
+	)
+
+	err := relay.Start()
+	if err != nil {
+		t.Fatalf("start relay: %v", err)
+	}
+
+	return relay
+}
+
+func (r *testRelay) address(scheme string) string {
+	if scheme == "udp" {
+		return "udp://" + r.UDPAddr().String()
+	}
+
+	return "http://" + r.HTTPAddr().String()
+}
+
+// outcome waits for the relay to accept or reject a request. Over UDP the
+// client does not learn it, so the relay is asked.
+func (r *testRelay) outcome(t *testing.T) error {
+	t.Helper()
+
+	select {
+	case <-r.requests:
+		return nil
+	case err := <-r.errors:
+		return err
+	case <-time.After(2 * time.Second):
+		t.Fatal("relay has not handled the request")
+
+		return nil
+	}
+}
+
+func TestRelay(t *testing.T) {
+	tests := []struct {
+		name    string
+		secret  []byte
+		skew    time.Duration
+		wantErr error
+	}{
+		{name: "accepted", secret: testSecret},
+		{name: "small clock skew", secret: testSecret, skew: -20 * time.Second},
+		{name: "invalid signature", secret: []byte("wrong"), wantErr: ErrInvalidSignature},
+		{name: "clock behind", secret: testSecret, skew: -time.Minute, wantErr: ErrExpiredRequest},
+		{name: "clock ahead", secret: testSecret, skew: time.Minute, wantErr: ErrExpiredRequest},
+	}
+
+	for _, scheme := range []string{"http", "udp"} {
+		for _, test := range tests {
+			t.Run(scheme+" "+test.name, func(t *testing.T) {
+				relay := startTestRelay(t)
+				defer func() {
+					_ = relay.Close()
+				}()
+
+				client := NewClient(
+					relay.address(scheme),
+					test.secret,
+					WithClientClock(clock.NewFake(testNow.Add(test.skew))),
+				)
+
+				err := client.Relay(tizenapi.WOLRequest{MACs: []string{"02:00:00:00:00:01"}})
+				if scheme == "http" && (err == nil) != (test.wantErr == nil) {
+					t.Errorf("Relay() = %v, want %v", err, test.wantErr)
+				}
+
+				if scheme == "udp" && err != nil {
+					t.Fatalf("Relay() = %v", err)
+				}
+
+				err = relay.outcome(t)
+				if err != test.wantErr {
+					t.Errorf("relay outcome = %v, want %v", err, test.wantErr)
+				}
+			})
+		}
+	}
+}
+
+func TestRelayRejectsReplayedRequests(t *testing.T) {
+	relay := startTestRelay(t)
+	defer func() {
+		_ = relay.Close()
+	}()
+
+	m, err := newMessage([]string{"02:00:00:00:00:01"}, "", "", testNow)
+	if err != nil {
+		t.Fatalf("newMessage() = %v", err)
+	}
+
+	body, signature, err := encodeMessage(m, testSecret)
+	if err != nil {
+		t.Fatalf("encodeMessage() = %v", err)
+	}
+
+	send := map[string]func() error{
+		"http": func() error {
+			request, err := http.NewRequest(http.MethodPost, relay.address("http")+wakePath, bytes.NewReader(body))
+			if err != nil {
+				return err
+			}
+
+			request.Header.Set(signatureHeader, signature)
+
+			response, err := http.DefaultClient.Do(request)
+			if err != nil {
+				return err
+			}
+
+			return response.Body.Close()
+		},
+		"udp": func() error {
+			connection, err := net.Dial("udp", relay.UDPAddr().String())
+			if err != nil {
+				return err
+			}
+
+			defer func() {
+				_ = connection.Close()
+			}()
+
+			_, err = connection.Write(append([]byte(signature+"\n"), body...))
+
+			return err
+		},
+	}
+
+	// The first request passes, the same one sent again over either path is a
+	// replay.
+	for i, scheme := range []string{"http", "http", "udp"} {
+		err := send[scheme]()
+		if err != nil {
+			t.Fatalf("send over %s: %v", scheme, err)
+		}
+
+		want := ErrReplayedRequest
+		if i == 0 {
+			want = nil
+		}
+
+		err = relay.outcome(t)
+		if err != want {
+			t.Errorf("request %d over %s: outcome = %v, want %v", i+1, scheme, err, want)
+		}
+	}
+}
@@ -0,0 +1,354 @@
+package wolrelay
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/kpeu3i/go-tizen-tv/clock"
+	"github.com/kpeu3i/go-tizen-tv/tizenapi"
+)
+
+const (
+	defaultHTTPListenAddress = ":9009"
+	defaultMaxClockSkew      = 30 * time.Second
+	defaultShutdownTimeout   = 5 * time.Second
+	wakePath                 = "/wake"
+	maxMessageSize           = 4096
+)
+
+// WakeFunc broadcasts magic packets. It is called by the server for every
+// authenticated request.
+type WakeFunc func(ctx context.Context, request tizenapi.WOLRequest) error
+
+type ServerOption func(server *Server)
+
+// WithHTTPListenAddress sets the address of the HTTP listener. An empty address
+// disables it.
+func WithHTTPListenAddress(address string) ServerOption {
+	return func(server *Server) {
+		server.httpListenAddress = address
+	}
+}
+
+// WithUDPListenAddress sets the address of the UDP listener. It is disabled by
+// default.
+func WithUDPListenAddress(address string) ServerOption {
+	return func(server *Server) {
+		server.udpListenAddress = address
+	}
+}
+
+// WithMaxClockSkew sets how old a request may be. Clocks of the client and the
+// relay must not differ more than that.
+func WithMaxClockSkew(skew time.Duration) ServerOption {
+	return func(server *Server) {
+		server.maxClockSkew = skew
+	}
+}
+
+// WithUDPAPIOptions sets the options of the UDP clients which broadcast
+// packets, e.g. the subnet.
+func WithUDPAPIOptions(options ...tizenapi.UDPAPIOption) ServerOption {
+	return func(server *Server) {
+		server.udpOptions = options
+	}
+}
+
+func WithWakeFunc(wake WakeFunc) ServerOption {
+	return func(server *Server) {
+		server.wake = wake
+	}
+}
+
+// WithServerClock sets the clock which requests are checked against.
+func WithServerClock(c clock.Clock) ServerOption {
+	return func(server *Server) {
+		server.clock = c
+	}
+}
+
+func WithErrorHandler(handler func(err error)) ServerOption {
+	return func(server *Server) {
+		server.errorHandler = handler
+	}
+}
+
+// Server is a Wake-on-LAN relay. Deployed on the network of the TVs, it
+// rebroadcasts magic packets for authenticated requests from other networks.
+type Server struct {
+	secret            []byte
+	httpListenAddress string
+	udpListenAddress  string
+	maxClockSkew      time.Duration
+	udpOptions        []tizenapi.UDPAPIOption
+	wake              WakeFunc
+	errorHandler      func(err error)
+	clock             clock.Clock
+	mu                sync.Mutex
+	httpListener      net.Listener
+	httpServer        *http.Server
+	udpConnection     net.PacketConn
+	nonces            map[string]time.Time
+}
+
+func NewServer(secret []byte, options ...ServerOption) *Server {
+	server := &Server{
+		secret:            secret,
+		httpListenAddress: defaultHTTPListenAddress,
+		maxClockSkew:      defaultMaxClockSkew,
+		nonces:            map[string]time.Time{},
+		clock:             clock.New(),
+	}
+
+	for _, option := range options {
+		option(server)
+	}
+
+	if server.wake == nil {
+		server.wake = server.broadcast
+	}
+
+	return server
+}
+
+// Start starts the listeners. Calling Start on a started server does nothing.
+func (s *Server) Start() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.secret) == 0 {
+		return errors.New("relay secret is empty")
+	}
+
+	if s.httpListener != nil || s.udpConnection != nil {
+		return nil
+	}
+
+	if s.httpListenAddress == "" && s.udpListenAddress == "" {
+		return errors.New("no listen address")
+	}
+
+	if s.httpListenAddress != "" {
+		listener, err := net.Listen("tcp", s.httpListenAddress)
+		if err != nil {
+			return err
+		}
+
+		s.httpListener = listener
+		s.httpServer = &http.Server{Handler: http.HandlerFunc(s.handleHTTP)}
+
+		go func(server *http.Server) {
+			_ = server.Serve(listener)
+		}(s.httpServer)
+	}
+
+	if s.udpListenAddress != "" {
+		connection, err := net.ListenPacket("udp", s.udpListenAddress)
+		if err != nil {
+			if s.httpServer != nil {
+				_ = s.httpServer.Close()
+				s.httpListener = nil
+				s.httpServer = nil
+			}
+
+			return err
+		}
+
+		s.udpConnection = connection
+
+		go s.serveUDP(connection)
+	}
+
+	return nil
+}
+
+// HTTPAddr returns the address of the HTTP listener or nil.
+func (s *Server) HTTPAddr() net.Addr {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.httpListener == nil {
+		return nil
+	}
+
+	return s.httpListener.Addr()
+}
+
+// UDPAddr returns the address of the UDP listener or nil.
+func (s *Server) UDPAddr() net.Addr {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.udpConnection == nil {
+		return nil
+	}
+
+	return s.udpConnection.LocalAddr()
+}
+
+func (s *Server) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultShutdownTimeout)
+	defer cancel()
+
+	return s.CloseContext(ctx)
+}
+
+func (s *Server) CloseContext(ctx context.Context) error {
+	s.mu.Lock()
+	httpServer := s.httpServer
+	udpConnection := s.udpConnection
+	s.httpListener = nil
+	s.httpServer = nil
+	s.udpConnection = nil
+	s.mu.Unlock()
+
+	var err error
+	if udpConnection != nil {
+		err = udpConnection.Close()
+	}
+
+	if httpServer != nil {
+		shutdownErr := httpServer.Shutdown(ctx)
+		if shutdownErr != nil {
+			err = shutdownErr
+		}
+	}
+
+	return err
+}
+
+func (s *Server) handleHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != wakePath {
+		http.NotFound(w, r)
+
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+
+		return
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, maxMessageSize))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	err = s.handle(r.Context(), body, r.Header.Get(signatureHeader))
+	if err != nil {
+		s.handleError(err)
+	}
+
+	switch err {
+	case nil:
+		w.WriteHeader(http.StatusNoContent)
+	case ErrInvalidSignature, ErrExpiredRequest, ErrReplayedRequest:
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+	default:
+		http.Error(w, err.Error(), http.StatusBadGateway)
+	}
+}
+
+func (s *Server) serveUDP(connection net.PacketConn) {
+	buffer := make([]byte, maxMessageSize)
+
+	for {
+		n, _, err := connection.ReadFrom(buffer)
+		if err != nil {
+			if ne, ok := err.(net.Error); ok && ne.Temporary() {
+				continue
+			}
+
+			return
+		}
+
+		datagram := buffer[:n]
+
+		i := bytes.IndexByte(datagram, '\n')
+		if i < 0 {
+			s.handleError(ErrInvalidSignature)
+
+			continue
+		}
+
+		body := append([]byte(nil), datagram[i+1:]...)
+
+		err = s.handle(context.Background(), body, string(datagram[:i]))
+		if err != nil {
+			s.handleError(err)
+		}
+	}
+}
+
+func (s *Server) handle(ctx context.Context, body []byte, signature string) error {
+	m, err := decodeMessage(body, signature, s.secret)
+	if err != nil {
+		return err
+	}
+
+	err = s.checkReplay(m, s.clock.Now())
+	if err != nil {
+		return err
+	}
+
+	return s.wake(ctx, tizenapi.WOLRequest{
+		MACs:     m.MACs,
+		SecureOn: m.SecureOn,
+		Port:     m.Port,
+	})
+}
+
+func (s *Server) checkReplay(m message, now time.Time) error {
+	timestamp := time.Unix(m.Timestamp, 0)
+	if timestamp.Before(now.Add(-s.maxClockSkew)) || timestamp.After(now.Add(s.maxClockSkew)) {
+		return ErrExpiredRequest
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for nonce, expires := range s.nonces {
+		if now.After(expires) {
+			delete(s.nonces, nonce)
+		}
+	}
+
+	if _, ok := s.nonces[m.Nonce]; ok {
+		return ErrReplayedRequest
+	}
+
+	s.nonces[m.Nonce] = timestamp.Add(s.maxClockSkew)
+
+	return nil
+}
+
+func (s *Server) broadcast(ctx context.Context, request tizenapi.WOLRequest) error {
+	options := append([]tizenapi.UDPAPIOption(nil), s.udpOptions...)
+	options = append(options,
+		tizenapi.WithUDPMACs(request.MACs[1:]...),
+		tizenapi.WithUDPSecureOn(request.SecureOn),
+	)
+
+	if request.Port != "" {
+		options = append(options, tizenapi.WithUDPPort(request.Port))
+	}
+
+	return tizenapi.NewUDPAPIClient(request.MACs[0], options...).WakeUpContext(ctx)
+}
+
+func (s *Server) handleError(err error) {
+	if s.errorHandler != nil {
+		s.errorHandler(err)
+	}
+}